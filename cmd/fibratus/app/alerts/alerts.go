@@ -0,0 +1,241 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/rabbitstack/fibratus/internal/bootstrap"
+	"github.com/rabbitstack/fibratus/pkg/alertsender/store"
+	"github.com/rabbitstack/fibratus/pkg/config"
+	errs "github.com/rabbitstack/fibratus/pkg/errors"
+	"github.com/rabbitstack/fibratus/pkg/util/rest"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:   "alerts",
+	Short: "List, show, acknowledge, or export alerts recorded in the local alert store",
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List alerts",
+	RunE:  list,
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show the alert along with the events that triggered it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  show,
+}
+
+var ackCmd = &cobra.Command{
+	Use:   "ack [id...]",
+	Short: "Acknowledge alerts",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  ack,
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export alerts as JSON lines",
+	RunE:  export,
+}
+
+var cfg = config.NewWithOpts(config.WithAlerts())
+
+var (
+	rule       string
+	severities []string
+	since      string
+	until      string
+	state      string
+	limit      int
+	output     string
+)
+
+func init() {
+	cfg.MustViperize(Command)
+
+	for _, cmd := range []*cobra.Command{listCmd, exportCmd} {
+		cmd.PersistentFlags().StringVarP(&rule, "rule", "r", "", "Filters alerts by rule identifier or rule name")
+		cmd.PersistentFlags().StringSliceVarP(&severities, "severity", "s", nil, "Filters alerts by comma-separated list of severities")
+		cmd.PersistentFlags().StringVar(&since, "since", "", "Filters alerts recorded after the time given in RFC3339 format or as the duration relative to the current time, e.g. 24h")
+		cmd.PersistentFlags().StringVar(&until, "until", "", "Filters alerts recorded before the time given in RFC3339 format or as the duration relative to the current time, e.g. 1h")
+		cmd.PersistentFlags().StringVar(&state, "state", "", "Filters alerts by acknowledgement state. Possible values are acked and unacked")
+	}
+	listCmd.PersistentFlags().IntVarP(&limit, "limit", "n", 50, "Specifies the maximum number of listed alerts. Zero means no limit")
+	exportCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "Specifies the file where alerts are exported. Defaults to the standard output")
+
+	Command.AddCommand(listCmd)
+	Command.AddCommand(showCmd)
+	Command.AddCommand(ackCmd)
+	Command.AddCommand(exportCmd)
+}
+
+func list(cmd *cobra.Command, args []string) error {
+	params, err := queryParams()
+	if err != nil {
+		return err
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	body, err := request(rest.Get, "alerts?"+params.Encode())
+	if err != nil {
+		return err
+	}
+	var records []store.Record
+	if err := json.Unmarshal(body, &records); err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{"ID", "Time", "Rule", "Severity", "Acked"})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Name: "Rule", WidthMax: 80},
+	})
+	for _, r := range records {
+		t.AppendRow(table.Row{r.ID, r.Timestamp.Local().Format(time.DateTime), r.Rule, r.Severity, r.Acked})
+	}
+	t.AppendFooter(table.Row{"TOTAL", len(records), "", "", ""})
+	t.Render()
+
+	return nil
+}
+
+func show(cmd *cobra.Command, args []string) error {
+	body, err := request(rest.Get, "alerts/"+url.PathEscape(args[0]))
+	if err != nil {
+		return err
+	}
+	var r store.Record
+	if err := json.Unmarshal(body, &r); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func ack(cmd *cobra.Command, args []string) error {
+	for _, id := range args {
+		if _, err := request(rest.Post, "alerts/"+url.PathEscape(id)+"/ack"); err != nil {
+			return fmt.Errorf("unable to acknowledge %s alert: %v", id, err)
+		}
+		fmt.Printf("%s alert acknowledged\n", id)
+	}
+	return nil
+}
+
+func export(cmd *cobra.Command, args []string) error {
+	params, err := queryParams()
+	if err != nil {
+		return err
+	}
+	body, err := request(rest.Get, "alerts/export?"+params.Encode())
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	return os.WriteFile(output, body, 0o600)
+}
+
+// request sends the request to the alerts API endpoint.
+func request(fn func(...rest.Option) ([]byte, error), uri string) ([]byte, error) {
+	if err := bootstrap.InitConfigAndLogger(cfg); err != nil {
+		return nil, err
+	}
+	c := cfg.API
+	body, err := fn(rest.WithTransport(c.Transport), rest.WithURI(uri), rest.WithStatusError())
+	var serr *rest.StatusError
+	if errors.As(err, &serr) {
+		if serr.Code == http.StatusNotFound && serr.Body != store.ErrNotFound.Error() {
+			return nil, errors.New("alert store is not enabled")
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, errs.ErrHTTPServerUnavailable(c.Transport, err)
+	}
+	return body, nil
+}
+
+// queryParams builds alert query parameters from command flags.
+func queryParams() (url.Values, error) {
+	params := url.Values{}
+	if rule != "" {
+		params.Set("rule", rule)
+	}
+	if len(severities) > 0 {
+		params.Set("severity", strings.Join(severities, ","))
+	}
+	if since != "" {
+		t, err := parseTime(since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since flag: %v", err)
+		}
+		params.Set("since", t.Format(time.RFC3339))
+	}
+	if until != "" {
+		t, err := parseTime(until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid until flag: %v", err)
+		}
+		params.Set("until", t.Format(time.RFC3339))
+	}
+	switch state {
+	case "":
+	case "acked":
+		params.Set("acked", "true")
+	case "unacked":
+		params.Set("acked", "false")
+	default:
+		return nil, fmt.Errorf("invalid state flag: %s. Possible values are acked and unacked", state)
+	}
+	return params, nil
+}
+
+// parseTime parses the time in RFC3339 format or
+// the duration relative to the current time.
+func parseTime(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quarantine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/rabbitstack/fibratus/internal/bootstrap"
+	"github.com/rabbitstack/fibratus/pkg/config"
+	"github.com/rabbitstack/fibratus/pkg/quarantine"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:   "quarantine",
+	Short: "List, restore, or purge quarantined files",
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List quarantined files",
+	RunE:  list,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [id]",
+	Short: "Restore the quarantined file to its original location",
+	Args:  cobra.ExactArgs(1),
+	RunE:  restore,
+}
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge [id...]",
+	Short: "Permanently remove quarantined files",
+	RunE:  purge,
+}
+
+var cfg = config.NewWithOpts(config.WithQuarantine())
+
+var (
+	to  string
+	all bool
+)
+
+func init() {
+	cfg.MustViperize(Command)
+
+	restoreCmd.PersistentFlags().StringVar(&to, "to", "", "Specifies the path where the file is restored. Defaults to the original file location")
+	purgeCmd.PersistentFlags().BoolVar(&all, "all", false, "Removes all quarantined files")
+
+	Command.AddCommand(listCmd)
+	Command.AddCommand(restoreCmd)
+	Command.AddCommand(purgeCmd)
+}
+
+func list(cmd *cobra.Command, args []string) error {
+	vault, err := open()
+	if err != nil {
+		return err
+	}
+	entries, err := vault.List()
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{"ID", "Time", "Path", "Rule", "SHA256"})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Name: "Path", WidthMax: 60},
+		{Name: "Rule", WidthMax: 60},
+	})
+	for _, e := range entries {
+		t.AppendRow(table.Row{e.ID, e.Timestamp.Local().Format(time.DateTime), e.Path, e.Rule, e.SHA256})
+	}
+	t.AppendFooter(table.Row{"TOTAL", len(entries), "", "", ""})
+	t.Render()
+
+	return nil
+}
+
+func restore(cmd *cobra.Command, args []string) error {
+	vault, err := open()
+	if err != nil {
+		return err
+	}
+	entry, err := vault.Restore(args[0], to)
+	if err != nil {
+		return fmt.Errorf("unable to restore %s quarantined file: %v", args[0], err)
+	}
+	path := to
+	if path == "" {
+		path = entry.Path
+	}
+	fmt.Printf("%s file restored to %s\n", entry.ID, path)
+	return nil
+}
+
+func purge(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !all {
+		return errors.New("specify quarantined file identifiers or the --all flag")
+	}
+	vault, err := open()
+	if err != nil {
+		return err
+	}
+	ids := args
+	if all {
+		entries, err := vault.List()
+		if err != nil {
+			return err
+		}
+		ids = make([]string, 0, len(entries))
+		for _, e := range entries {
+			ids = append(ids, e.ID)
+		}
+	}
+	for _, id := range ids {
+		if err := vault.Purge(id); err != nil {
+			return fmt.Errorf("unable to purge %s quarantined file: %v", id, err)
+		}
+		fmt.Printf("%s file purged\n", id)
+	}
+	return nil
+}
+
+// open opens the quarantine vault from the configured path.
+func open() (*quarantine.Vault, error) {
+	if err := bootstrap.InitConfigAndLogger(cfg); err != nil {
+		return nil, err
+	}
+	return quarantine.Open(cfg.Quarantine)
+}
@@ -20,9 +20,11 @@ package app
 
 import (
 	"errors"
+	"github.com/rabbitstack/fibratus/cmd/fibratus/app/alerts"
 	"github.com/rabbitstack/fibratus/cmd/fibratus/app/capture"
 	"github.com/rabbitstack/fibratus/cmd/fibratus/app/config"
 	"github.com/rabbitstack/fibratus/cmd/fibratus/app/list"
+	"github.com/rabbitstack/fibratus/cmd/fibratus/app/quarantine"
 	"github.com/rabbitstack/fibratus/cmd/fibratus/app/replay"
 	"github.com/rabbitstack/fibratus/cmd/fibratus/app/rules"
 	"github.com/rabbitstack/fibratus/cmd/fibratus/app/service"
@@ -62,6 +64,8 @@ func init() {
 	RootCmd.AddCommand(config.Command)
 	RootCmd.AddCommand(list.Command)
 	RootCmd.AddCommand(rules.Command)
+	RootCmd.AddCommand(alerts.Command)
+	RootCmd.AddCommand(quarantine.Command)
 	RootCmd.AddCommand(runCmd)
 	RootCmd.AddCommand(docsCmd)
 	RootCmd.AddCommand(versionCmd)
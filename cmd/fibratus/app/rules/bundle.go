@@ -0,0 +1,210 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rules
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/enescakir/emoji"
+	"github.com/rabbitstack/fibratus/internal/bootstrap"
+	"github.com/rabbitstack/fibratus/pkg/rules/bundle"
+	"github.com/spf13/cobra"
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create, sign, and verify rule bundles",
+}
+
+var bundleKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate the Ed25519 key pair for signing rule bundles",
+	RunE:  keygen,
+}
+
+var bundleSignCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Pack rules and macros into a signed bundle",
+	RunE:  sign,
+}
+
+var bundleVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the signature and integrity of the rule bundle file or URL",
+	RunE:  verify,
+}
+
+var (
+	privateKeyPath string
+	keygenOutput   string
+	bundleOutput   string
+	publicKeys     []string
+)
+
+func init() {
+	bundleKeygenCmd.PersistentFlags().StringVarP(&keygenOutput, "output", "o", "fibratus-rules.key", "Path of the file where the PEM-encoded private key is written")
+	bundleCmd.AddCommand(bundleKeygenCmd)
+
+	bundleSignCmd.PersistentFlags().StringVarP(&privateKeyPath, "private-key", "k", "", "Path of the PEM-encoded private key used to sign the bundle")
+	bundleSignCmd.PersistentFlags().StringVarP(&bundleOutput, "output", "o", "rules.tar.gz", "Path of the signed bundle file")
+	bundleCmd.AddCommand(bundleSignCmd)
+
+	bundleVerifyCmd.PersistentFlags().StringSliceVarP(&publicKeys, "public-key", "p", []string{}, "Base64-encoded public keys used to verify the bundle. Overrides the keys given in filters.rules.public-keys")
+	bundleCmd.AddCommand(bundleVerifyCmd)
+
+	Command.AddCommand(bundleCmd)
+}
+
+func keygen(cmd *cobra.Command, args []string) error {
+	pub, priv, err := bundle.GenerateKey()
+	if err != nil {
+		return err
+	}
+	b, err := bundle.EncodePrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keygenOutput, b, 0600); err != nil {
+		return fmt.Errorf("unable to write private key: %v", err)
+	}
+	emo("%v Private key written to %s. Keep it in a safe place\n", emoji.Key, keygenOutput)
+	emo("%v Public key (%s): %s\n", emoji.Locked, bundle.Fingerprint(pub), bundle.EncodePublicKey(pub))
+	return nil
+}
+
+func sign(cmd *cobra.Command, args []string) error {
+	if err := bootstrap.InitConfigAndLogger(cfg); err != nil {
+		return err
+	}
+	if privateKeyPath == "" {
+		return fmt.Errorf("private key is required")
+	}
+	b, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return fmt.Errorf("unable to read private key: %v", err)
+	}
+	priv, err := bundle.DecodePrivateKey(b)
+	if err != nil {
+		return err
+	}
+
+	builder := bundle.NewBuilder()
+	n, err := addBundleFiles(cfg.Filters.Rules.FromPaths, builder.AddRule, emoji.Package)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%v no rules found in %s", emoji.DisappointedFace, strings.Join(cfg.Filters.Rules.FromPaths, ","))
+	}
+	if _, err := addBundleFiles(cfg.Filters.Macros.FromPaths, builder.AddMacro, emoji.Hook); err != nil {
+		return err
+	}
+
+	out, err := builder.Sign(priv)
+	if err != nil {
+		return fmt.Errorf("%v %v", emoji.DisappointedFace, err)
+	}
+	if err := os.WriteFile(bundleOutput, out, 0644); err != nil {
+		return fmt.Errorf("unable to write rule bundle: %v", err)
+	}
+	emo("%v Signed bundle with %d rule file(s) written to %s\n", emoji.CheckMark, n, bundleOutput)
+	return nil
+}
+
+func verify(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("rule bundle file or URL is required")
+	}
+	if err := bootstrap.InitConfigAndLogger(cfg); err != nil {
+		return err
+	}
+	if len(publicKeys) == 0 {
+		publicKeys = cfg.Filters.Rules.PublicKeys
+	}
+	keys, err := bundle.ParsePublicKeys(publicKeys)
+	if err != nil {
+		return err
+	}
+
+	b, err := readBundle(args[0])
+	if err != nil {
+		return err
+	}
+	bun, err := bundle.Open(b, keys)
+	if err != nil {
+		return fmt.Errorf("%v %v", emoji.DisappointedFace, err)
+	}
+
+	for _, m := range bun.Macros {
+		emo("%v %s\n", emoji.Hook, m.Name)
+	}
+	for _, r := range bun.Rules {
+		emo("%v %s\n", emoji.Package, r.Name)
+	}
+	emo("%v Bundle signed by key %s on %s is valid\n", emoji.CheckMark, bun.KeyID, bun.Manifest.Created)
+	return nil
+}
+
+// addBundleFiles adds all rule or macro files matching
+// the glob patterns to the bundle.
+func addBundleFiles(patterns []string, add func(string, []byte), e emoji.Emoji) (int, error) {
+	var n int
+	for _, p := range patterns {
+		paths, err := filepath.Glob(p)
+		if err != nil {
+			return n, err
+		}
+		for _, path := range paths {
+			if filepath.Ext(path) != ".yml" && filepath.Ext(path) != ".yaml" {
+				continue
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return n, err
+			}
+			emo("%v Adding %s\n", e, path)
+			// the builder expects slash-separated names,
+			// so strip the platform-specific directory here
+			add(filepath.Base(path), b)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func readBundle(resource string) ([]byte, error) {
+	if !strings.HasPrefix(resource, "http://") && !strings.HasPrefix(resource, "https://") {
+		return os.ReadFile(resource)
+	}
+	//nolint:noctx
+	resp, err := http.Get(resource)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch rule bundle from %q: %v", resource, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got non-ok status code for %q: %s", resource, http.StatusText(resp.StatusCode))
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -32,6 +32,7 @@ import (
 var ruleTemplate = `name: {{ .Name }}
 id: {{ .ID }}
 version: {{ .Version }}
+status: experimental
 description: |
   Provide a meaningful description that clearly conveys the detection objectives of this rule.
   Good descriptions usually start with "Identifies ..." or "Detects ...".
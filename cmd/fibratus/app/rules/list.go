@@ -23,6 +23,8 @@ import (
 	"github.com/enescakir/emoji"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/rabbitstack/fibratus/internal/bootstrap"
+	"github.com/rabbitstack/fibratus/pkg/config"
+	"gopkg.in/yaml.v3"
 	"os"
 	"strings"
 )
@@ -39,6 +41,10 @@ func listRules() error {
 		return fmt.Errorf("%v no rules found in %s", emoji.DisappointedFace, strings.Join(cfg.Filters.Rules.FromPaths, ","))
 	}
 
+	if effective {
+		return listEffectiveRules(filters)
+	}
+
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
 	t.SetStyle(table.StyleLight)
@@ -73,12 +79,14 @@ func listRules() error {
 		t.AppendFooter(table.Row{"TOTAL", tot})
 	} else {
 		// show all rules
-		t.AppendHeader(table.Row{"#", "Rule", "Technique", "Tactic"})
+		t.AppendHeader(table.Row{"#", "Rule", "Technique", "Tactic", "Status", "State"})
 		t.SetColumnConfigs([]table.ColumnConfig{
 			{Name: "#", WidthMax: 5},
 			{Name: "Rule"},
 			{Name: "Technique"},
 			{Name: "Tactic", WidthMax: 50},
+			{Name: "Status"},
+			{Name: "State", WidthMax: 60},
 		})
 
 		n := 0
@@ -94,7 +102,8 @@ func listRules() error {
 			if _, ok := tactics[tec]; !ok {
 				techniques[tec] = 1
 			}
-			t.AppendRow(table.Row{n + 1, f.Name, tec, tac})
+			state := cfg.Filters.RuleState(f)
+			t.AppendRow(table.Row{n + 1, f.Name, tec, tac, f.EffectiveStatus(), ruleState(state)})
 			n++
 		}
 
@@ -110,10 +119,61 @@ func listRules() error {
 			totTec += n
 		}
 
-		t.AppendFooter(table.Row{"TOTAL", n, totTec, totTat})
+		t.AppendFooter(table.Row{"TOTAL", n, totTec, totTat, "", ""})
 	}
 
 	t.Render()
 
 	return nil
 }
+
+// ruleState renders the rule state along with the reason
+// why the rule is enabled or disabled.
+func ruleState(state config.RuleState) string {
+	if state.Enabled {
+		return fmt.Sprintf("%v enabled (%s)", emoji.CheckMarkButton, state.Reason)
+	}
+	return fmt.Sprintf("%v disabled (%s)", emoji.CrossMark, state.Reason)
+}
+
+// effectiveRule is the effective rule definition
+// resulting from applying overlays to the rule.
+type effectiveRule struct {
+	ID        string                `yaml:"id"`
+	Name      string                `yaml:"name"`
+	Version   string                `yaml:"version"`
+	Status    string                `yaml:"status"`
+	Severity  string                `yaml:"severity,omitempty"`
+	Labels    map[string]string     `yaml:"labels,omitempty"`
+	Condition string                `yaml:"condition"`
+	Action    []config.FilterAction `yaml:"action,omitempty"`
+	Overlays  []string              `yaml:"overlays,omitempty"`
+	State     string                `yaml:"state"`
+}
+
+func listEffectiveRules(filters []*config.FilterConfig) error {
+	for i, f := range filters {
+		state := cfg.Filters.RuleState(f)
+		rule := effectiveRule{
+			ID:        f.ID,
+			Name:      f.Name,
+			Version:   f.Version,
+			Status:    f.EffectiveStatus(),
+			Severity:  f.Severity,
+			Labels:    f.Labels,
+			Condition: f.Condition,
+			Action:    f.Action,
+			Overlays:  f.Overlays,
+			State:     ruleState(state),
+		}
+		b, err := yaml.Marshal(&rule)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Println("---")
+		}
+		fmt.Print(string(b))
+	}
+	return nil
+}
@@ -53,6 +53,7 @@ var cfg = config.NewWithOpts(config.WithValidate(), config.WithList())
 
 var (
 	summarized bool
+	effective  bool
 	tacticID   string
 )
 
@@ -62,6 +63,7 @@ func init() {
 	Command.AddCommand(validateCmd)
 
 	listCmd.PersistentFlags().BoolVarP(&summarized, "summary", "s", false, "Show rules summary by MITRE tactics and techniques")
+	listCmd.PersistentFlags().BoolVarP(&effective, "effective", "e", false, "Show effective rule definitions after applying overlays")
 	Command.AddCommand(listCmd)
 
 	createCmd.PersistentFlags().StringVarP(&tacticID, "tactic-id", "t", "", "Specifies the MITRE tactic identifier for the rule (e.g. TA0001)")
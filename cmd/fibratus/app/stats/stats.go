@@ -49,7 +49,18 @@ type Stats struct {
 	AggregatorFlushesCount              int            `json:"aggregator.flushes.count"`
 	AggregatorEventErrors               int            `json:"aggregator.event.errors"`
 	AggregatorTransformerErrors         map[string]int `json:"aggregator.transformer.errors"`
+	AggregatorOutputDroppedBatches      map[string]int `json:"aggregator.output.dropped.batches"`
+	AggregatorOutputFilteredEvents      map[string]int `json:"aggregator.output.filtered.events"`
+	AggregatorBufferBacklogBatches      map[string]int `json:"aggregator.buffer.backlog.batches"`
+	AggregatorBufferBacklogBytes        map[string]int `json:"aggregator.buffer.backlog.bytes"`
+	AggregatorBufferDroppedBatches      map[string]int `json:"aggregator.buffer.dropped.batches"`
+	AggregatorBufferReplayedBatches     map[string]int `json:"aggregator.buffer.replayed.batches"`
+	AggregatorBufferRetries             map[string]int `json:"aggregator.buffer.retries"`
 	AggregatorWorkerClientPublishErrors int            `json:"aggregator.worker.client.publish.errors"`
+	AlertsenderSpoolDepth               map[string]int `json:"alertsender.spool.depth"`
+	AlertsenderSpoolDropped             map[string]int `json:"alertsender.spool.dropped"`
+	AlertsenderSpoolReplayed            map[string]int `json:"alertsender.spool.replayed"`
+	AlertsenderSpoolRetries             map[string]int `json:"alertsender.spool.retries"`
 	FilamentDictErrors                  int            `json:"filament.dict.errors"`
 	FilamentEventBatchFlushes           int            `json:"filament.event.batch.flushes"`
 	FilamentEventErrors                 map[string]int `json:"filament.event.errors"`
@@ -93,7 +104,23 @@ type Stats struct {
 	OutputAMQPConnectionFailures        int            `json:"output.amqp.connection.failures"`
 	OutputAMQPPublishErrors             int            `json:"output.amqp.publish.errors"`
 	OutputConsoleErrors                 int            `json:"output.console.errors"`
+	OutputFileCompressionErrors         int            `json:"output.file.compression.errors"`
+	OutputFileRemovedBackups            int            `json:"output.file.removed.backups"`
+	OutputFileRotations                 int            `json:"output.file.rotations"`
+	OutputFileWriteErrors               int            `json:"output.file.write.errors"`
+	OutputFileWrittenEvents             int            `json:"output.file.written.events"`
+	OutputKafkaPublishErrors            int            `json:"output.kafka.publish.errors"`
+	OutputKafkaPublishMessages          int            `json:"output.kafka.publish.messages"`
 	OutputNullBlackholeEvents           int            `json:"output.null.blackhole.events"`
+	OutputOTLPPublishErrors             int            `json:"output.otlp.publish.errors"`
+	OutputOTLPPublishRecords            int            `json:"output.otlp.publish.records"`
+	OutputOTLPRejectedRecords           int            `json:"output.otlp.rejected.records"`
+	OutputOTLPRetries                   int            `json:"output.otlp.retries"`
+	OutputSplunkAckTimeouts             int            `json:"output.splunk.ack.timeouts"`
+	OutputSplunkOversizedEvents         int            `json:"output.splunk.oversized.events"`
+	OutputSplunkPublishErrors           int            `json:"output.splunk.publish.errors"`
+	OutputSplunkPublishEvents           int            `json:"output.splunk.publish.events"`
+	OutputSplunkRetries                 int            `json:"output.splunk.retries"`
 	PeSkippedImages                     int            `json:"pe.skipped.images"`
 	PeDirectoryParseErrors              int            `json:"pe.directory.parse.errors"`
 	PeVersionResourcesParseErrors       int            `json:"pe.version.resources.parse.errors"`
@@ -103,10 +130,12 @@ type Stats struct {
 	ProcessPebReadErrors                int            `json:"process.peb.read.errors"`
 	ProcessReaped                       int            `json:"process.reaped"`
 	ProcessThreadCount                  int            `json:"process.thread.count"`
+	QuarantineFilesQuarantined          int            `json:"quarantine.files.quarantined"`
 	RegistryKcbCount                    int            `json:"registry.kcb.count"`
 	RegistryKcbMisses                   int            `json:"registry.kcb.misses"`
 	RegistryKeyHandleHits               int            `json:"registry.key.handle.hits"`
 	RegistryUnknownKeysCount            int            `json:"registry.unknown.keys.count"`
+	RiskThresholdExceeded               int            `json:"risk.threshold.exceeded"`
 	StackwalkEnqueued                   int            `json:"stackwalk.enqueued"`
 	StackwalkFlushes                    int            `json:"stackwalk.flushes"`
 	StackwalkFlushesProcs               map[string]int `json:"stackwalk.flushes.procs"`
@@ -26,7 +26,9 @@ import (
 	"github.com/rabbitstack/fibratus/internal/evasion"
 	"github.com/rabbitstack/fibratus/pkg/aggregator"
 	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	alertstore "github.com/rabbitstack/fibratus/pkg/alertsender/store"
 	"github.com/rabbitstack/fibratus/pkg/api"
+	"github.com/rabbitstack/fibratus/pkg/api/handler"
 	"github.com/rabbitstack/fibratus/pkg/cap"
 	"github.com/rabbitstack/fibratus/pkg/config"
 	"github.com/rabbitstack/fibratus/pkg/filament"
@@ -62,6 +64,7 @@ type App struct {
 	psnap      ps.Snapshotter
 	filament   filament.Filament
 	agg        *aggregator.BufferedAggregator
+	alerts     *alertstore.Store
 	writer     cap.Writer
 	reader     cap.Reader
 	signals    chan struct{}
@@ -187,6 +190,25 @@ func (f *App) Run(args []string) error {
 	log.Infof("bootstrapping with pid %d. Version: %s", os.Getpid(), version.Get())
 	log.Infof("configuration options: %s", cfg.Print())
 
+	// set the routing table that dispatches alerts to sender instances
+	alertsender.SetRouting(cfg.AlertRouting)
+	// resolve filter fields referenced by alert senders, e.g. PagerDuty deduplication fields
+	alertsender.SetFieldResolver(filter.GetFieldValue)
+
+	// open the local alert store that records all emitted alerts
+	if cfg.AlertStore.Enabled {
+		alerts, err := alertstore.Open(cfg.AlertStore)
+		if err != nil {
+			log.Warnf("unable to open alert store: %v", err)
+		} else {
+			f.alerts = alerts
+			alertsender.SetRecorder(alerts)
+			h := handler.Alerts(alerts)
+			api.RegisterHandler("/alerts", h)
+			api.RegisterHandler("/alerts/", h)
+		}
+	}
+
 	// build the filter from the CLI argument. If we got
 	// a valid expression the filter is attached to the
 	// event consumer
@@ -244,6 +266,9 @@ func (f *App) Run(args []string) error {
 		// register rule engine
 		if f.engine != nil {
 			f.evs.RegisterEventListener(f.engine)
+			if scorer := f.engine.RiskScorer(); scorer != nil {
+				api.RegisterHandler("/risk", handler.Risk(scorer))
+			}
 		}
 		// register YARA scanner
 		if cfg.Yara.Enabled {
@@ -262,9 +287,10 @@ func (f *App) Run(args []string) error {
 			f.evs.Events(),
 			f.evs.Errors(),
 			cfg.Aggregator,
-			cfg.Output,
+			cfg.Outputs,
 			cfg.Transformers,
 			cfg.Alertsenders,
+			f.compileOutputFilter,
 		)
 		if err != nil {
 			return err
@@ -354,9 +380,10 @@ func (f *App) ReadCapture(ctx context.Context, args []string) error {
 			evts,
 			errs,
 			f.config.Aggregator,
-			f.config.Output,
+			f.config.Outputs,
 			f.config.Transformers,
 			f.config.Alertsenders,
+			f.compileOutputFilter,
 		)
 		if err != nil {
 			return err
@@ -366,6 +393,16 @@ func (f *App) ReadCapture(ctx context.Context, args []string) error {
 	return api.StartServer(f.config)
 }
 
+// compileOutputFilter builds the filter that decides
+// which events are routed to a particular output.
+func (f *App) compileOutputFilter(expr string) (aggregator.Filter, error) {
+	fltr := filter.New(expr, f.config, filter.WithPSnapshotter(f.psnap))
+	if err := fltr.Compile(); err != nil {
+		return nil, err
+	}
+	return fltr, nil
+}
+
 // Wait waits for the app to receive the termination signal.
 func (f *App) Wait() {
 	if f.signals != nil {
@@ -420,9 +457,20 @@ func (f *App) Shutdown() error {
 	if err := api.CloseServer(); err != nil {
 		errs = append(errs, err)
 	}
+	if f.engine != nil {
+		if err := f.engine.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	if err := alertsender.ShutdownAll(); err != nil {
 		errs = append(errs, err)
 	}
+	if f.alerts != nil {
+		alertsender.CloseRecorder()
+		if err := f.alerts.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 
 	signature.GetSignatures().Close()
 	fs.GetMetadataStore().Close()
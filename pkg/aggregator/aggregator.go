@@ -19,7 +19,6 @@
 package aggregator
 
 import (
-	"errors"
 	"expvar"
 	"time"
 
@@ -34,8 +33,12 @@ import (
 	_ "github.com/rabbitstack/fibratus/pkg/outputs/console"
 	_ "github.com/rabbitstack/fibratus/pkg/outputs/elasticsearch"
 	_ "github.com/rabbitstack/fibratus/pkg/outputs/eventlog"
+	_ "github.com/rabbitstack/fibratus/pkg/outputs/file"
 	_ "github.com/rabbitstack/fibratus/pkg/outputs/http"
+	_ "github.com/rabbitstack/fibratus/pkg/outputs/kafka"
 	_ "github.com/rabbitstack/fibratus/pkg/outputs/null"
+	_ "github.com/rabbitstack/fibratus/pkg/outputs/otlp"
+	_ "github.com/rabbitstack/fibratus/pkg/outputs/splunk"
 
 	// initialize alert senders
 	_ "github.com/rabbitstack/fibratus/pkg/alertsender/mail"
@@ -64,7 +67,7 @@ var (
 )
 
 // BufferedAggregator collects events from the inbound channel and produces batches on regular intervals. The batches
-// are pushed to the work queue from which load-balanced configured workers consume the batches and publish to the outputs.
+// are submitted to the work queue of each output from which load-balanced workers consume the batches and publish to the output.
 type BufferedAggregator struct {
 	evtsc   <-chan *event.Event
 	errsc   <-chan error
@@ -72,8 +75,7 @@ type BufferedAggregator struct {
 	flusher *time.Ticker
 	// queue of inbound events
 	evts []*event.Event
-	// work queue that forwarder passes to outputs
-	wq         queue
+	// submitter forwards batches to output work queues
 	submitter  *submitter
 	transforms []transformers.Transformer
 	c          Config
@@ -84,9 +86,10 @@ func NewBuffered(
 	evts <-chan *event.Event,
 	errs <-chan error,
 	aggConfig Config,
-	outputConfig outputs.Config,
+	outputConfigs []outputs.Config,
 	transformerConfigs []transformers.Config,
 	alertsenderConfigs []alertsender.Config,
+	compileFilter FilterCompiler,
 ) (*BufferedAggregator, error) {
 	flushInterval := aggConfig.FlushPeriod
 	if flushInterval < time.Millisecond*250 {
@@ -98,12 +101,11 @@ func NewBuffered(
 		errsc:   errs,
 		stop:    make(chan struct{}, 1),
 		flusher: time.NewTicker(flushInterval),
-		wq:      make(chan *event.Batch),
 		c:       aggConfig,
 	}
 
 	var err error
-	agg.submitter, err = newSubmitter(agg.wq, outputConfig)
+	agg.submitter, err = newSubmitter(outputConfigs, aggConfig, compileFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -129,29 +131,12 @@ func (agg *BufferedAggregator) Stop() error {
 	// flush enqueued events
 	b := event.NewBatch(agg.evts...)
 	if b.Len() > 0 {
-		done := make(chan struct{}, 1)
-		go func() {
-			agg.wq <- b
-			done <- struct{}{}
-		}()
-
-		select {
-		case <-done:
-			close(agg.wq)
-		case <-time.After(agg.c.FlushTimeout):
-			return errors.New("fail to flush events after stop timed out")
-		}
-	}
-
-	// sleep a bit before closing the clients
-	time.Sleep(time.Millisecond * 150)
-
-	err := agg.submitter.shutdown()
-	if err != nil {
-		return err
+		agg.submitter.submit(b)
 	}
 
-	return nil
+	// wait for outputs to publish pending
+	// batches before closing the clients
+	return agg.submitter.shutdown(agg.c.FlushTimeout)
 }
 
 // run starts the aggregator loop. The aggregator receives event stream from the upstream channel, buffers
@@ -169,9 +154,9 @@ func (agg *BufferedAggregator) run() {
 			b := event.NewBatch(agg.evts...)
 			l := b.Len()
 			batchEvents.Add(l)
-			// push the batch to the work queue
+			// push the batch to output work queues
 			if l > 0 {
-				agg.wq <- b
+				agg.submitter.submit(b)
 			}
 			flushesCount.Add(1)
 			// clear the queue
@@ -0,0 +1,671 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregator
+
+import (
+	"encoding/binary"
+	"errors"
+	"expvar"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/cap/section"
+	capver "github.com/rabbitstack/fibratus/pkg/cap/version"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	ptypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/rabbitstack/fibratus/pkg/util/multierror"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// bufferBacklog reports the number of batches pending delivery per output
+	bufferBacklog = expvar.NewMap("aggregator.buffer.backlog.batches")
+	// bufferSize reports the size of buffer segments in bytes per output
+	bufferSize = expvar.NewMap("aggregator.buffer.backlog.bytes")
+	// bufferDropped counts batches dropped due to buffer size or age limits per output
+	bufferDropped = expvar.NewMap("aggregator.buffer.dropped.batches")
+	// bufferRetries counts failed publish attempts of buffered batches per output
+	bufferRetries = expvar.NewMap("aggregator.buffer.retries")
+	// bufferReplayed counts batches recovered from the buffer on startup per output
+	bufferReplayed = expvar.NewMap("aggregator.buffer.replayed.batches")
+	// bufferRejected counts batches dropped after the output rejected them per output
+	bufferRejected = expvar.NewMap("aggregator.buffer.rejected.batches")
+)
+
+const (
+	// segmentExt is the extension of buffer segment files
+	segmentExt = ".seg"
+	// cursorFile stores the position of the last published batch
+	cursorFile = "cursor"
+	// recordHeaderSize is the size of the payload length and checksum preceding each record
+	recordHeaderSize = 8
+)
+
+var errBufferFull = errors.New("buffer is full")
+
+// segment is the append-only file that stores a sequence of batch records.
+type segment struct {
+	seq     uint64
+	size    int64
+	pending int
+}
+
+// record is the batch pending delivery.
+type record struct {
+	seg       *segment
+	off       int64
+	size      int64
+	timestamp time.Time
+	// batch is the in-memory batch that spares
+	// the decoding of recently appended records
+	batch *event.Batch
+}
+
+// buffer is the write-ahead log that sits between the aggregator and the output. Batches are
+// appended to segment files before they are published, and the delivery loop publishes them
+// in order, retrying failed attempts with exponential backoff until the batch is accepted by
+// the output. Once all batches of the segment are published, the segment file is removed.
+type buffer struct {
+	name        string
+	dir         string
+	config      BufferConfig
+	maxSize     int64
+	segmentSize int64
+	cacheSize   int
+
+	mu       sync.Mutex
+	segments []*segment
+	records  []*record
+	active   *os.File
+	size     int64
+	cached   int
+
+	clients   []outputs.Client
+	connected []bool
+	next      int
+
+	backlog *expvar.Int
+	bytes   *expvar.Int
+	notify  chan struct{}
+	quit    chan struct{}
+	done    chan struct{}
+}
+
+// newBuffer opens the buffer for the output and starts the delivery loop. Batches
+// left in the buffer by the previous run are published before the new batches.
+// Up to cacheSize recently appended batches are kept in memory.
+func newBuffer(name string, config BufferConfig, cacheSize int, clients []outputs.Client) (*buffer, error) {
+	b := &buffer{
+		name:        name,
+		dir:         filepath.Join(bufferDir(config.Path), name),
+		config:      config,
+		maxSize:     int64(config.MaxSize) * 1024 * 1024,
+		segmentSize: int64(config.SegmentSize) * 1024 * 1024,
+		cacheSize:   cacheSize,
+		segments:    make([]*segment, 0),
+		records:     make([]*record, 0),
+		clients:     clients,
+		connected:   make([]bool, len(clients)),
+		backlog:     new(expvar.Int),
+		bytes:       new(expvar.Int),
+		notify:      make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	// segments are rotated before the buffer reaches the
+	// size limit, so the oldest segment can be evicted
+	if b.maxSize > 0 && (b.segmentSize <= 0 || b.segmentSize > b.maxSize/2) {
+		b.segmentSize = max(b.maxSize/2, 1)
+	}
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("%s output has no clients", name)
+	}
+	if err := os.MkdirAll(b.dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create %q buffer directory: %v", b.dir, err)
+	}
+	bufferBacklog.Set(name, b.backlog)
+	bufferSize.Set(name, b.bytes)
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+
+	go b.run()
+
+	return b, nil
+}
+
+// append writes the batch to the active segment. If the buffer is full
+// and the drop-newest policy is in effect, the batch is rejected.
+func (b *buffer) append(batch *event.Batch) error {
+	now := time.Now()
+	payload := encodeBatch(batch, now)
+	rec := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(rec, uint32(len(payload)))
+	binary.LittleEndian.PutUint32(rec[4:], crc32.ChecksumIEEE(payload))
+	rec = append(rec, payload...)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	defer b.updateGauges()
+
+	b.evict()
+	if b.config.Policy == DropNewest && b.maxSize > 0 && b.size+int64(len(rec)) > b.maxSize {
+		bufferDropped.Add(b.name, 1)
+		return errBufferFull
+	}
+	seg := b.segments[len(b.segments)-1]
+	if b.segmentSize > 0 && seg.size >= b.segmentSize {
+		if err := b.rotate(); err != nil {
+			return err
+		}
+		seg = b.segments[len(b.segments)-1]
+	}
+	if _, err := b.active.Write(rec); err != nil {
+		return err
+	}
+	if err := b.active.Sync(); err != nil {
+		return err
+	}
+	r := &record{seg: seg, off: seg.size, size: int64(len(rec)), timestamp: now}
+	if b.cached < b.cacheSize {
+		r.batch = batch
+		b.cached++
+	}
+	seg.size += r.size
+	seg.pending++
+	b.size += r.size
+	b.records = append(b.records, r)
+	b.evict()
+
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// stop instructs the delivery loop to stop. Unpublished
+// batches remain in the buffer until the next run.
+func (b *buffer) stop() {
+	close(b.quit)
+}
+
+// close waits for the in-flight batch to be published and closes the output clients. If
+// the in-flight batch can't be published within the timeout, the buffer is closed anyway
+// since the batch is already persisted.
+func (b *buffer) close(timeout time.Duration) error {
+	select {
+	case <-b.done:
+	case <-time.After(timeout):
+		log.Warnf("%s output didn't publish the buffered batch in %v. Retrying on the next run", b.name, timeout)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var errs []error
+	if err := b.active.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, client := range b.clients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return multierror.Wrap(errs...)
+}
+
+// load recovers pending batches from segment files
+// and creates the new active segment.
+func (b *buffer) load() error {
+	cseq, coff := b.readCursor()
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return err
+	}
+	var seqs []uint64
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != segmentExt {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(e.Name(), segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	last := cseq
+	for _, seq := range seqs {
+		last = max(last, seq)
+		seg := &segment{seq: seq}
+		if seq >= cseq {
+			if err := b.scan(seg, cseq, coff); err != nil {
+				return err
+			}
+		}
+		if seg.pending == 0 {
+			b.removeFile(seg)
+			continue
+		}
+		b.segments = append(b.segments, seg)
+		b.size += seg.size
+	}
+
+	if n := len(b.records); n > 0 {
+		log.Infof("replaying %d buffered batch(es) to %s output", n, b.name)
+		bufferReplayed.Add(b.name, int64(n))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.create(last + 1); err != nil {
+		return err
+	}
+	b.evict()
+	b.updateGauges()
+
+	return nil
+}
+
+// scan reads the records of the segment file. Records preceding
+// the cursor were published by the previous run and are skipped.
+// A truncated or corrupted record terminates the scan, since the
+// subsequent records can't be located.
+func (b *buffer) scan(seg *segment, cseq uint64, coff int64) error {
+	data, err := os.ReadFile(b.file(seg))
+	if err != nil {
+		return err
+	}
+	seg.size = int64(len(data))
+	var off int64
+	for off+recordHeaderSize <= seg.size {
+		n := int64(binary.LittleEndian.Uint32(data[off:]))
+		sum := binary.LittleEndian.Uint32(data[off+4:])
+		end := off + recordHeaderSize + n
+		if n < 12 || end > seg.size || crc32.ChecksumIEEE(data[off+recordHeaderSize:end]) != sum {
+			log.Warnf("found corrupted record at offset %d in %s buffer segment", off, b.file(seg))
+			break
+		}
+		if seg.seq > cseq || off >= coff {
+			ts := time.Unix(0, int64(binary.LittleEndian.Uint64(data[off+recordHeaderSize:])))
+			b.records = append(b.records, &record{seg: seg, off: off, size: end - off, timestamp: ts})
+			seg.pending++
+		}
+		off = end
+	}
+	return nil
+}
+
+// create creates the new active segment. The caller must hold the lock.
+func (b *buffer) create(seq uint64) error {
+	seg := &segment{seq: seq}
+	f, err := os.OpenFile(b.file(seg), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	b.active = f
+	b.segments = append(b.segments, seg)
+	return nil
+}
+
+// rotate seals the active segment and creates the new one. The caller must hold the lock.
+func (b *buffer) rotate() error {
+	seg := b.segments[len(b.segments)-1]
+	if err := b.active.Close(); err != nil {
+		return err
+	}
+	if err := b.create(seg.seq + 1); err != nil {
+		return err
+	}
+	if seg.pending == 0 {
+		b.removeSegment(seg)
+	}
+	return nil
+}
+
+// evict drops the batches exceeding the retention period. If the buffer exceeds the
+// size limit and the drop-oldest policy is in effect, the oldest segments are evicted.
+// The caller must hold the lock.
+func (b *buffer) evict() {
+	for len(b.records) > 0 {
+		r := b.records[0]
+		if b.config.MaxAge <= 0 || time.Since(r.timestamp) <= b.config.MaxAge {
+			break
+		}
+		bufferDropped.Add(b.name, 1)
+		b.remove(r)
+	}
+	if b.config.Policy == DropNewest || b.maxSize <= 0 {
+		return
+	}
+	for b.size > b.maxSize && len(b.records) > 0 {
+		seg := b.records[0].seg
+		if seg == b.segments[len(b.segments)-1] {
+			if err := b.rotate(); err != nil {
+				log.Warnf("unable to rotate %s output buffer segment: %v", b.name, err)
+				return
+			}
+		}
+		log.Warnf("%s output buffer is full. Dropping %d oldest batch(es)", b.name, seg.pending)
+		for len(b.records) > 0 && b.records[0].seg == seg {
+			bufferDropped.Add(b.name, 1)
+			b.remove(b.records[0])
+		}
+	}
+}
+
+// remove removes the head record from the buffer. The segment
+// file is deleted once all of its batches are consumed. The
+// caller must hold the lock.
+func (b *buffer) remove(r *record) {
+	if len(b.records) == 0 || b.records[0] != r {
+		// the batch was already evicted
+		return
+	}
+	b.records = b.records[1:]
+	if r.batch != nil {
+		b.cached--
+	}
+	r.seg.pending--
+	if r.seg.pending == 0 && r.seg != b.segments[len(b.segments)-1] {
+		b.removeSegment(r.seg)
+	}
+}
+
+// removeSegment deletes the sealed segment. The caller must hold the lock.
+func (b *buffer) removeSegment(seg *segment) {
+	for i, s := range b.segments {
+		if s == seg {
+			b.segments = append(b.segments[:i], b.segments[i+1:]...)
+			b.size -= seg.size
+			b.removeFile(seg)
+			return
+		}
+	}
+}
+
+func (b *buffer) removeFile(seg *segment) {
+	if err := os.Remove(b.file(seg)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warnf("unable to remove buffer segment: %v", err)
+	}
+}
+
+// head returns the oldest pending batch record.
+func (b *buffer) head() *record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.evict()
+	b.updateGauges()
+	if len(b.records) == 0 {
+		return nil
+	}
+	return b.records[0]
+}
+
+// read returns the batch of the record. If the batch
+// is not cached, it is decoded from the segment file.
+func (b *buffer) read(r *record) (*event.Batch, error) {
+	if r.batch != nil {
+		return r.batch, nil
+	}
+	f, err := os.Open(b.file(r.seg))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	payload := make([]byte, r.size-recordHeaderSize)
+	if _, err := f.ReadAt(payload, r.off+recordHeaderSize); err != nil {
+		return nil, err
+	}
+	return decodeBatch(payload)
+}
+
+// ack removes the published batch from the buffer and
+// advances the cursor past the batch record.
+func (b *buffer) ack(r *record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(r)
+	b.updateGauges()
+	cursor := fmt.Sprintf("%d %d", r.seg.seq, r.off+r.size)
+	if err := os.WriteFile(filepath.Join(b.dir, cursorFile), []byte(cursor), 0600); err != nil {
+		log.Warnf("unable to write %s output buffer cursor: %v", b.name, err)
+	}
+}
+
+// discard drops the batch that can't be read from the segment
+// file or can't be accepted by the output, and increments the
+// counter that tracks the reason for dropping the batch.
+func (b *buffer) discard(r *record, err error, counter *expvar.Map) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.records) == 0 || b.records[0] != r {
+		// the batch was evicted along with the segment
+		return
+	}
+	log.Warnf("dropping batch from %s output buffer: %v", b.name, err)
+	counter.Add(b.name, 1)
+	b.remove(r)
+	b.updateGauges()
+}
+
+// readCursor returns the segment sequence and the offset
+// following the last batch published by the previous run.
+func (b *buffer) readCursor() (uint64, int64) {
+	data, err := os.ReadFile(filepath.Join(b.dir, cursorFile))
+	if err != nil {
+		return 0, 0
+	}
+	var (
+		seq uint64
+		off int64
+	)
+	if _, err := fmt.Sscanf(string(data), "%d %d", &seq, &off); err != nil {
+		return 0, 0
+	}
+	return seq, off
+}
+
+// run is the delivery loop. Batches are published in order. When publishing
+// fails, the next attempt is scheduled after the backoff interval that doubles
+// on every failed attempt. The batch is dropped if the output signals it can
+// never accept the batch, or the maximum number of attempts is exhausted, so
+// it doesn't hold back the delivery of subsequent batches.
+func (b *buffer) run() {
+	defer close(b.done)
+	var (
+		backoff  = initialBackoff
+		last     *record
+		attempts int
+	)
+	for {
+		select {
+		case <-b.quit:
+			return
+		default:
+		}
+		r := b.head()
+		if r == nil {
+			select {
+			case <-b.notify:
+				continue
+			case <-b.quit:
+				return
+			}
+		}
+		if r != last {
+			last, attempts = r, 0
+		}
+		batch, err := b.read(r)
+		if err != nil {
+			b.discard(r, fmt.Errorf("unreadable batch: %w", err), bufferDropped)
+			continue
+		}
+		if err := b.publish(batch); err != nil {
+			attempts++
+			if outputs.IsPermanent(err) {
+				b.discard(r, fmt.Errorf("batch rejected by output: %w", err), bufferRejected)
+				backoff = initialBackoff
+				continue
+			}
+			if b.config.MaxRetries > 0 && attempts > b.config.MaxRetries {
+				b.discard(r, fmt.Errorf("giving up after %d attempts: %w", attempts, err), bufferRejected)
+				backoff = initialBackoff
+				continue
+			}
+			bufferRetries.Add(b.name, 1)
+			log.Warnf("couldn't publish buffered batch to %s output: %v. Retrying in %v...", b.name, err, backoff)
+			select {
+			case <-time.After(backoff):
+				backoff = min(backoff*2, maxBackoff)
+			case <-b.quit:
+				return
+			}
+			continue
+		}
+		backoff = initialBackoff
+		b.ack(r)
+	}
+}
+
+// publish publishes the batch to the next client in the output
+// group. The client is connected before the first publish and
+// after it reports the connection loss.
+func (b *buffer) publish(batch *event.Batch) error {
+	i := b.next % len(b.clients)
+	b.next++
+	client := b.clients[i]
+	if !b.connected[i] {
+		if err := client.Connect(); err != nil {
+			return err
+		}
+		b.connected[i] = true
+	}
+	err := client.Publish(batch)
+	if errors.Is(err, outputs.ErrConnectionLost) {
+		clientReconnects.Add(1)
+		b.connected[i] = false
+	}
+	if err != nil {
+		clientPublishErrors.Add(1)
+	}
+	return err
+}
+
+// updateGauges updates the backlog gauges. The caller must hold the lock.
+func (b *buffer) updateGauges() {
+	b.backlog.Set(int64(len(b.records)))
+	b.bytes.Set(b.size)
+}
+
+func (b *buffer) file(seg *segment) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%020d%s", seg.seq, segmentExt))
+}
+
+// encodeBatch serializes the batch to the record payload. The payload
+// starts with the timestamp and the number of events, followed by the
+// raw event and the process state of each event.
+func encodeBatch(batch *event.Batch, ts time.Time) []byte {
+	b := make([]byte, 0, 1024)
+	b = binary.LittleEndian.AppendUint64(b, uint64(ts.UnixNano()))
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(batch.Events)))
+	for _, e := range batch.Events {
+		raw := e.MarshalRaw()
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(raw)))
+		b = append(b, raw...)
+		// the raw event format only retains the
+		// process state for process creation events
+		var ps []byte
+		if e.PS != nil && !e.IsCreateProcess() && !e.IsProcessRundown() {
+			ps = e.PS.Marshal()
+		}
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(ps)))
+		b = append(b, ps...)
+	}
+	return b
+}
+
+// decodeBatch recovers the batch from the record payload.
+func decodeBatch(b []byte) (*event.Batch, error) {
+	if len(b) < 12 {
+		return nil, errors.New("short batch record")
+	}
+	n := binary.LittleEndian.Uint32(b[8:])
+	b = b[12:]
+	next := func() ([]byte, error) {
+		if len(b) < 4 {
+			return nil, errors.New("truncated batch record")
+		}
+		l := binary.LittleEndian.Uint32(b)
+		if uint32(len(b)-4) < l {
+			return nil, errors.New("truncated batch record")
+		}
+		buf := b[4 : 4+l]
+		b = b[4+l:]
+		return buf, nil
+	}
+	evts := make([]*event.Event, 0, n)
+	for i := uint32(0); i < n; i++ {
+		raw, err := next()
+		if err != nil {
+			return nil, err
+		}
+		ps, err := next()
+		if err != nil {
+			return nil, err
+		}
+		e, err := event.NewFromCapture(raw, capver.EvtSecV2)
+		if err != nil {
+			return nil, err
+		}
+		if len(ps) > 0 {
+			e.PS, err = ptypes.NewFromCapture(ps, section.New(section.Process, capver.ProcessSecV4, 0, uint32(len(ps))))
+			if err != nil {
+				return nil, err
+			}
+		}
+		evts = append(evts, e)
+	}
+	return event.NewBatch(evts...), nil
+}
+
+// bufferDir returns the buffer directory. If the path
+// is not specified, the Buffer directory in the
+// installation path is used.
+func bufferDir(path string) string {
+	if path != "" {
+		return path
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return filepath.Join(os.Getenv("PROGRAMFILES"), "Fibratus", "Buffer")
+	}
+	return filepath.Join(filepath.Dir(exe), "..", "Buffer")
+}
@@ -0,0 +1,296 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregator
+
+import (
+	"errors"
+	"expvar"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingClient struct {
+	mu    sync.Mutex
+	fails int
+	err   error
+	seqs  []uint64
+}
+
+func (c *failingClient) Connect() error { return nil }
+func (c *failingClient) Close() error   { return nil }
+func (c *failingClient) Publish(b *event.Batch) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fails > 0 {
+		c.fails--
+		if c.err != nil {
+			return c.err
+		}
+		return errors.New("connection refused")
+	}
+	for _, e := range b.Events {
+		c.seqs = append(c.seqs, e.Seq)
+	}
+	return nil
+}
+
+func (c *failingClient) published() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]uint64(nil), c.seqs...)
+}
+
+func counter(m *expvar.Map, name string) int64 {
+	if v, ok := m.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func newBatch(seq uint64) *event.Batch {
+	return event.NewBatch(&event.Event{
+		Seq:       seq,
+		PID:       859,
+		Tid:       2484,
+		Type:      event.CreateFile,
+		Category:  event.File,
+		Name:      "CreateFile",
+		Timestamp: time.Now(),
+	})
+}
+
+func bufferConfig(path string) BufferConfig {
+	return BufferConfig{
+		Enabled:     true,
+		Path:        path,
+		MaxSize:     1,
+		MaxAge:      time.Hour,
+		SegmentSize: 1,
+		Policy:      DropOldest,
+	}
+}
+
+func segments(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	require.NoError(t, err)
+	return files
+}
+
+func TestBufferOrderedRetry(t *testing.T) {
+	path := t.TempDir()
+	client := &failingClient{fails: 1}
+	retries := counter(bufferRetries, "elasticsearch")
+	b, err := newBuffer("elasticsearch", bufferConfig(path), 0, []outputs.Client{client})
+	require.NoError(t, err)
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		require.NoError(t, b.append(newBatch(seq)))
+	}
+
+	require.Eventually(t, func() bool { return len(client.published()) == 3 }, time.Second*10, time.Millisecond*10)
+	assert.Equal(t, []uint64{1, 2, 3}, client.published())
+	assert.Equal(t, int64(1), counter(bufferRetries, "elasticsearch")-retries)
+	assert.Equal(t, "0", bufferBacklog.Get("elasticsearch").String())
+
+	b.stop()
+	require.NoError(t, b.close(time.Second))
+}
+
+func TestBufferReplay(t *testing.T) {
+	path := t.TempDir()
+	b, err := newBuffer("http", bufferConfig(path), 0, []outputs.Client{&failingClient{fails: math.MaxInt}})
+	require.NoError(t, err)
+	for seq := uint64(1); seq <= 3; seq++ {
+		require.NoError(t, b.append(newBatch(seq)))
+	}
+	b.stop()
+	require.NoError(t, b.close(time.Second))
+
+	client := &failingClient{}
+	replayed := counter(bufferReplayed, "http")
+	b, err = newBuffer("http", bufferConfig(path), 0, []outputs.Client{client})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(client.published()) == 3 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, []uint64{1, 2, 3}, client.published())
+	assert.Equal(t, int64(3), counter(bufferReplayed, "http")-replayed)
+	require.NoError(t, b.append(newBatch(4)))
+	require.Eventually(t, func() bool { return len(client.published()) == 4 }, time.Second*5, time.Millisecond*10)
+	b.stop()
+	require.NoError(t, b.close(time.Second))
+
+	// published batches are not replayed
+	client = &failingClient{}
+	b, err = newBuffer("http", bufferConfig(path), 0, []outputs.Client{client})
+	require.NoError(t, err)
+	require.NoError(t, b.append(newBatch(5)))
+	require.Eventually(t, func() bool { return len(client.published()) == 1 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, []uint64{5}, client.published())
+	b.stop()
+	require.NoError(t, b.close(time.Second))
+
+	assert.Len(t, segments(t, filepath.Join(path, "http")), 1)
+}
+
+func TestBufferCorruptedSegment(t *testing.T) {
+	path := t.TempDir()
+	b, err := newBuffer("syslog", bufferConfig(path), 0, []outputs.Client{&failingClient{fails: math.MaxInt}})
+	require.NoError(t, err)
+	require.NoError(t, b.append(newBatch(1)))
+	require.NoError(t, b.append(newBatch(2)))
+	b.stop()
+	require.NoError(t, b.close(time.Second))
+
+	// simulate the torn write of the last record
+	files := segments(t, filepath.Join(path, "syslog"))
+	require.Len(t, files, 1)
+	fi, err := os.Stat(files[0])
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(files[0], fi.Size()-4))
+
+	client := &failingClient{}
+	b, err = newBuffer("syslog", bufferConfig(path), 0, []outputs.Client{client})
+	require.NoError(t, err)
+	defer b.close(time.Second)
+	defer b.stop()
+
+	require.Eventually(t, func() bool { return len(client.published()) == 1 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, []uint64{1}, client.published())
+}
+
+func TestBufferRejectedBatch(t *testing.T) {
+	path := t.TempDir()
+	client := &failingClient{fails: 1, err: outputs.Permanent(errors.New("splunk request failed with 400 status code"))}
+	rejected := counter(bufferRejected, "splunk")
+	retries := counter(bufferRetries, "splunk")
+	b, err := newBuffer("splunk", bufferConfig(path), 0, []outputs.Client{client})
+	require.NoError(t, err)
+	defer b.close(time.Second)
+	defer b.stop()
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		require.NoError(t, b.append(newBatch(seq)))
+	}
+
+	require.Eventually(t, func() bool { return len(client.published()) == 2 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, []uint64{2, 3}, client.published())
+	assert.Equal(t, int64(1), counter(bufferRejected, "splunk")-rejected)
+	assert.Equal(t, int64(0), counter(bufferRetries, "splunk")-retries)
+}
+
+func TestBufferMaxRetries(t *testing.T) {
+	path := t.TempDir()
+	client := &failingClient{fails: 2}
+	rejected := counter(bufferRejected, "amqp")
+	config := bufferConfig(path)
+	config.MaxRetries = 1
+	b, err := newBuffer("amqp", config, 0, []outputs.Client{client})
+	require.NoError(t, err)
+	defer b.close(time.Second)
+	defer b.stop()
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		require.NoError(t, b.append(newBatch(seq)))
+	}
+
+	require.Eventually(t, func() bool { return len(client.published()) == 2 }, time.Second*10, time.Millisecond*10)
+	assert.Equal(t, []uint64{2, 3}, client.published())
+	assert.Equal(t, int64(1), counter(bufferRejected, "amqp")-rejected)
+}
+
+func TestBufferDropPolicies(t *testing.T) {
+	var tests = []struct {
+		policy DropPolicy
+		seqs   []uint64
+	}{
+		{DropOldest, []uint64{7, 8, 9, 10}},
+		{DropNewest, []uint64{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			path := t.TempDir()
+			config := bufferConfig(path)
+			config.Policy = tt.policy
+			client := &failingClient{fails: math.MaxInt}
+			b, err := newBuffer("amqp", config, 0, []outputs.Client{client})
+			require.NoError(t, err)
+
+			// shrink the buffer to accommodate a few records
+			size := int64(len(encodeBatch(newBatch(1), time.Now())) + recordHeaderSize)
+			b.mu.Lock()
+			b.maxSize = size * 4
+			b.segmentSize = size * 2
+			b.mu.Unlock()
+
+			dropped := counter(bufferDropped, "amqp")
+			var full int
+			for seq := uint64(1); seq <= 10; seq++ {
+				if err := b.append(newBatch(seq)); errors.Is(err, errBufferFull) {
+					full++
+				}
+			}
+			assert.True(t, counter(bufferDropped, "amqp")-dropped >= 6)
+			if tt.policy == DropNewest {
+				assert.Equal(t, 6, full)
+			} else {
+				assert.Zero(t, full)
+			}
+
+			client.mu.Lock()
+			client.fails = 0
+			client.mu.Unlock()
+			b.stop()
+			require.NoError(t, b.close(time.Second))
+
+			b, err = newBuffer("amqp", config, 0, []outputs.Client{client})
+			require.NoError(t, err)
+			require.Eventually(t, func() bool { return len(client.published()) == len(tt.seqs) }, time.Second*5, time.Millisecond*10)
+			assert.Equal(t, tt.seqs, client.published())
+			b.stop()
+			require.NoError(t, b.close(time.Second))
+		})
+	}
+}
+
+func TestBufferMaxAge(t *testing.T) {
+	config := bufferConfig(t.TempDir())
+	config.MaxAge = time.Millisecond * 100
+	b, err := newBuffer("eventlog", config, 4, []outputs.Client{&failingClient{fails: math.MaxInt}})
+	require.NoError(t, err)
+	defer b.close(time.Second)
+	defer b.stop()
+
+	dropped := counter(bufferDropped, "eventlog")
+	require.NoError(t, b.append(newBatch(1)))
+	require.NoError(t, b.append(newBatch(2)))
+
+	require.Eventually(t, func() bool { return b.head() == nil }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, int64(2), counter(bufferDropped, "eventlog")-dropped)
+	assert.Equal(t, "0", bufferBacklog.Get("eventlog").String())
+}
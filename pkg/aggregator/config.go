@@ -27,24 +27,87 @@ import (
 const (
 	flushPeriod  = "aggregator.flush-period"
 	flushTimeout = "aggregator.flush-timeout"
+	queueSize    = "aggregator.queue-size"
+
+	bufferEnabled     = "aggregator.buffer.enabled"
+	bufferPath        = "aggregator.buffer.path"
+	bufferMaxSize     = "aggregator.buffer.max-size"
+	bufferMaxAge      = "aggregator.buffer.max-age"
+	bufferSegmentSize = "aggregator.buffer.segment-size"
+	bufferPolicy      = "aggregator.buffer.policy"
+	bufferMaxRetries  = "aggregator.buffer.max-retries"
+)
+
+// defaultQueueSize is the default capacity of the output batch queue
+const defaultQueueSize = 64
+
+// DropPolicy determines which batches are discarded when the buffer reaches the size limit.
+type DropPolicy string
+
+const (
+	// DropOldest evicts the oldest segment to make room for new batches
+	DropOldest DropPolicy = "drop-oldest"
+	// DropNewest rejects incoming batches while the buffer is full
+	DropNewest DropPolicy = "drop-newest"
 )
 
+// BufferConfig contains the settings of the disk-backed buffer
+// that sits between the aggregator and each output.
+type BufferConfig struct {
+	// Enabled indicates if batches are written to the buffer before they are published to outputs.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Path is the directory where buffer segments are stored.
+	Path string `json:"path" yaml:"path"`
+	// MaxSize is the maximum size of the buffer for each output expressed in megabytes.
+	MaxSize int `json:"max-size" yaml:"max-size"`
+	// MaxAge specifies the retention period of the buffered batches.
+	MaxAge time.Duration `json:"max-age" yaml:"max-age"`
+	// SegmentSize is the size in megabytes at which the segment file is rotated.
+	SegmentSize int `json:"segment-size" yaml:"segment-size"`
+	// Policy determines which batches are dropped when the buffer is full.
+	Policy DropPolicy `json:"policy" yaml:"policy"`
+	// MaxRetries is the maximum number of times the batch is published again before it is dropped.
+	MaxRetries int `json:"max-retries" yaml:"max-retries"`
+}
+
 // Config contains aggregator-specific configuration tweaks.
 type Config struct {
 	// FlushPeriod determines the period for flushing batches to outputs.
 	FlushPeriod time.Duration `json:"aggregator.flush-period" yaml:"aggregator.flush-period"`
 	// FlushTimeout represents the max time to wait before announcing failed flushing of enqueued events
 	FlushTimeout time.Duration `json:"aggregator.flush-timeout" yaml:"aggregator.flush-timeout"`
+	// QueueSize is the maximum number of batches buffered for each output.
+	QueueSize int `json:"aggregator.queue-size" yaml:"aggregator.queue-size"`
+	// Buffer contains the disk-backed output buffer settings.
+	Buffer BufferConfig `json:"aggregator.buffer" yaml:"aggregator.buffer"`
 }
 
 // AddFlags registers persistent aggregator flags.
 func AddFlags(flags *pflag.FlagSet) {
 	flags.Duration(flushPeriod, time.Millisecond*200, "Determines the period for flushing batches to outputs")
 	flags.Duration(flushTimeout, time.Second*4, "Represents the max time to wait before announcing failed flushing of enqueued events on aggregator shutdown")
+	flags.Int(queueSize, defaultQueueSize, "Specifies the maximum number of event batches buffered for each output. If multiple outputs are enabled, batches are dropped when the output queue is full")
+	flags.Bool(bufferEnabled, false, "Indicates if event batches are written to the disk-backed buffer before they are published to outputs")
+	flags.String(bufferPath, "", "Specifies the directory where buffer segments are stored. Defaults to the Buffer directory in the installation path")
+	flags.Int(bufferMaxSize, 512, "Specifies the maximum size of the buffer for each output in megabytes")
+	flags.Duration(bufferMaxAge, time.Hour*24, "Specifies the retention period of the buffered batches")
+	flags.Int(bufferSegmentSize, 16, "Specifies the size in megabytes at which the buffer segment file is rotated")
+	flags.String(bufferPolicy, string(DropOldest), "Determines which batches are dropped when the buffer is full. Possible values are drop-oldest and drop-newest")
+	flags.Int(bufferMaxRetries, 0, "Specifies the maximum number of times the buffered batch is published again before the batch is dropped. Zero retries the batch until it expires. Batches rejected by the output are dropped immediately")
 }
 
 // InitFromViper initializes aggregator flags from viper.
 func (c *Config) InitFromViper(v *viper.Viper) {
 	c.FlushPeriod = v.GetDuration(flushPeriod)
 	c.FlushTimeout = v.GetDuration(flushTimeout)
+	c.QueueSize = v.GetInt(queueSize)
+	c.Buffer = BufferConfig{
+		Enabled:     v.GetBool(bufferEnabled),
+		Path:        v.GetString(bufferPath),
+		MaxSize:     v.GetInt(bufferMaxSize),
+		MaxAge:      v.GetDuration(bufferMaxAge),
+		SegmentSize: v.GetInt(bufferSegmentSize),
+		Policy:      DropPolicy(v.GetString(bufferPolicy)),
+		MaxRetries:  v.GetInt(bufferMaxRetries),
+	}
 }
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregator
+
+import (
+	"expvar"
+	"math"
+	"strings"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"golang.org/x/time/rate"
+)
+
+var (
+	// outputSampledEvents counts events not forwarded to the output by the sampling rates
+	outputSampledEvents = expvar.NewMap("aggregator.output.sampled.events")
+	// outputRateLimitedEvents counts events not forwarded to the output because the maximum rate was exceeded
+	outputRateLimitedEvents = expvar.NewMap("aggregator.output.ratelimited.events")
+)
+
+// sampleScale is the resolution of the sampling rates in basis points.
+const sampleScale = 10000
+
+// sampler decides which events are forwarded to the output according
+// to the output sampling policy. Sampling is deterministic by process,
+// so all events of a sampled process are forwarded to the output. The
+// events exceeding the maximum rate are dropped by the token bucket.
+// Events matched by rules bypass the sampler.
+type sampler struct {
+	// rates keeps the lowercased event names and categories
+	// along with the share of events in basis points
+	rates   map[string]uint64
+	limiter *rate.Limiter
+}
+
+// drops accumulates the number of events dropped by the
+// sampler per event category.
+type drops struct {
+	sampled     map[event.Category]int64
+	ratelimited map[event.Category]int64
+}
+
+func newSampler(s outputs.Sampling) *sampler {
+	if !s.IsEnabled() {
+		return nil
+	}
+	sa := &sampler{rates: make(map[string]uint64, len(s.Rates))}
+	for k, r := range s.Rates {
+		sa.rates[strings.ToLower(k)] = uint64(math.Round(r * sampleScale / 100))
+	}
+	if s.MaxRate > 0 {
+		burst := s.Burst
+		if burst == 0 {
+			burst = max(1, int(s.MaxRate))
+		}
+		sa.limiter = rate.NewLimiter(rate.Limit(s.MaxRate), burst)
+	}
+	return sa
+}
+
+// allow determines if the event is forwarded to the output. Dropped
+// events are accumulated in the per-category counters.
+func (s *sampler) allow(evt *event.Event, d *drops) bool {
+	if evt.ContainsMeta(event.RuleNameKey) {
+		return true
+	}
+	if !s.sample(evt) {
+		d.sampled[evt.Category]++
+		return false
+	}
+	if s.limiter != nil && !s.limiter.Allow() {
+		d.ratelimited[evt.Category]++
+		return false
+	}
+	return true
+}
+
+// sample applies the sampling rate of the event name or category.
+// The process identifier is hashed to keep or drop the complete
+// activity of the process.
+func (s *sampler) sample(evt *event.Event) bool {
+	threshold, ok := s.rates[strings.ToLower(evt.Name)]
+	if !ok {
+		threshold, ok = s.rates[strings.ToLower(string(evt.Category))]
+	}
+	if !ok || threshold >= sampleScale {
+		return true
+	}
+	if threshold == 0 {
+		return false
+	}
+	id := uint64(evt.PID)
+	if evt.PS != nil {
+		id = evt.PS.UUID()
+	}
+	return mix(id)%sampleScale < threshold
+}
+
+// mix is the splitmix64 finalizer that spreads the bits of
+// sequential process identifiers across the hash space.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func newDrops() *drops {
+	return &drops{
+		sampled:     make(map[event.Category]int64),
+		ratelimited: make(map[event.Category]int64),
+	}
+}
+
+// flush publishes the dropped event counters for the output.
+func (d *drops) flush(typ outputs.Type) {
+	for cat, n := range d.sampled {
+		outputSampledEvents.Add(typ.String()+"."+string(cat), n)
+	}
+	for cat, n := range d.ratelimited {
+		outputRateLimitedEvents.Add(typ.String()+"."+string(cat), n)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSampler(t *testing.T) {
+	assert.Nil(t, newSampler(outputs.Sampling{}))
+
+	s := newSampler(outputs.Sampling{Rates: map[string]float64{"ReadFile": 12.5}, MaxRate: 200})
+	require.NotNil(t, s)
+	assert.Equal(t, uint64(1250), s.rates["readfile"])
+	require.NotNil(t, s.limiter)
+	assert.Equal(t, 200, s.limiter.Burst())
+}
+
+func TestSamplerRates(t *testing.T) {
+	s := newSampler(outputs.Sampling{Rates: map[string]float64{"file": 0, "createfile": 100, "registry": 10}})
+	d := newDrops()
+
+	// event name takes precedence over category
+	assert.True(t, s.allow(&event.Event{Name: "CreateFile", Category: event.File, PID: 1}, d))
+	assert.False(t, s.allow(&event.Event{Name: "ReadFile", Category: event.File, PID: 1}, d))
+	// events without the rate are forwarded
+	assert.True(t, s.allow(&event.Event{Name: "CreateProcess", Category: event.Process, PID: 1}, d))
+
+	// rule-matched events are never dropped
+	evt := &event.Event{Name: "ReadFile", Category: event.File, PID: 1}
+	evt.AddMeta(event.RuleNameKey, "Suspicious file access")
+	assert.True(t, s.allow(evt, d))
+
+	// sampling keeps or drops the complete process activity
+	var sampled int
+	for pid := uint32(1); pid <= 1000; pid++ {
+		open := s.allow(&event.Event{Name: "RegOpenKey", Category: event.Registry, PID: pid}, d)
+		query := s.allow(&event.Event{Name: "RegQueryValue", Category: event.Registry, PID: pid}, d)
+		require.Equal(t, open, query)
+		if open {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 100, sampled, 40)
+	assert.Equal(t, int64(1), d.sampled[event.File])
+	assert.Equal(t, int64(2*(1000-sampled)), d.sampled[event.Registry])
+}
+
+func TestSamplerRateLimit(t *testing.T) {
+	c := &mockClient{}
+	o := newOutput(outputs.HTTP, nil, 4, c)
+	o.sampler = newSampler(outputs.Sampling{MaxRate: 1, Burst: 3})
+	dropped := counter(outputRateLimitedEvents, "http.file")
+
+	evts := make([]*event.Event, 0, 10)
+	for i := 0; i < 10; i++ {
+		evts = append(evts, &event.Event{Name: "ReadFile", Category: event.File, PID: 4})
+	}
+	evt := &event.Event{Name: "CreateProcess", Category: event.Process, PID: 4}
+	evt.AddMeta(event.RuleNameKey, "Suspicious process")
+	evts = append(evts, evt)
+	o.submit(event.NewBatch(evts...))
+
+	s := &submitter{outputs: []*output{o}}
+	require.NoError(t, s.shutdown(time.Second))
+	assert.Equal(t, 4, c.published())
+	assert.Equal(t, dropped+7, counter(outputRateLimitedEvents, "http.file"))
+}
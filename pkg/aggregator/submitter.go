@@ -19,39 +19,229 @@
 package aggregator
 
 import (
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+
 	"github.com/rabbitstack/fibratus/pkg/event"
 	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/rabbitstack/fibratus/pkg/util/multierror"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+var (
+	// outputDroppedBatches counts batches dropped because the output queue is full
+	outputDroppedBatches = expvar.NewMap("aggregator.output.dropped.batches")
+	// outputDroppedEvents counts events of batches dropped because the output queue or buffer is full
+	outputDroppedEvents = expvar.NewMap("aggregator.output.dropped.events")
+	// outputFilteredEvents counts events not forwarded to the output because they didn't match the output filter
+	outputFilteredEvents = expvar.NewMap("aggregator.output.filtered.events")
 )
 
+// dropWarnInterval is the minimum interval between warnings about dropped batches
+const dropWarnInterval = time.Minute
+
 // queue defines the type alias for the batch worker queue
 type queue chan *event.Batch
 
-// submitter initializes a group of load balanced output producers.
-type submitter struct {
-	wq      queue
+// Filter selects the events that are forwarded to the output.
+type Filter interface {
+	// Eval returns true if the event is forwarded to the output.
+	Eval(*event.Event) bool
+}
+
+// FilterCompiler compiles the output filter expression.
+type FilterCompiler func(expr string) (Filter, error)
+
+// output is the sink with its own batch queue consumed by
+// a group of load balanced workers. Each output has its own
+// queue, so a slow output can't stall other outputs. If the
+// disk-backed buffer is enabled, batches are written to the
+// buffer instead of the queue. If the output is the only one
+// configured, the queue applies backpressure instead of dropping
+// batches.
+type output struct {
+	typ     outputs.Type
+	filter  Filter
+	sampler *sampler
+	qu      queue
+	block   bool
 	workers []*worker
+	buf     *buffer
+	// warn throttles the warnings about dropped batches
+	warn rate.Sometimes
+}
+
+// submit forwards events of the batch that match the output
+// filter and pass the sampling policy to the output queue. If
+// the queue is full, the batch is dropped.
+func (o *output) submit(b *event.Batch) {
+	if o.filter != nil || o.sampler != nil {
+		evts := make([]*event.Event, 0, len(b.Events))
+		var filtered int64
+		var d *drops
+		if o.sampler != nil {
+			d = newDrops()
+		}
+		for _, evt := range b.Events {
+			if o.filter != nil && !o.filter.Eval(evt) {
+				filtered++
+				continue
+			}
+			if o.sampler != nil && !o.sampler.allow(evt, d) {
+				continue
+			}
+			evts = append(evts, evt)
+		}
+		if filtered > 0 {
+			outputFilteredEvents.Add(o.typ.String(), filtered)
+		}
+		if d != nil {
+			d.flush(o.typ)
+		}
+		if len(evts) == 0 {
+			return
+		}
+		b = event.NewBatch(evts...)
+	}
+	if o.buf != nil {
+		err := o.buf.append(b)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, errBufferFull) {
+			o.drop(b, "output buffer is full")
+		} else {
+			o.drop(b, fmt.Sprintf("unable to write batch to output buffer: %v", err))
+		}
+		return
+	}
+	if o.block {
+		o.qu <- b
+		return
+	}
+	select {
+	case o.qu <- b:
+	default:
+		o.drop(b, "output queue is full")
+	}
 }
 
-func newSubmitter(wq queue, outputConfig outputs.Config) (*submitter, error) {
-	output, err := outputs.Load(outputConfig.Type, outputConfig)
-	if err != nil {
-		return nil, err
+// drop accounts the dropped batch and periodically
+// logs the warning with the total number of dropped
+// batches.
+func (o *output) drop(b *event.Batch, reason string) {
+	outputDroppedBatches.Add(o.typ.String(), 1)
+	outputDroppedEvents.Add(o.typ.String(), int64(b.Len()))
+	o.warn.Do(func() {
+		log.Warnf("%s %s. Dropping batch of %d events. Total dropped batches: %s",
+			o.typ, reason, b.Len(), outputDroppedBatches.Get(o.typ.String()))
+	})
+}
+
+// submitter initializes the outputs and fans out event batches to them.
+type submitter struct {
+	outputs []*output
+}
+
+func newSubmitter(configs []outputs.Config, config Config, compile FilterCompiler) (*submitter, error) {
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	s := &submitter{outputs: make([]*output, 0, len(configs))}
+
+	for _, c := range configs {
+		o := &output{typ: c.Type, sampler: newSampler(c.Sampling), warn: rate.Sometimes{Interval: dropWarnInterval}}
+		if c.Filter != "" {
+			if compile == nil {
+				return nil, fmt.Errorf("%s output filter can't be compiled", c.Type)
+			}
+			var err error
+			o.filter, err = compile(c.Filter)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s output filter: %v", c.Type, err)
+			}
+		}
+		group, err := outputs.Load(c.Type, c)
+		if err != nil {
+			return nil, err
+		}
+		if config.Buffer.Enabled {
+			o.buf, err = newBuffer(c.Type.String(), config.Buffer, queueSize, group.Clients)
+			if err != nil {
+				return nil, err
+			}
+			s.outputs = append(s.outputs, o)
+			continue
+		}
+		o.qu = make(queue, queueSize)
+		o.workers = make([]*worker, len(group.Clients))
+		for i, client := range group.Clients {
+			o.workers[i] = initWorker(o.qu, client)
+		}
+		s.outputs = append(s.outputs, o)
 	}
-	clients := output.Clients
-	workers := make([]*worker, len(clients))
 
-	for i, client := range clients {
-		workers[i] = initWorker(wq, client)
+	// the single output doesn't hold up other outputs,
+	// so the queue blocks the aggregator when the output
+	// can't keep up, rather than losing events
+	if len(s.outputs) == 1 && s.outputs[0].buf == nil {
+		s.outputs[0].block = true
 	}
 
-	return &submitter{wq: wq, workers: workers}, nil
+	return s, nil
 }
 
-func (s *submitter) shutdown() error {
-	for _, w := range s.workers {
-		if err := w.close(); err != nil {
-			return err
+// submit forwards the batch to all outputs.
+func (s *submitter) submit(b *event.Batch) {
+	for _, o := range s.outputs {
+		o.submit(b)
+	}
+}
+
+// shutdown closes output queues and waits for workers to publish
+// pending batches before the output clients are closed. Buffered
+// outputs keep pending batches on disk until the next run.
+func (s *submitter) shutdown(timeout time.Duration) error {
+	for _, o := range s.outputs {
+		if o.buf != nil {
+			o.buf.stop()
+			continue
+		}
+		close(o.qu)
+	}
+	done := make(chan struct{})
+	go func() {
+		for _, o := range s.outputs {
+			for _, w := range o.workers {
+				<-w.done
+			}
+		}
+		close(done)
+	}()
+
+	errs := make([]error, 0)
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		errs = append(errs, fmt.Errorf("fail to flush output queues after %v", timeout))
+	}
+
+	for _, o := range s.outputs {
+		if o.buf != nil {
+			if err := o.buf.close(timeout); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		for _, w := range o.workers {
+			if err := w.close(); err != nil {
+				errs = append(errs, err)
+			}
 		}
 	}
-	return nil
+	return multierror.Wrap(errs...)
 }
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2019-2020 by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregator
+
+import (
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type categoryFilter event.Category
+
+func (f categoryFilter) Eval(e *event.Event) bool { return e.Category == event.Category(f) }
+
+type mockClient struct {
+	mu    sync.Mutex
+	evts  []*event.Event
+	block chan struct{}
+}
+
+func (c *mockClient) Connect() error { return nil }
+func (c *mockClient) Close() error   { return nil }
+func (c *mockClient) Publish(b *event.Batch) error {
+	if c.block != nil {
+		<-c.block
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evts = append(c.evts, b.Events...)
+	return nil
+}
+
+func (c *mockClient) published() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.evts)
+}
+
+func newOutput(typ outputs.Type, filter Filter, size int, client outputs.Client) *output {
+	o := &output{typ: typ, filter: filter, qu: make(queue, size)}
+	o.workers = []*worker{initWorker(o.qu, client)}
+	return o
+}
+
+func droppedBatches(typ outputs.Type) int64 {
+	if v, ok := outputDroppedBatches.Get(typ.String()).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestSubmitterOutputFilters(t *testing.T) {
+	es := &mockClient{}
+	http := &mockClient{}
+	s := &submitter{outputs: []*output{
+		newOutput(outputs.Elasticsearch, nil, 4, es),
+		newOutput(outputs.HTTP, categoryFilter(event.Process), 4, http),
+	}}
+
+	s.submit(event.NewBatch(
+		&event.Event{Type: event.CreateProcess, Category: event.Process},
+		&event.Event{Type: event.CreateFile, Category: event.File},
+		&event.Event{Type: event.ConnectTCPv4, Category: event.Net},
+	))
+	s.submit(event.NewBatch(&event.Event{Type: event.CreateFile, Category: event.File}))
+
+	require.NoError(t, s.shutdown(time.Second))
+	assert.Equal(t, 4, es.published())
+	require.Equal(t, 1, http.published())
+	assert.Equal(t, event.CreateProcess, http.evts[0].Type)
+}
+
+func TestSubmitterSlowOutput(t *testing.T) {
+	fast := &mockClient{}
+	slow := &mockClient{block: make(chan struct{})}
+	s := &submitter{outputs: []*output{
+		newOutput(outputs.Elasticsearch, nil, 16, fast),
+		newOutput(outputs.HTTP, nil, 2, slow),
+	}}
+
+	dropped := droppedBatches(outputs.HTTP)
+	droppedEvents := counter(outputDroppedEvents, outputs.HTTP.String())
+	for i := 0; i < 10; i++ {
+		s.submit(event.NewBatch(&event.Event{Type: event.CreateFile, Category: event.File}))
+	}
+
+	// the slow output doesn't stall the fast output
+	require.Eventually(t, func() bool { return fast.published() == 10 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, 0, slow.published())
+	assert.True(t, droppedBatches(outputs.HTTP) > dropped)
+	assert.Equal(t, droppedBatches(outputs.HTTP)-dropped, counter(outputDroppedEvents, outputs.HTTP.String())-droppedEvents)
+
+	close(slow.block)
+	require.NoError(t, s.shutdown(time.Second))
+	assert.True(t, slow.published() > 0 && slow.published() < 10)
+}
+
+func TestSubmitterSingleOutputBackpressure(t *testing.T) {
+	c := &mockClient{block: make(chan struct{})}
+	o := newOutput(outputs.Kafka, nil, 1, c)
+	o.block = true
+	s := &submitter{outputs: []*output{o}}
+
+	dropped := droppedBatches(outputs.Kafka)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			s.submit(event.NewBatch(&event.Event{Type: event.CreateFile, Category: event.File}))
+		}
+	}()
+
+	// the submitter blocks while the output is stalled
+	select {
+	case <-done:
+		t.Fatal("expected the submitter to block")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	close(c.block)
+	<-done
+	require.NoError(t, s.shutdown(time.Second))
+	assert.Equal(t, 5, c.published())
+	assert.Equal(t, dropped, droppedBatches(outputs.Kafka))
+}
@@ -19,6 +19,7 @@
 package aggregator
 
 import (
+	"errors"
 	"expvar"
 	"github.com/rabbitstack/fibratus/pkg/outputs"
 	log "github.com/sirupsen/logrus"
@@ -28,21 +29,52 @@ import (
 // maxBackoff determines the maximum exponential backoff wait time before reconnecting the client
 const maxBackoff = time.Minute
 
-var clientPublishErrors = expvar.NewInt("aggregator.worker.client.publish.errors")
+// initialBackoff is the initial backoff wait time before reconnecting the client
+const initialBackoff = time.Second * 2
+
+var (
+	clientPublishErrors = expvar.NewInt("aggregator.worker.client.publish.errors")
+	clientReconnects    = expvar.NewInt("aggregator.worker.client.reconnects")
+)
 
 type worker struct {
 	qu      queue
 	client  outputs.Client
 	backoff time.Duration
+	// done is closed when the worker drains the queue
+	done chan struct{}
 }
 
 func initWorker(q queue, client outputs.Client) *worker {
-	w := &worker{qu: q, client: client, backoff: time.Second * 2}
+	w := &worker{qu: q, client: client, backoff: initialBackoff, done: make(chan struct{})}
 	go w.run()
 	return w
 }
 
 func (w *worker) run() {
+	defer close(w.done)
+	w.connect()
+	for batch := range w.qu {
+		err := w.client.Publish(batch)
+		if errors.Is(err, outputs.ErrConnectionLost) {
+			// the client dropped the connection. Reconnect
+			// and try to publish the batch again
+			log.Warnf("client lost connection: %v. Reconnecting...", err)
+			clientReconnects.Add(1)
+			w.connect()
+			err = w.client.Publish(batch)
+		}
+		if err != nil {
+			clientPublishErrors.Add(1)
+			log.Warnf("couldn't publish batch to client: %v", err)
+		}
+	}
+}
+
+// connect connects the client. If the connection can't be
+// established, the connection attempt is retried using the
+// exponential backoff reconnect strategy.
+func (w *worker) connect() {
 	for {
 		err := w.client.Connect()
 		if err != nil {
@@ -55,14 +87,9 @@ func (w *worker) run() {
 			<-time.After(w.backoff)
 			continue
 		}
+		w.backoff = initialBackoff
 		break
 	}
-	for batch := range w.qu {
-		if err := w.client.Publish(batch); err != nil {
-			clientPublishErrors.Add(1)
-			log.Warnf("couldn't publish batch to client: %v", err)
-		}
-	}
 }
 
 func (w *worker) close() error {
@@ -19,7 +19,9 @@
 package aggregator
 
 import (
+	"fmt"
 	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
@@ -125,3 +127,37 @@ func TestConnectClientBackoff(t *testing.T) {
 
 	assert.Equal(t, 2, client.published)
 }
+
+type flakyClient struct {
+	connects  int
+	published int
+	lost      bool
+	wait      chan struct{}
+}
+
+func (c *flakyClient) Connect() error { c.connects++; return nil }
+func (c *flakyClient) Close() error   { return nil }
+func (c *flakyClient) Publish(b *event.Batch) error {
+	if !c.lost {
+		c.lost = true
+		return fmt.Errorf("%w: broken pipe", outputs.ErrConnectionLost)
+	}
+	c.published++
+	c.wait <- struct{}{}
+	return nil
+}
+
+func TestReconnectClientOnConnectionLost(t *testing.T) {
+	q := make(chan *event.Batch, 1)
+	q <- &event.Batch{}
+
+	client := &flakyClient{wait: make(chan struct{}, 1)}
+
+	w := initWorker(q, client)
+	defer w.close()
+
+	<-client.wait
+
+	assert.Equal(t, 2, client.connects)
+	assert.Equal(t, 1, client.published)
+}
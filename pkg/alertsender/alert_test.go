@@ -126,3 +126,30 @@ func TestAlertJSON(t *testing.T) {
 
 	require.Equal(t, expectedJSON, string(b))
 }
+
+type sliceRecorder struct {
+	alerts []Alert
+}
+
+func (r *sliceRecorder) Record(alert Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestRecord(t *testing.T) {
+	r := &sliceRecorder{}
+	// alerts are dropped if the recorder is not set
+	Record(NewAlert("LSASS memory dump", "", nil, High))
+
+	SetRecorder(r)
+	Record(NewAlert("Suspicious DLL loaded", "", nil, Critical))
+	Record(NewAlert("Credential discovery via VaultCmd.exe", "", nil, Normal))
+	CloseRecorder()
+
+	require.Len(t, r.alerts, 2)
+	require.Equal(t, "Suspicious DLL loaded", r.alerts[0].Title)
+
+	// alerts are dropped after the recorder is closed
+	Record(NewAlert("LSASS memory dump", "", nil, High))
+	require.Len(t, r.alerts, 2)
+}
@@ -18,8 +18,58 @@
 
 package alertsender
 
+import "time"
+
 // Config is the container for the alert sender configuration structure.
 type Config struct {
-	Type   Type
+	Type Type
+	// Name identifies the alert sender instance. Multiple instances
+	// of the same sender type are declared with the type:name key,
+	// e.g. mail:credential-team. If empty, the sender type name is
+	// used.
+	Name   string
 	Sender interface{}
+	// Spool contains the settings of the on-disk
+	// delivery queue of the alert sender instance.
+	Spool SpoolConfig
+}
+
+// SpoolConfig contains the settings of the alert sender delivery spool.
+type SpoolConfig struct {
+	// Enabled indicates if alerts are spooled to disk before they are delivered.
+	Enabled bool `mapstructure:"enabled"`
+	// Path is the directory where spooled alerts are written.
+	Path string `mapstructure:"path"`
+	// MaxSize is the maximum size of the spool in megabytes. The
+	// oldest alerts are dropped when the size is exceeded.
+	MaxSize int `mapstructure:"max-size"`
+	// MaxAge is the maximum period the alert is retained in the spool.
+	MaxAge time.Duration `mapstructure:"max-age"`
+	// RetryInterval is the initial interval between delivery retries.
+	RetryInterval time.Duration `mapstructure:"retry-interval"`
+	// MaxRetryInterval caps the exponential backoff between delivery retries.
+	MaxRetryInterval time.Duration `mapstructure:"max-retry-interval"`
+	// MaxRetries is the maximum number of delivery retries of the alert
+	// before it is dropped from the spool. Zero retries the alert until
+	// it expires.
+	MaxRetries int `mapstructure:"max-retries"`
+}
+
+// DefaultSpoolConfig returns the spool config with default settings.
+func DefaultSpoolConfig() SpoolConfig {
+	return SpoolConfig{
+		MaxSize:          50,
+		MaxAge:           time.Hour * 24,
+		RetryInterval:    time.Second * 5,
+		MaxRetryInterval: time.Minute * 5,
+		MaxRetries:       10,
+	}
+}
+
+// InstanceName returns the name of the alert sender instance.
+func (c Config) InstanceName() string {
+	if c.Name == "" {
+		return c.Type.String()
+	}
+	return c.Name
 }
@@ -18,7 +18,11 @@
 
 package mail
 
-import "github.com/spf13/pflag"
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
 
 const (
 	host        = "alertsenders.mail.host"
@@ -30,8 +34,24 @@ const (
 	enabled     = "alertsenders.mail.enabled"
 	contentType = "alertsenders.mail.content-type"
 	useTemplate = "alertsenders.mail.use-template"
+
+	digestEnabled   = "alertsenders.mail.digest.enabled"
+	digestInterval  = "alertsenders.mail.digest.interval"
+	digestMaxAlerts = "alertsenders.mail.digest.max-alerts"
 )
 
+// DigestConfig contains the settings of the digest mode. In digest mode,
+// alerts are buffered and sent in a single email when the interval elapses
+// or the maximum number of buffered alerts is reached.
+type DigestConfig struct {
+	// Enabled indicates if alerts are sent in digest emails.
+	Enabled bool `mapstructure:"enabled"`
+	// Interval specifies how often the digest email is sent.
+	Interval time.Duration `mapstructure:"interval"`
+	// MaxAlerts is the number of buffered alerts that triggers the digest email.
+	MaxAlerts int `mapstructure:"max-alerts"`
+}
+
 // Config contains the configuration for the mail alert sender.
 type Config struct {
 	// Host is the host of the SMTP server.
@@ -53,6 +73,8 @@ type Config struct {
 	// UseTemplate indicates if the alert is rendered with HTML template.
 	// If set to false, the plain text email is sent instead.
 	UseTemplate bool `mapstructure:"use-template"`
+	// Digest contains the settings of the digest mode.
+	Digest DigestConfig `mapstructure:"digest"`
 }
 
 // AddFlags registers persistent flags.
@@ -66,4 +88,7 @@ func AddFlags(flags *pflag.FlagSet) {
 	flags.Bool(enabled, false, "Indicates whether mail alert sender is enabled")
 	flags.String(contentType, "text/html", "Represents the email body content type")
 	flags.Bool(useTemplate, true, "Indicates if the alert is rendered with HTML template")
+	flags.Bool(digestEnabled, false, "Indicates if alerts are buffered and sent in a single digest email")
+	flags.Duration(digestInterval, time.Minute*15, "Specifies how often the digest email is sent")
+	flags.Int(digestMaxAlerts, 100, "Specifies the number of buffered alerts that triggers the digest email")
 }
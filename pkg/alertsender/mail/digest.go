@@ -0,0 +1,248 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mail
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultDigestInterval is the digest interval used if not specified in the config
+	defaultDigestInterval = time.Minute * 15
+	// defaultDigestMaxAlerts is the number of alerts in the digest used if not specified in the config
+	defaultDigestMaxAlerts = 100
+	// maxTopProcesses is the maximum number of top processes rendered for each digest group
+	maxTopProcesses = 5
+	// digestBufferFactor bounds the number of alerts buffered while the digest can't be sent
+	// to the multiple of the maximum number of alerts in the digest
+	digestBufferFactor = 10
+)
+
+// bufferedAlert is the alert buffered in the digest along with the time it was received.
+type bufferedAlert struct {
+	alertsender.Alert
+	seen time.Time
+}
+
+// digest buffers alerts and periodically flushes them via the send function.
+// Alerts are also flushed when the maximum number of buffered alerts is reached.
+// If the digest can't be sent, alerts are kept in the buffer and sent with the
+// next digest. The buffer is bounded, so the oldest alerts are dropped if the
+// digest keeps failing.
+type digest struct {
+	mu        sync.Mutex
+	alerts    []bufferedAlert
+	maxAlerts int
+	send      func([]bufferedAlert) error
+	// failed indicates the last digest couldn't be
+	// sent, so the full buffer is not flushed until
+	// the next digest interval
+	failed bool
+
+	ticker *time.Ticker
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newDigest(c DigestConfig, send func([]bufferedAlert) error) *digest {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = defaultDigestInterval
+	}
+	maxAlerts := c.MaxAlerts
+	if maxAlerts <= 0 {
+		maxAlerts = defaultDigestMaxAlerts
+	}
+	d := &digest{
+		alerts:    make([]bufferedAlert, 0),
+		maxAlerts: maxAlerts,
+		send:      send,
+		ticker:    time.NewTicker(interval),
+		quit:      make(chan struct{}),
+	}
+	d.wg.Add(1)
+	go d.run()
+	return d
+}
+
+func (d *digest) run() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.ticker.C:
+			if err := d.flush(); err != nil {
+				log.Warnf("unable to send alert digest email: %v", err)
+			}
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// add buffers the alert. If the maximum number of
+// buffered alerts is reached, the digest is flushed.
+// The alert remains buffered if the digest can't be
+// sent, so the error is not propagated to the caller.
+func (d *digest) add(alert alertsender.Alert) error {
+	d.mu.Lock()
+	d.alerts = append(d.alerts, bufferedAlert{Alert: alert, seen: time.Now()})
+	d.trim()
+	full := len(d.alerts) >= d.maxAlerts && !d.failed
+	d.mu.Unlock()
+	if full {
+		if err := d.flush(); err != nil {
+			log.Warnf("unable to send alert digest email: %v", err)
+		}
+	}
+	return nil
+}
+
+// flush sends all buffered alerts. If the digest
+// can't be sent, alerts are put back in the buffer.
+func (d *digest) flush() error {
+	d.mu.Lock()
+	alerts := d.alerts
+	d.alerts = make([]bufferedAlert, 0)
+	d.mu.Unlock()
+	if len(alerts) == 0 {
+		return nil
+	}
+	err := d.send(alerts)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failed = err != nil
+	if err != nil {
+		d.alerts = append(alerts, d.alerts...)
+		d.trim()
+	}
+	return err
+}
+
+// trim drops the oldest alerts exceeding the buffer
+// capacity. The caller must hold the lock.
+func (d *digest) trim() {
+	if n := len(d.alerts) - d.maxAlerts*digestBufferFactor; n > 0 {
+		log.Warnf("alert digest buffer is full. Dropping %d oldest alert(s)", n)
+		d.alerts = d.alerts[n:]
+	}
+}
+
+// close stops the flush loop and sends the remaining alerts.
+func (d *digest) close() error {
+	d.ticker.Stop()
+	close(d.quit)
+	d.wg.Wait()
+	return d.flush()
+}
+
+// digestGroup aggregates alerts of the same rule and severity.
+type digestGroup struct {
+	Title        string
+	Severity     alertsender.Severity
+	Labels       map[string]string
+	Count        int
+	FirstSeen    time.Time
+	LastSeen     time.Time
+	TopProcesses []processCount
+}
+
+// processCount is the number of alerts involving the process.
+type processCount struct {
+	Name  string
+	Count int
+}
+
+// groupAlerts groups buffered alerts by rule and severity. Groups are
+// sorted by severity and the number of alerts in descending order.
+func groupAlerts(alerts []bufferedAlert) []digestGroup {
+	type key struct {
+		id       string
+		title    string
+		severity alertsender.Severity
+	}
+	var (
+		groups = make(map[key]*digestGroup)
+		procs  = make(map[key]map[string]int)
+		keys   = make([]key, 0)
+	)
+	for _, alert := range alerts {
+		k := key{alert.ID, alert.Title, alert.Severity}
+		g, ok := groups[k]
+		if !ok {
+			g = &digestGroup{
+				Title:     alert.Title,
+				Severity:  alert.Severity,
+				Labels:    alert.Labels,
+				FirstSeen: alert.seen,
+			}
+			groups[k] = g
+			procs[k] = make(map[string]int)
+			keys = append(keys, k)
+		}
+		g.Count++
+		if alert.seen.Before(g.FirstSeen) {
+			g.FirstSeen = alert.seen
+		}
+		if alert.seen.After(g.LastSeen) {
+			g.LastSeen = alert.seen
+		}
+		// count each process once per alert
+		seen := make(map[string]bool)
+		for _, evt := range alert.Events {
+			if evt.PS == nil || seen[evt.PS.Name] {
+				continue
+			}
+			seen[evt.PS.Name] = true
+			procs[k][evt.PS.Name]++
+		}
+	}
+
+	digest := make([]digestGroup, 0, len(keys))
+	for _, k := range keys {
+		g := groups[k]
+		for name, n := range procs[k] {
+			g.TopProcesses = append(g.TopProcesses, processCount{Name: name, Count: n})
+		}
+		slices.SortFunc(g.TopProcesses, func(a, b processCount) int {
+			if c := cmp.Compare(b.Count, a.Count); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.Name, b.Name)
+		})
+		if len(g.TopProcesses) > maxTopProcesses {
+			g.TopProcesses = g.TopProcesses[:maxTopProcesses]
+		}
+		digest = append(digest, *g)
+	}
+	slices.SortStableFunc(digest, func(a, b digestGroup) int {
+		if c := cmp.Compare(b.Severity, a.Severity); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Count, a.Count)
+	})
+
+	return digest
+}
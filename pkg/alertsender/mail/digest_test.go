@@ -0,0 +1,148 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestFlush(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		digests [][]bufferedAlert
+	)
+	send := func(alerts []bufferedAlert) error {
+		mu.Lock()
+		defer mu.Unlock()
+		digests = append(digests, alerts)
+		return nil
+	}
+
+	d := newDigest(DigestConfig{Interval: time.Hour, MaxAlerts: 3}, send)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, d.add(alertsender.NewAlert("Suspicious DLL loaded by Microsoft Office process", "", nil, alertsender.Medium)))
+	}
+	// the max alerts count triggers the digest
+	require.Len(t, digests, 1)
+	assert.Len(t, digests[0], 3)
+
+	// remaining alerts are flushed on close
+	require.NoError(t, d.close())
+	require.Len(t, digests, 2)
+	assert.Len(t, digests[1], 1)
+}
+
+func TestDigestFlushInterval(t *testing.T) {
+	flushed := make(chan []bufferedAlert, 1)
+	d := newDigest(DigestConfig{Interval: time.Millisecond * 20, MaxAlerts: 100}, func(alerts []bufferedAlert) error {
+		flushed <- alerts
+		return nil
+	})
+	defer d.close()
+
+	require.NoError(t, d.add(alertsender.NewAlert("Suspicious DLL loaded by Microsoft Office process", "", nil, alertsender.Medium)))
+
+	select {
+	case alerts := <-flushed:
+		assert.Len(t, alerts, 1)
+	case <-time.After(time.Second):
+		t.Fatal("digest not flushed")
+	}
+}
+
+func TestDigestSendFailure(t *testing.T) {
+	var (
+		fail    = true
+		digests [][]bufferedAlert
+	)
+	send := func(alerts []bufferedAlert) error {
+		if fail {
+			return errors.New("connection refused")
+		}
+		digests = append(digests, alerts)
+		return nil
+	}
+
+	d := newDigest(DigestConfig{Interval: time.Hour, MaxAlerts: 2}, send)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, d.add(alertsender.NewAlert(fmt.Sprintf("alert %d", i), "", nil, alertsender.Medium)))
+	}
+	// alerts are kept in the buffer if the digest can't be sent
+	require.Len(t, d.alerts, 3)
+	assert.Equal(t, "alert 0", d.alerts[0].Title)
+
+	// the buffer is bounded while the digest keeps failing
+	for i := 3; i < 25; i++ {
+		require.NoError(t, d.add(alertsender.NewAlert(fmt.Sprintf("alert %d", i), "", nil, alertsender.Medium)))
+	}
+	require.Len(t, d.alerts, 2*digestBufferFactor)
+	assert.Equal(t, "alert 5", d.alerts[0].Title)
+
+	fail = false
+	require.NoError(t, d.close())
+	require.Len(t, digests, 1)
+	assert.Len(t, digests[0], 2*digestBufferFactor)
+}
+
+func TestGroupAlerts(t *testing.T) {
+	now := time.Now()
+	alert := func(id, title string, severity alertsender.Severity, seen time.Time, procs ...string) bufferedAlert {
+		evts := make([]*event.Event, 0, len(procs))
+		for _, proc := range procs {
+			evts = append(evts, &event.Event{PS: &pstypes.PS{Name: proc}})
+		}
+		a := alertsender.NewAlertWithEvents(title, "", nil, severity, evts)
+		a.ID = id
+		return bufferedAlert{Alert: a, seen: seen}
+	}
+
+	groups := groupAlerts([]bufferedAlert{
+		alert("1", "Suspicious DLL loaded by Microsoft Office process", alertsender.Medium, now, "winword.exe"),
+		alert("2", "LSASS memory dumping via legitimate or offensive tools", alertsender.Critical, now.Add(time.Second), "rundll32.exe", "rundll32.exe"),
+		alert("1", "Suspicious DLL loaded by Microsoft Office process", alertsender.Medium, now.Add(time.Minute), "excel.exe"),
+		alert("1", "Suspicious DLL loaded by Microsoft Office process", alertsender.Medium, now.Add(time.Second*30), "winword.exe"),
+		alert("1", "Suspicious DLL loaded by Microsoft Office process", alertsender.High, now.Add(time.Second*2), "powerpnt.exe"),
+	})
+
+	require.Len(t, groups, 3)
+
+	assert.Equal(t, "LSASS memory dumping via legitimate or offensive tools", groups[0].Title)
+	assert.Equal(t, 1, groups[0].Count)
+	assert.Equal(t, []processCount{{"rundll32.exe", 1}}, groups[0].TopProcesses)
+
+	assert.Equal(t, alertsender.High, groups[1].Severity)
+
+	assert.Equal(t, "Suspicious DLL loaded by Microsoft Office process", groups[2].Title)
+	assert.Equal(t, alertsender.Medium, groups[2].Severity)
+	assert.Equal(t, 3, groups[2].Count)
+	assert.Equal(t, now, groups[2].FirstSeen)
+	assert.Equal(t, now.Add(time.Minute), groups[2].LastSeen)
+	assert.Equal(t, []processCount{{"winword.exe", 2}, {"excel.exe", 1}}, groups[2].TopProcesses)
+}
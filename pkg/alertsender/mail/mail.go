@@ -19,6 +19,10 @@
 package mail
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/rabbitstack/fibratus/pkg/alertsender"
 	"gopkg.in/gomail.v2"
 )
@@ -26,6 +30,7 @@ import (
 type mail struct {
 	dialer *gomail.Dialer
 	c      Config
+	digest *digest
 }
 
 func init() {
@@ -39,26 +44,53 @@ func makeSender(config alertsender.Config) (alertsender.Sender, error) {
 		return nil, alertsender.ErrInvalidConfig(alertsender.Mail)
 	}
 	dialer := gomail.NewDialer(c.Host, c.Port, c.User, c.Pass)
-	return &mail{dialer: dialer, c: c}, nil
+	m := &mail{dialer: dialer, c: c}
+	if c.Digest.Enabled {
+		m.digest = newDigest(c.Digest, m.sendDigest)
+	}
+	return m, nil
 }
 
 func (s mail) Send(alert alertsender.Alert) error {
-	sender, err := s.dialer.Dial()
-	if err != nil {
-		return err
+	if s.digest != nil {
+		return s.digest.add(alert)
 	}
-	defer sender.Close()
 	msg, err := s.composeMessage(s.c.From, s.c.To, alert)
 	if err != nil {
 		return err
 	}
-	return gomail.Send(sender, msg)
+	return s.send(msg)
 }
 
 func (s mail) Type() alertsender.Type { return alertsender.Mail }
-func (s mail) Shutdown() error        { return nil }
 func (s mail) SupportsMarkdown() bool { return true }
 
+// Shutdown sends the digest email with the remaining buffered alerts.
+func (s mail) Shutdown() error {
+	if s.digest != nil {
+		return s.digest.close()
+	}
+	return nil
+}
+
+func (s mail) send(msg *gomail.Message) error {
+	sender, err := s.dialer.Dial()
+	if err != nil {
+		return err
+	}
+	defer sender.Close()
+	return gomail.Send(sender, msg)
+}
+
+// sendDigest sends a single email summarizing buffered alerts.
+func (s mail) sendDigest(alerts []bufferedAlert) error {
+	msg, err := s.composeDigestMessage(s.c.From, s.c.To, alerts)
+	if err != nil {
+		return err
+	}
+	return s.send(msg)
+}
+
 func (s mail) composeMessage(from string, to []string, alert alertsender.Alert) (*gomail.Message, error) {
 	msg := gomail.NewMessage()
 	msg.SetHeader("From", from)
@@ -79,3 +111,40 @@ func (s mail) composeMessage(from string, to []string, alert alertsender.Alert)
 
 	return msg, nil
 }
+
+func (s mail) composeDigestMessage(from string, to []string, alerts []bufferedAlert) (*gomail.Message, error) {
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", from)
+	msg.SetHeader("To", to...)
+	if len(alerts) == 1 {
+		msg.SetHeader("Subject", "Alert digest: 1 alert")
+	} else {
+		msg.SetHeader("Subject", fmt.Sprintf("Alert digest: %d alerts", len(alerts)))
+	}
+
+	if !s.c.UseTemplate {
+		var b strings.Builder
+		for _, g := range groupAlerts(alerts) {
+			b.WriteString(fmt.Sprintf("%s\nSeverity: %s\nCount: %d\nFirst seen: %s\nLast seen: %s\n",
+				g.Title, g.Severity, g.Count, g.FirstSeen.Format(time.RFC3339), g.LastSeen.Format(time.RFC3339)))
+			if len(g.TopProcesses) > 0 {
+				procs := make([]string, 0, len(g.TopProcesses))
+				for _, p := range g.TopProcesses {
+					procs = append(procs, fmt.Sprintf("%s (%d)", p.Name, p.Count))
+				}
+				b.WriteString("Top processes: " + strings.Join(procs, ", ") + "\n")
+			}
+			b.WriteByte('\n')
+		}
+		msg.SetBody("text/plain", b.String())
+		return msg, nil
+	}
+
+	body, err := renderDigestHTMLTemplate(alerts)
+	if err != nil {
+		return nil, err
+	}
+	msg.SetBody(s.c.ContentType, body)
+
+	return msg, nil
+}
@@ -45,6 +45,39 @@ func renderHTMLTemplate(alert alertsender.Alert) (string, error) {
 	}
 
 	_ = data.Alert.MDToHTML()
+
+	return render("alert", htmlTemplate, data)
+}
+
+// renderDigestHTMLTemplate produces HTML template for the alert
+// digest. Alerts are grouped by rule and severity.
+func renderDigestHTMLTemplate(alerts []bufferedAlert) (string, error) {
+	data := struct {
+		Groups   []digestGroup
+		Total    int
+		Since    time.Time
+		Until    time.Time
+		Hostname string
+		Version  string
+	}{
+		Groups:   groupAlerts(alerts),
+		Total:    len(alerts),
+		Hostname: hostname.Get(),
+		Version:  version.Get(),
+	}
+	for _, g := range data.Groups {
+		if data.Since.IsZero() || g.FirstSeen.Before(data.Since) {
+			data.Since = g.FirstSeen
+		}
+		if g.LastSeen.After(data.Until) {
+			data.Until = g.LastSeen
+		}
+	}
+
+	return render("digest", digestTemplate, data)
+}
+
+func render(name, text string, data any) (string, error) {
 	funcmap := sprig.TxtFuncMap()
 
 	// redefine hasKey to work on string map values
@@ -54,7 +87,7 @@ func renderHTMLTemplate(alert alertsender.Alert) (string, error) {
 		}
 		return false
 	}
-	tmpl, err := template.New("alert").Funcs(funcmap).Parse(htmlTemplate)
+	tmpl, err := template.New(name).Funcs(funcmap).Parse(text)
 	if err != nil {
 		return "", err
 	}
@@ -251,3 +251,29 @@ func TestRenderHTMLTemplate(t *testing.T) {
 	require.NotNil(t, alertTitle)
 	assert.Equal(t, "Suspicious access to Windows Vault files", htmlquery.InnerText(alertTitle))
 }
+
+func TestRenderDigestHTMLTemplate(t *testing.T) {
+	now := time.Now()
+	ps := &pstypes.PS{PID: 2436, Name: "rundll32.exe"}
+	alerts := []bufferedAlert{
+		{Alert: alertsender.Alert{ID: "1", Title: "Suspicious access to Windows Vault files", Severity: alertsender.Critical, Labels: map[string]string{"tactic.name": "Credential Access", "tactic.ref": "https://attack.mitre.org/tactics/TA0006/"}, Events: []*event.Event{{PS: ps}}}, seen: now},
+		{Alert: alertsender.Alert{ID: "2", Title: "Unsigned DLL loaded by Microsoft Office process", Severity: alertsender.Medium}, seen: now.Add(time.Second)},
+		{Alert: alertsender.Alert{ID: "1", Title: "Suspicious access to Windows Vault files", Severity: alertsender.Critical, Events: []*event.Event{{PS: ps}}}, seen: now.Add(time.Minute)},
+	}
+
+	out, err := renderDigestHTMLTemplate(alerts)
+	require.NoError(t, err)
+	doc, err := htmlquery.Parse(strings.NewReader(out))
+	require.NoError(t, err)
+
+	title := htmlquery.FindOne(doc, "//h1")
+	require.NotNil(t, title)
+	assert.Equal(t, "3 alerts from 2 rules", htmlquery.InnerText(title))
+
+	rules := htmlquery.Find(doc, "//h2")
+	require.Len(t, rules, 2)
+	assert.Equal(t, "Suspicious access to Windows Vault files", htmlquery.InnerText(rules[0]))
+	assert.Equal(t, "Unsigned DLL loaded by Microsoft Office process", htmlquery.InnerText(rules[1]))
+	assert.Contains(t, out, "rundll32.exe (2)")
+	assert.Contains(t, out, "Credential Access")
+}
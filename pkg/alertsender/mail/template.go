@@ -259,3 +259,109 @@ var htmlTemplate = `
 </body>
 </html>
 `
+
+var digestTemplate = `
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN"
+  "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+  <meta name="viewport" content="width=device-width, initial-scale=1.0"/>
+  <meta http-equiv="Content-Type" content="text/html; charset=UTF-8"/>
+  <style>
+     @media only screen and (max-width: 600px) {
+      .alert-body-inner,
+      .alert-footer {
+        width: 100% !important;
+      }
+    }
+  </style>
+  <!--[if (gte mso 9)|(IE)]>
+    <style type="text/css">
+        table {border-collapse: collapse;}
+    </style>
+  <![endif]-->
+  <title></title>
+</head>
+<body style="font-family: 'Noto Sans', Tahoma, Roboto, 'Open Sans', Arial, 'Helvetica Neue', Helvetica, sans-serif; -webkit-box-sizing: border-box; box-sizing: border-box; width: 100% !important; height: 100%; margin: 0; line-height: 1.4; background-color: #f7f7f7; color: #74787E; -webkit-text-size-adjust: none; border-radius: 4px">
+<table style="width: 100%; margin: 0; padding: 0; background-color: #F2F4F6; border-collapse: collapse;" width="100%" cellpadding="0" cellspacing="0">
+  <tr>
+    <td>
+      <table class="alert-body-inner" style="width: 570px; margin: 0 auto; padding: 0; border-collapse: collapse;" align="center" width="570" cellpadding="0" cellspacing="0">
+        <tr>
+          <td style="padding: 35px; color: #74787E; font-size: 15px; line-height: 18px;">
+            <p style="font-size: 12px; color: #C5C5C5; line-height: 0.5em;">
+              Triggered between <span style="color: #6f7578;">{{ .Since | date "Mon Jan 02 2006 03:04:05 PM" }}</span> and <span style="color: #6f7578;">{{ .Until | date "Mon Jan 02 2006 03:04:05 PM" }}</span> in <span
+              style="color: #6f7578;"> {{ .Hostname }} </span> host
+            </p>
+            <h1 style="font-size: 16px; font-weight: bold; color: #2F3133; text-decoration: none; text-shadow: 0 1px 0 white;">{{ .Total }} {{ if eq .Total 1 }}alert{{ else }}alerts{{ end }} from {{ len .Groups }} {{ if eq (len .Groups) 1 }}rule{{ else }}rules{{ end }}</h1>
+          </td>
+        </tr>
+        {{- range .Groups }}
+        <tr>
+          <td style="padding: 0px 35px 25px 35px;">
+            <table style="width: 100%; margin: 0; padding: 0; border-collapse: collapse; background-color: #ffffff; border-radius: 5px;" width="100%" cellpadding="0" cellspacing="0">
+              <tr>
+                <td style="padding: 15px 20px;">
+                  {{ $severityColor := "#fcd834" }}
+                  {{- if eq .Severity.String "low" }}
+                  {{ $severityColor = "#29b33e" }}
+                  {{- else if eq .Severity.String "medium" }}
+                  {{ $severityColor = "#fcd834" }}
+                  {{- else }}
+                  {{ $severityColor = "#fa7975" }}
+                  {{- end }}
+                  <h2 style="font-size: 14px; font-weight: bold; color: #2F3133; margin: 0 0 8px 0;">{{ .Title }}</h2>
+                  <div style="margin-bottom: 8px;">
+                    <span style="height: 8px; width: 8px; border-radius: 50%; display: inline-block; background-color: {{ $severityColor }}"></span>
+                    <p style="font-size: 12px; white-space: pre-wrap; color: #6f7578; line-height: 0.5em; display: inline; margin-left: 2px">{{ .Severity.String | title }} Severity</p>
+                    {{ if hasKey .Labels "tactic.name" }}
+                    <div class="tag" style="display: inline-block; border-radius: 5px; color: #404243; font-size: .7rem; margin: 2px 2px; padding: 2px 5px; white-space: pre-wrap; font-weight: 600; background-color: #bad1fb;"><a style="text-decoration: none; color: inherit;" href="{{ index .Labels "tactic.ref"}}">{{ index .Labels "tactic.name"}}</a></div>
+                    {{ end }}
+                    {{ if hasKey .Labels "technique.name" }}
+                    <div class="tag" style="display: inline-block; border-radius: 5px; color: #404243; font-size: .7rem; margin: 2px 2px; padding: 2px 5px; white-space: pre-wrap; font-weight: 600; background-color: #84cad7;"><a style="text-decoration: none; color: inherit;" href="{{ index .Labels "technique.ref"}}">{{ index .Labels "technique.name"}}</a></div>
+                    {{ end }}
+                  </div>
+                  <table style="width: 100%; border-collapse: collapse; font-size: 13px;" width="100%" cellpadding="0" cellspacing="0">
+                    <tr>
+                      <td style="width: 30%; padding: 2px 0; color: #626567;">Count</td>
+                      <td style="padding: 2px 0; color: #74787E; font-weight: bold;">{{ .Count }}</td>
+                    </tr>
+                    <tr>
+                      <td style="width: 30%; padding: 2px 0; color: #626567;">First seen</td>
+                      <td style="padding: 2px 0; color: #74787E; font-weight: bold;">{{ .FirstSeen | date "03:04:05 PM" }}</td>
+                    </tr>
+                    <tr>
+                      <td style="width: 30%; padding: 2px 0; color: #626567;">Last seen</td>
+                      <td style="padding: 2px 0; color: #74787E; font-weight: bold;">{{ .LastSeen | date "03:04:05 PM" }}</td>
+                    </tr>
+                    {{- if .TopProcesses }}
+                    <tr>
+                      <td style="width: 30%; padding: 2px 0; color: #626567; vertical-align: top;">Top processes</td>
+                      <td style="padding: 2px 0;">
+                        {{- range .TopProcesses }}
+                        <p style="margin: 2px 4px 2px 0px; font-size: 12px; color: #74787E; font-weight: bold; background: #fed5a0; display: inline-block; border-radius: 5px; padding: 2px 5px; white-space: pre-wrap;">{{ .Name }} ({{ .Count }})</p>
+                        {{- end }}
+                      </td>
+                    </tr>
+                    {{- end }}
+                  </table>
+                </td>
+              </tr>
+            </table>
+          </td>
+        </tr>
+        {{- end }}
+      </table>
+    </td>
+  </tr>
+  <tr style="margin: 0 auto; padding: 0; text-align: center; background: #f7f7f7;">
+    <td style="padding: 5px;">
+      <p style="font-size: 9px; text-align: center;">
+        This email was automatically generated by Fibratus {{ .Version }}
+      </p>
+    </td>
+  </tr>
+</table>
+</body>
+</html>
+`
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pagerduty
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	enabled     = "alertsenders.pagerduty.enabled"
+	url         = "alertsenders.pagerduty.url"
+	routingKey  = "alertsenders.pagerduty.routing-key"
+	dedupFields = "alertsenders.pagerduty.dedup-fields"
+	autoResolve = "alertsenders.pagerduty.auto-resolve"
+	timeout     = "alertsenders.pagerduty.timeout"
+)
+
+// Config contains the configuration for the PagerDuty alert sender.
+type Config struct {
+	// Enabled indicates whether PagerDuty alert sender is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// URL is the Events API v2 endpoint.
+	URL string `mapstructure:"url"`
+	// RoutingKey is the integration key of the PagerDuty service.
+	RoutingKey string `mapstructure:"routing-key"`
+	// DedupFields contains the event fields that along with the
+	// rule identifier make up the incident deduplication key.
+	DedupFields []string `mapstructure:"dedup-fields"`
+	// SeverityMapping maps alert severities to PagerDuty severities.
+	SeverityMapping map[string]string `mapstructure:"severity-mapping"`
+	// AutoResolve is the period of inactivity after which the
+	// resolve event is sent for the incident. Zero disables
+	// automatic incident resolution.
+	AutoResolve time.Duration `mapstructure:"auto-resolve"`
+	// Timeout is the maximum time the request to the Events API is allowed to take.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// AddFlags registers persistent flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(enabled, false, "Indicates whether PagerDuty alert sender is enabled")
+	flags.String(url, "https://events.pagerduty.com/v2/enqueue", "Represents the PagerDuty Events API v2 endpoint")
+	flags.String(routingKey, "", "Specifies the integration key of the PagerDuty service")
+	flags.StringSlice(dedupFields, []string{"evt.host"}, "Specifies the event fields that along with the rule identifier make up the incident deduplication key")
+	flags.Duration(autoResolve, 0, "Specifies the period of inactivity after which the incident is resolved. Zero disables automatic incident resolution")
+	flags.Duration(timeout, time.Second*10, "Specifies the maximum time the request to the Events API is allowed to take")
+}
@@ -0,0 +1,313 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/filter/fields"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultURL     = "https://events.pagerduty.com/v2/enqueue"
+	defaultTimeout = time.Second * 10
+	// maxDedupKeyLength is the maximum length of the deduplication key accepted by the Events API
+	maxDedupKeyLength = 255
+)
+
+// Event actions
+const (
+	trigger = "trigger"
+	resolve = "resolve"
+)
+
+// defaultSeverities maps alert severities to PagerDuty severities.
+var defaultSeverities = map[alertsender.Severity]string{
+	alertsender.Normal:   "info",
+	alertsender.Medium:   "warning",
+	alertsender.High:     "error",
+	alertsender.Critical: "critical",
+}
+
+// payload contains the incident details of the trigger event.
+type payload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// eventV2 is the Events API v2 event.
+type eventV2 struct {
+	RoutingKey  string   `json:"routing_key"`
+	EventAction string   `json:"event_action"`
+	DedupKey    string   `json:"dedup_key"`
+	Client      string   `json:"client,omitempty"`
+	Payload     *payload `json:"payload,omitempty"`
+}
+
+type pagerduty struct {
+	config     Config
+	client     *http.Client
+	severities map[alertsender.Severity]string
+
+	mu        sync.Mutex
+	incidents map[string]time.Time // dedup key -> last trigger timestamp
+	quit      chan struct{}
+	stop      sync.Once
+	wg        sync.WaitGroup
+}
+
+func init() {
+	alertsender.Register(alertsender.PagerDuty, makeSender)
+}
+
+// makeSender constructs a new instance of the PagerDuty alert sender.
+func makeSender(config alertsender.Config) (alertsender.Sender, error) {
+	c, ok := config.Sender.(Config)
+	if !ok {
+		return nil, alertsender.ErrInvalidConfig(alertsender.PagerDuty)
+	}
+	if c.RoutingKey == "" {
+		return nil, errors.New("routing key is required")
+	}
+	for _, name := range c.DedupFields {
+		if n, _, _ := strings.Cut(name, "["); !fields.IsField(n) {
+			return nil, fmt.Errorf("%q deduplication field is not a known filter field", name)
+		}
+	}
+	if c.URL == "" {
+		c.URL = defaultURL
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultTimeout
+	}
+	severities := make(map[alertsender.Severity]string)
+	for k, v := range defaultSeverities {
+		severities[k] = v
+	}
+	for k, v := range c.SeverityMapping {
+		severities[alertsender.ParseSeverityFromString(k)] = v
+	}
+	p := &pagerduty{
+		config:     c,
+		severities: severities,
+		incidents:  make(map[string]time.Time),
+		quit:       make(chan struct{}),
+		client: &http.Client{
+			Timeout: c.Timeout,
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   10 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+		},
+	}
+	if c.AutoResolve > 0 {
+		p.wg.Add(1)
+		go p.resolveInactive()
+	}
+	return p, nil
+}
+
+// Send sends the trigger event for the alert. Repeated alerts with
+// the same deduplication key are grouped into the same incident.
+func (p *pagerduty) Send(alert alertsender.Alert) error {
+	return p.SendContext(context.Background(), alert)
+}
+
+// SendContext sends the trigger event for the alert. The request is aborted when the context is done.
+func (p *pagerduty) SendContext(ctx context.Context, alert alertsender.Alert) error {
+	key := p.dedupKey(alert)
+	evt := eventV2{
+		RoutingKey:  p.config.RoutingKey,
+		EventAction: trigger,
+		DedupKey:    key,
+		Client:      "fibratus",
+		Payload: &payload{
+			Summary:   alert.Title,
+			Source:    source(alert),
+			Severity:  p.severities[alert.Severity],
+			Timestamp: timestamp(alert).Format(time.RFC3339),
+			Component: component(alert),
+			Class:     strings.Join(alert.Tags, ","),
+			CustomDetails: map[string]any{
+				"text":        alert.Text,
+				"description": alert.Description,
+				"labels":      alert.Labels,
+				"rule_id":     alert.ID,
+			},
+		},
+	}
+	if err := p.send(ctx, evt); err != nil {
+		return err
+	}
+	if p.config.AutoResolve > 0 {
+		p.mu.Lock()
+		p.incidents[key] = time.Now()
+		p.mu.Unlock()
+	}
+	return nil
+}
+
+// Resolve sends the resolve event for the incident identified by the deduplication key.
+func (p *pagerduty) Resolve(key string) error {
+	return p.send(context.Background(), eventV2{RoutingKey: p.config.RoutingKey, EventAction: resolve, DedupKey: key})
+}
+
+func (p *pagerduty) send(ctx context.Context, evt eventV2) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("failed to send %s event to PagerDuty. code: %d content: %s", evt.EventAction, resp.StatusCode, strings.TrimSpace(string(b)))
+	if alertsender.IsPermanentStatus(resp.StatusCode) {
+		return alertsender.Permanent(err)
+	}
+	return err
+}
+
+// resolveInactive periodically sends resolve events
+// for incidents that weren't triggered within the
+// auto-resolve period.
+func (p *pagerduty) resolveInactive() {
+	defer p.wg.Done()
+	interval := p.config.AutoResolve / 2
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case now := <-tick.C:
+			p.mu.Lock()
+			keys := make([]string, 0)
+			for key, ts := range p.incidents {
+				if now.Sub(ts) >= p.config.AutoResolve {
+					keys = append(keys, key)
+				}
+			}
+			p.mu.Unlock()
+			for _, key := range keys {
+				if err := p.Resolve(key); err != nil {
+					log.Warnf("unable to resolve PagerDuty incident %s: %v", key, err)
+					continue
+				}
+				p.mu.Lock()
+				if ts, ok := p.incidents[key]; ok && now.Sub(ts) >= p.config.AutoResolve {
+					delete(p.incidents, key)
+				}
+				p.mu.Unlock()
+			}
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// dedupKey builds the incident deduplication key from the rule
+// identifier and the values of configured fields extracted from
+// the first event that triggered the alert.
+func (p *pagerduty) dedupKey(alert alertsender.Alert) string {
+	parts := make([]string, 0, len(p.config.DedupFields)+1)
+	parts = append(parts, alert.ID)
+	if len(alert.Events) > 0 {
+		for _, field := range p.config.DedupFields {
+			if v := alertsender.FieldValue(field, alert.Events[0]); v != nil {
+				parts = append(parts, fmt.Sprintf("%v", v))
+			} else {
+				parts = append(parts, "")
+			}
+		}
+	}
+	key := strings.Join(parts, ":")
+	if len(key) > maxDedupKeyLength {
+		sum := sha256.Sum256([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+	return key
+}
+
+// source returns the host where the alert originated.
+func source(alert alertsender.Alert) string {
+	if len(alert.Events) > 0 && alert.Events[0].Host != "" {
+		return alert.Events[0].Host
+	}
+	return "fibratus"
+}
+
+// timestamp returns the time of the event that triggered the alert.
+func timestamp(alert alertsender.Alert) time.Time {
+	if len(alert.Events) > 0 && !alert.Events[0].Timestamp.IsZero() {
+		return alert.Events[0].Timestamp
+	}
+	return time.Now()
+}
+
+// component returns the name of the process that triggered the alert.
+func component(alert alertsender.Alert) string {
+	if len(alert.Events) > 0 && alert.Events[0].PS != nil {
+		return alert.Events[0].PS.Name
+	}
+	return ""
+}
+
+func (p *pagerduty) Type() alertsender.Type { return alertsender.PagerDuty }
+func (p *pagerduty) Shutdown() error {
+	p.stop.Do(func() { close(p.quit) })
+	p.wg.Wait()
+	return nil
+}
+func (p *pagerduty) SupportsMarkdown() bool { return false }
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pagerduty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stub struct {
+	sync.Mutex
+	events []eventV2
+}
+
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var evt eventV2
+	if err := json.NewDecoder(r.Body).Decode(&evt); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.Lock()
+	s.events = append(s.events, evt)
+	s.Unlock()
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte(`{"status":"success","message":"Event processed","dedup_key":"` + evt.DedupKey + `"}`))
+}
+
+func (s *stub) Events() []eventV2 {
+	s.Lock()
+	defer s.Unlock()
+	return append([]eventV2(nil), s.events...)
+}
+
+// resolveField resolves filter fields used in tests. The filter
+// package can't be imported here since it depends on the config
+// package that loads alert senders.
+func resolveField(name string, evt *event.Event) any {
+	switch name {
+	case "evt.host":
+		return evt.Host
+	case "ps.exe":
+		return evt.PS.Exe
+	case "evt.arg[exe]":
+		return evt.GetParamAsString(params.Exe)
+	}
+	return nil
+}
+
+func init() {
+	alertsender.SetFieldResolver(resolveField)
+}
+
+func newAlert(severity alertsender.Severity, exe string) alertsender.Alert {
+	alert := alertsender.NewAlertWithEvents(
+		"LSASS memory dumping",
+		"rundll32.exe accessed lsass.exe memory",
+		[]string{"T1003"},
+		severity,
+		[]*event.Event{
+			{
+				Name:      "OpenProcess",
+				Host:      "archrabbit",
+				Timestamp: time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC),
+				PS:        &pstypes.PS{Name: "rundll32.exe", Exe: exe},
+				Params: event.Params{
+					params.Exe: {Name: params.Exe, Type: params.UnicodeString, Value: "C:\\Windows\\System32\\lsass.exe"},
+				},
+			},
+		},
+	)
+	alert.ID = "335795af-246b-483e-8657-09a30c102e63"
+	return alert
+}
+
+func TestPagerDutySender(t *testing.T) {
+	srv := &stub{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	s, err := alertsender.Load(alertsender.Config{
+		Type: alertsender.PagerDuty,
+		Sender: Config{
+			Enabled:         true,
+			URL:             ts.URL,
+			RoutingKey:      "R0UT1NGK3Y",
+			DedupFields:     []string{"evt.host", "ps.exe"},
+			SeverityMapping: map[string]string{"medium": "info"},
+		},
+	})
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	require.NoError(t, s.Send(newAlert(alertsender.Critical, "C:\\Windows\\System32\\rundll32.exe")))
+	require.NoError(t, s.Send(newAlert(alertsender.Medium, "C:\\Temp\\rundll32.exe")))
+
+	events := srv.Events()
+	require.Len(t, events, 2)
+
+	evt := events[0]
+	assert.Equal(t, "R0UT1NGK3Y", evt.RoutingKey)
+	assert.Equal(t, "trigger", evt.EventAction)
+	assert.Equal(t, "335795af-246b-483e-8657-09a30c102e63:archrabbit:C:\\Windows\\System32\\rundll32.exe", evt.DedupKey)
+	require.NotNil(t, evt.Payload)
+	assert.Equal(t, "LSASS memory dumping", evt.Payload.Summary)
+	assert.Equal(t, "archrabbit", evt.Payload.Source)
+	assert.Equal(t, "2024-05-12T09:30:00Z", evt.Payload.Timestamp)
+	assert.Equal(t, "critical", evt.Payload.Severity)
+	assert.Equal(t, "rundll32.exe", evt.Payload.Component)
+	assert.Equal(t, "T1003", evt.Payload.Class)
+	assert.Equal(t, "rundll32.exe accessed lsass.exe memory", evt.Payload.CustomDetails["text"])
+
+	assert.Equal(t, "335795af-246b-483e-8657-09a30c102e63:archrabbit:C:\\Temp\\rundll32.exe", events[1].DedupKey)
+	assert.Equal(t, "info", events[1].Payload.Severity)
+}
+
+func TestPagerDutySenderAutoResolve(t *testing.T) {
+	srv := &stub{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	s, err := alertsender.Load(alertsender.Config{
+		Type: alertsender.PagerDuty,
+		Sender: Config{
+			Enabled:     true,
+			URL:         ts.URL,
+			RoutingKey:  "R0UT1NGK3Y",
+			DedupFields: []string{"ps.exe", "evt.arg[exe]"},
+			AutoResolve: time.Millisecond * 100,
+		},
+	})
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	require.NoError(t, s.Send(newAlert(alertsender.High, "C:\\Windows\\System32\\rundll32.exe")))
+
+	require.Eventually(t, func() bool {
+		return len(srv.Events()) == 2
+	}, time.Second*5, time.Millisecond*50)
+
+	events := srv.Events()
+	assert.Equal(t, "trigger", events[0].EventAction)
+	assert.Equal(t, "resolve", events[1].EventAction)
+	assert.Equal(t, events[0].DedupKey, events[1].DedupKey)
+	assert.Equal(t, "335795af-246b-483e-8657-09a30c102e63:C:\\Windows\\System32\\rundll32.exe:C:\\Windows\\System32\\lsass.exe", events[1].DedupKey)
+	assert.Nil(t, events[1].Payload)
+
+	require.NoError(t, s.Shutdown())
+}
+
+func TestPagerDutySenderError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"invalid event","message":"Event object is invalid"}`))
+	}))
+	defer ts.Close()
+
+	s, err := alertsender.Load(alertsender.Config{
+		Type:   alertsender.PagerDuty,
+		Sender: Config{Enabled: true, URL: ts.URL, RoutingKey: "R0UT1NGK3Y"},
+	})
+	require.NoError(t, err)
+	defer s.Shutdown()
+	err = s.Send(newAlert(alertsender.High, "C:\\Windows\\System32\\rundll32.exe"))
+	require.Error(t, err)
+	assert.True(t, alertsender.IsPermanent(err))
+
+	_, err = alertsender.Load(alertsender.Config{Type: alertsender.PagerDuty, Sender: Config{Enabled: true}})
+	require.Error(t, err)
+
+	_, err = alertsender.Load(alertsender.Config{
+		Type:   alertsender.PagerDuty,
+		Sender: Config{Enabled: true, RoutingKey: "R0UT1NGK3Y", DedupFields: []string{"exe"}},
+	})
+	require.EqualError(t, err, `"exe" deduplication field is not a known filter field`)
+}
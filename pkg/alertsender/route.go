@@ -0,0 +1,133 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alertsender
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Route determines the alert senders that receive
+// alerts satisfying all route match conditions.
+type Route struct {
+	// Severities contains the alert severities matched by the route.
+	Severities []string `mapstructure:"severity"`
+	// MinSeverity is the minimum alert severity matched by the route.
+	MinSeverity string `mapstructure:"min-severity"`
+	// Labels contains the labels that all must be present in the alert.
+	Labels map[string]string `mapstructure:"labels"`
+	// Tags matches alerts containing any of the tags.
+	Tags []string `mapstructure:"tags"`
+	// Senders is the list of sender instance names that receive matching alerts.
+	Senders []string `mapstructure:"senders"`
+}
+
+// RoutingConfig is the alert routing table.
+type RoutingConfig struct {
+	// Routes contains alert routes. The alert is delivered to
+	// senders of all routes that match the alert.
+	Routes []Route `mapstructure:"routes"`
+	// Default is the list of sender instance names that receive
+	// alerts not matched by any route.
+	Default []string `mapstructure:"default"`
+}
+
+// IsEnabled indicates if the alert routing table is configured.
+func (c RoutingConfig) IsEnabled() bool { return len(c.Routes) > 0 || len(c.Default) > 0 }
+
+// NamedSender is the alert sender instance with its name.
+type NamedSender struct {
+	Sender
+	// Name is the alert sender instance name.
+	Name string
+}
+
+var (
+	routing RoutingConfig
+	rmu     sync.RWMutex
+)
+
+// SetRouting sets the alert routing table.
+func SetRouting(c RoutingConfig) {
+	rmu.Lock()
+	defer rmu.Unlock()
+	routing = c
+}
+
+// Route returns the senders that receive the alert according to the
+// routing table. The alert is delivered to the senders of all matching
+// routes. If no route matches the alert, the default senders receive
+// the alert. If the routing table is not configured, all senders are
+// returned.
+func (a Alert) Route() []NamedSender {
+	rmu.RLock()
+	defer rmu.RUnlock()
+
+	if !routing.IsEnabled() {
+		senders := make([]NamedSender, 0, len(alertsenders))
+		for name, s := range alertsenders {
+			senders = append(senders, NamedSender{Sender: s, Name: name})
+		}
+		sort.Slice(senders, func(i, j int) bool { return senders[i].Name < senders[j].Name })
+		return senders
+	}
+
+	names := make([]string, 0)
+	for _, r := range routing.Routes {
+		if r.Matches(a) {
+			names = append(names, r.Senders...)
+		}
+	}
+	if len(names) == 0 {
+		names = routing.Default
+	}
+
+	senders := make([]NamedSender, 0, len(names))
+	for _, name := range names {
+		s, ok := alertsenders[name]
+		if !ok || slices.ContainsFunc(senders, func(n NamedSender) bool { return n.Name == name }) {
+			continue
+		}
+		senders = append(senders, NamedSender{Sender: s, Name: name})
+	}
+	return senders
+}
+
+// Matches determines if the alert satisfies all route match conditions.
+func (r Route) Matches(a Alert) bool {
+	if len(r.Severities) > 0 && !slices.ContainsFunc(r.Severities, func(s string) bool { return ParseSeverityFromString(s) == a.Severity }) {
+		return false
+	}
+	if r.MinSeverity != "" && a.Severity < ParseSeverityFromString(r.MinSeverity) {
+		return false
+	}
+	for k, v := range r.Labels {
+		if !strings.EqualFold(a.Labels[k], v) {
+			return false
+		}
+	}
+	if len(r.Tags) > 0 && !slices.ContainsFunc(r.Tags, func(t string) bool {
+		return slices.ContainsFunc(a.Tags, func(tag string) bool { return strings.EqualFold(t, tag) })
+	}) {
+		return false
+	}
+	return true
+}
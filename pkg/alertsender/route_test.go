@@ -0,0 +1,87 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alertsender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSender struct{ typ Type }
+
+func (s mockSender) Send(Alert) error       { return nil }
+func (s mockSender) Type() Type             { return s.typ }
+func (s mockSender) Shutdown() error        { return nil }
+func (s mockSender) SupportsMarkdown() bool { return false }
+
+func TestRoute(t *testing.T) {
+	alertsenders = map[string]Sender{
+		"eventlog":             mockSender{Eventlog},
+		"slack":                mockSender{Slack},
+		"mail":                 mockSender{Mail},
+		"mail:credential-team": mockSender{Mail},
+	}
+	defer func() {
+		alertsenders = map[string]Sender{}
+		SetRouting(RoutingConfig{})
+	}()
+
+	names := func(senders []NamedSender) []string {
+		n := make([]string, 0, len(senders))
+		for _, s := range senders {
+			n = append(n, s.Name)
+		}
+		return n
+	}
+
+	alert := NewAlert("LSASS memory dump", "", []string{"Credential Access"}, High)
+	alert.Labels = map[string]string{"tactic.id": "TA0006"}
+
+	// without the routing table all senders receive the alert
+	assert.Equal(t, []string{"eventlog", "mail", "mail:credential-team", "slack"}, names(alert.Route()))
+	assert.Equal(t, alertsenders["mail"], Find(Mail))
+	assert.Equal(t, alertsenders["mail:credential-team"], FindByName("mail:credential-team"))
+
+	SetRouting(RoutingConfig{
+		Routes: []Route{
+			{Severities: []string{"critical"}, Senders: []string{"slack", "mail"}},
+			{Labels: map[string]string{"tactic.id": "TA0006"}, Senders: []string{"mail:credential-team"}},
+			{MinSeverity: "high", Tags: []string{"credential access"}, Senders: []string{"mail", "mail:credential-team"}},
+		},
+		Default: []string{"eventlog"},
+	})
+
+	var tests = []struct {
+		alert Alert
+		want  []string
+	}{
+		{alert, []string{"mail:credential-team", "mail"}},
+		{NewAlert("Suspicious DLL loaded", "", nil, Critical), []string{"slack", "mail"}},
+		{NewAlert("Credential discovery", "", []string{"credential access"}, Medium), []string{"eventlog"}},
+		{NewAlert("Unsigned binary executed", "", nil, Normal), []string{"eventlog"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alert.Title, func(t *testing.T) {
+			require.Equal(t, tt.want, names(tt.alert.Route()))
+		})
+	}
+}
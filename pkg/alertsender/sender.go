@@ -19,15 +19,55 @@
 package alertsender
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
 	"github.com/rabbitstack/fibratus/pkg/util/multierror"
+	log "github.com/sirupsen/logrus"
 )
 
 // ErrInvalidConfig signals an invalid sender config
 var ErrInvalidConfig = func(name Type) error { return fmt.Errorf("invalid config for %q sender", name) }
 
 var factories = map[Type]Factory{}
-var alertsenders = map[Type]Sender{}
+
+// PermanentError signals the alert was rejected by the destination,
+// for example, due to a malformed payload or invalid credentials.
+// Retrying the delivery of such an alert can't succeed.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent wraps the error to signal the alert delivery shouldn't be retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent determines if the alert delivery error is non-retryable.
+func IsPermanent(err error) bool {
+	var e *PermanentError
+	return errors.As(err, &e)
+}
+
+// IsPermanentStatus determines if the HTTP status code returned
+// by the alert destination designates the rejected alert. Client
+// errors are permanent except timeouts and rate limiting.
+func IsPermanentStatus(code int) bool {
+	return code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
+
+// alertsenders stores alert sender instances keyed by instance name
+var alertsenders = map[string]Sender{}
 
 // Factory defines the alias for the alert sender factory
 type Factory func(config Config) (Sender, error)
@@ -46,6 +86,12 @@ const (
 	Systray
 	// Eventlog designate the eventlog alert sender
 	Eventlog
+	// Webhook designates the generic webhook alert sender
+	Webhook
+	// PagerDuty designates the PagerDuty alert sender
+	PagerDuty
+	// Syslog designates the syslog alert sender
+	Syslog
 	// None is the type for unknown alert sender
 	None
 )
@@ -63,6 +109,12 @@ func (s Type) String() string {
 		return "systray"
 	case Eventlog:
 		return "eventlog"
+	case Webhook:
+		return "webhook"
+	case PagerDuty:
+		return "pagerduty"
+	case Syslog:
+		return "syslog"
 	default:
 		return "none"
 	}
@@ -82,6 +134,23 @@ type Sender interface {
 	SupportsMarkdown() bool
 }
 
+// ContextSender is implemented by senders whose alert delivery
+// can be cancelled, for example, when the delivery attempt
+// exceeds the rule action timeout.
+type ContextSender interface {
+	// SendContext emits an alert. The delivery is aborted when the context is done.
+	SendContext(context.Context, Alert) error
+}
+
+// SendContext emits the alert via the sender. If the sender
+// supports cancellation, the delivery is bound to the context.
+func SendContext(ctx context.Context, s Sender, alert Alert) error {
+	if cs, ok := s.(ContextSender); ok {
+		return cs.SendContext(ctx, alert)
+	}
+	return s.Send(alert)
+}
+
 // ToType converts the string representation of the alert sender to its corresponding type.
 func ToType(s string) Type {
 	switch s {
@@ -93,6 +162,12 @@ func ToType(s string) Type {
 		return Noop
 	case "systray":
 		return Systray
+	case "webhook":
+		return Webhook
+	case "pagerduty":
+		return PagerDuty
+	case "syslog":
+		return Syslog
 	default:
 		return None
 	}
@@ -106,9 +181,102 @@ func Register(typ Type, factory Factory) {
 	factories[typ] = factory
 }
 
-// Find locates the sender.
+// Find locates the sender. If there are multiple instances of
+// the same sender type, the default instance is returned.
 func Find(typ Type) Sender {
-	return alertsenders[typ]
+	if s, ok := alertsenders[typ.String()]; ok {
+		return s
+	}
+	for _, s := range alertsenders {
+		if s.Type() == typ {
+			return s
+		}
+	}
+	return nil
+}
+
+// FindByName locates the sender instance by its name.
+func FindByName(name string) Sender {
+	return alertsenders[name]
+}
+
+// Recorder persists alerts regardless of the senders they are routed to.
+type Recorder interface {
+	// Record persists the alert.
+	Record(Alert) error
+}
+
+// recordQueueSize is the maximum number of alerts waiting to be recorded
+const recordQueueSize = 1024
+
+var (
+	recmu    sync.RWMutex
+	records  chan Alert
+	recorded sync.WaitGroup
+)
+
+// SetRecorder sets the recorder that persists all emitted alerts.
+// Alerts are recorded in the background, so persisting the alert
+// doesn't hold up the caller.
+func SetRecorder(r Recorder) {
+	recmu.Lock()
+	defer recmu.Unlock()
+	records = make(chan Alert, recordQueueSize)
+	recorded.Add(1)
+	go func(records chan Alert) {
+		defer recorded.Done()
+		for alert := range records {
+			if err := r.Record(alert); err != nil {
+				log.Warnf("unable to record %q alert: %v", alert.Title, err)
+			}
+		}
+	}(records)
+}
+
+// Record enqueues the alert for the recorder if the recorder is
+// set. The alert is not recorded if the recorder can't keep up.
+func Record(alert Alert) {
+	recmu.RLock()
+	defer recmu.RUnlock()
+	if records == nil {
+		return
+	}
+	select {
+	case records <- alert:
+	default:
+		log.Warnf("alert recorder queue is full. %q alert won't be recorded", alert.Title)
+	}
+}
+
+// CloseRecorder waits until pending alerts are
+// recorded and detaches the recorder.
+func CloseRecorder() {
+	recmu.Lock()
+	if records != nil {
+		close(records)
+		records = nil
+	}
+	recmu.Unlock()
+	recorded.Wait()
+}
+
+// FieldResolver extracts the value of the filter field from the event.
+type FieldResolver func(name string, evt *event.Event) any
+
+var resolver FieldResolver
+
+// SetFieldResolver sets the function that resolves values
+// of filter fields referenced in alert sender settings.
+func SetFieldResolver(r FieldResolver) { resolver = r }
+
+// FieldValue resolves the value of the filter field from the event.
+// Nil is returned if the field resolver is not set or the field value
+// can't be resolved.
+func FieldValue(name string, evt *event.Event) any {
+	if resolver == nil {
+		return nil
+	}
+	return resolver(name, evt)
 }
 
 // FindAll returns all registered senders.
@@ -143,13 +311,29 @@ func Load(config Config) (Sender, error) {
 }
 
 // LoadAll loads all alert senders from the configuration inputs.
+// Senders with the enabled spool are wrapped in the durable delivery
+// queue that retries failed deliveries in the background.
+// If the sender instance with the same name is already loaded, it
+// is shut down and replaced with the new instance.
 func LoadAll(configs []Config) error {
 	for _, config := range configs {
 		alertsender, err := Load(config)
 		if err != nil {
-			return fmt.Errorf("fail to load %q alertsender: %v", config.Type, err)
+			return fmt.Errorf("fail to load %q alertsender: %v", config.InstanceName(), err)
+		}
+		name := config.InstanceName()
+		if config.Spool.Enabled {
+			alertsender, err = NewSpool(name, alertsender, config.Spool)
+			if err != nil {
+				return fmt.Errorf("fail to open %q alertsender spool: %v", name, err)
+			}
+		}
+		if s, ok := alertsenders[name]; ok {
+			if err := s.Shutdown(); err != nil {
+				log.Warnf("unable to shutdown %q alertsender: %v", name, err)
+			}
 		}
-		alertsenders[config.Type] = alertsender
+		alertsenders[name] = alertsender
 	}
 	return nil
 }
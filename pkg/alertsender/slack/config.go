@@ -18,7 +18,11 @@
 
 package slack
 
-import "github.com/spf13/pflag"
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
 
 const (
 	enabled   = "alertsenders.slack.enabled"
@@ -26,6 +30,8 @@ const (
 	workspace = "alertsenders.slack.workspace"
 	channel   = "alertsenders.slack.channel"
 	botemoji  = "alertsenders.slack.emoji"
+	token     = "alertsenders.slack.token"
+	threading = "alertsenders.slack.thread-window"
 )
 
 // Config stores the settings that dictate the behaviour of the Slack alert sender.
@@ -40,6 +46,13 @@ type Config struct {
 	BotEmoji string `mapstructure:"emoji"`
 	// Enabled determines if Slack alert sender is enabled.
 	Enabled bool `mapstructure:"enabled"`
+	// Token is the bot token used to post alerts via the Web API. If
+	// the token is specified, the Webhook URL is ignored.
+	Token string `mapstructure:"token"`
+	// ThreadWindow is the period during which repeat alerts for the
+	// same rule and entity are posted as thread replies. Threading
+	// requires the bot token.
+	ThreadWindow time.Duration `mapstructure:"thread-window"`
 }
 
 // AddFlags registers persistent flags.
@@ -49,4 +62,6 @@ func AddFlags(flags *pflag.FlagSet) {
 	flags.String(workspace, "", "Designates the Slack workspace where alerts will be routed")
 	flags.String(channel, "", "Represents the slack channel in which to post alerts")
 	flags.String(botemoji, "", "Represents the emoji icon for the Slack bot")
+	flags.String(token, "", "Specifies the bot token used to post alerts via the Web API")
+	flags.Duration(threading, time.Hour, "Specifies the period during which repeat alerts for the same rule and entity are posted as thread replies")
 }
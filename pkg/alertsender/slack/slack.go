@@ -20,6 +20,7 @@ package slack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,22 +28,71 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 const botName = "fibratus"
 
+// apiURL is the Web API method for posting messages
+var apiURL = "https://slack.com/api/chat.postMessage"
+
+const (
+	// maxHeaderLength is the maximum length of the header block text
+	maxHeaderLength = 150
+	// maxSectionLength is the maximum length of the section block text
+	maxSectionLength = 3000
+)
+
 type slack struct {
-	client *http.Client
-	config Config
+	client  *http.Client
+	config  Config
+	threads *threads
+}
+
+// message represents the Slack message posted via the Webhook or Web API.
+type message struct {
+	Channel     string       `json:"channel,omitempty"`
+	Username    string       `json:"username,omitempty"`
+	IconEmoji   string       `json:"icon_emoji,omitempty"`
+	Text        string       `json:"text"`
+	ThreadTS    string       `json:"thread_ts,omitempty"`
+	Attachments []attachment `json:"attachments"`
 }
 
-// attachment represents Slack attachment info
+// attachment represents Slack attachment info. Blocks are
+// wrapped in the attachment to render the severity color.
 type attachment struct {
-	Fallback string   `json:"fallback"`
-	Color    string   `json:"color"`
-	Text     string   `json:"text"`
-	Mdin     []string `json:"mrkdwn_in"`
+	Fallback string  `json:"fallback"`
+	Color    string  `json:"color"`
+	Blocks   []block `json:"blocks"`
+}
+
+// block represents the Block Kit layout block.
+type block struct {
+	Type     string `json:"type"`
+	Text     *text  `json:"text,omitempty"`
+	Fields   []text `json:"fields,omitempty"`
+	Elements []text `json:"elements,omitempty"`
+}
+
+// text represents the Block Kit text object.
+type text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func mrkdwn(s string) text { return text{Type: "mrkdwn", Text: s} }
+
+// response is the Web API response.
+type response struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
 }
 
 func init() {
@@ -65,47 +115,114 @@ func makeSender(config alertsender.Config) (alertsender.Sender, error) {
 			TLSHandshakeTimeout: 10 * time.Second,
 		},
 	}
-	return &slack{config: c, client: client}, nil
+	return &slack{config: c, client: client, threads: newThreads(c.ThreadWindow)}, nil
 }
 
 func (s slack) Send(alert alertsender.Alert) error {
-	var color string
-	switch alert.Severity {
-	case alertsender.Medium:
-		color = "warning"
-	case alertsender.Critical, alertsender.High:
-		color = "danger"
-	default:
-		color = "good"
+	return s.SendContext(context.Background(), alert)
+}
+
+// SendContext posts the alert message. The request is aborted when the context is done.
+func (s slack) SendContext(ctx context.Context, alert alertsender.Alert) error {
+	msg := s.buildMessage(alert)
+	if s.config.Token == "" {
+		return s.postWebhook(ctx, msg)
+	}
+
+	// repeat alerts for the same rule and
+	// entity are posted as thread replies
+	key := threadKey(alert)
+	if ts, ok := s.threads.get(key); ok {
+		msg.ThreadTS = ts
+	}
+	resp, err := s.postAPI(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if msg.ThreadTS == "" && resp.TS != "" {
+		s.threads.put(key, resp.TS)
 	}
+	return nil
+}
 
-	text := fmt.Sprintf("%s\n%s", alert.Title, alert.Text)
+func (s slack) Type() alertsender.Type { return alertsender.Slack }
+func (s slack) Shutdown() error        { return nil }
+func (s slack) SupportsMarkdown() bool { return true }
+
+// buildMessage renders the alert as Block Kit message.
+func (s slack) buildMessage(alert alertsender.Alert) message {
+	fallback := fmt.Sprintf("%s\n%s", alert.Title, alert.Text)
 
-	attach := attachment{
-		Fallback: text,
-		Text:     text,
-		Color:    color,
-		Mdin:     []string{"text"},
+	blocks := []block{
+		{Type: "header", Text: &text{Type: "plain_text", Text: truncate(alert.Title, maxHeaderLength)}},
+	}
+	if alert.Text != "" {
+		blocks = append(blocks, block{Type: "section", Text: ptr(mrkdwn(truncate(alert.Text, maxSectionLength)))})
 	}
 
-	params := make(map[string]interface{})
-	params["as_user"] = false
-	params["channel"] = s.config.Channel
-	params["text"] = ""
-	params["attachments"] = []attachment{attach}
-	params["username"] = botName
+	// severity and MITRE ATT&CK fields
+	severity := alert.Severity.String()
+	fields := []text{mrkdwn(fmt.Sprintf("*Severity*\n%s", strings.ToUpper(severity[:1])+severity[1:]))}
+	for _, f := range []struct{ title, name, ref string }{
+		{"Tactic", "tactic.name", "tactic.ref"},
+		{"Technique", "technique.name", "technique.ref"},
+		{"Subtechnique", "subtechnique.name", "subtechnique.ref"},
+	} {
+		name, ok := alert.Labels[f.name]
+		if !ok {
+			continue
+		}
+		if ref := alert.Labels[f.ref]; ref != "" {
+			fields = append(fields, mrkdwn(fmt.Sprintf("*%s*\n<%s|%s>", f.title, ref, name)))
+		} else {
+			fields = append(fields, mrkdwn(fmt.Sprintf("*%s*\n%s", f.title, name)))
+		}
+	}
+	blocks = append(blocks, block{Type: "section", Fields: fields})
+
+	if alert.Description != "" {
+		blocks = append(blocks, block{Type: "context", Elements: []text{mrkdwn(truncate(alert.Description, maxSectionLength))}})
+	}
+
+	if tree := processTree(alert); tree != "" {
+		blocks = append(blocks, block{Type: "section", Text: ptr(mrkdwn(truncate("*Process tree*\n```"+tree+"```", maxSectionLength)))})
+	}
+
+	// Slack collapses long messages behind the
+	// show more link, so the event JSON goes last
+	if len(alert.Events) > 0 {
+		if events := eventsJSON(alert); events != "" {
+			blocks = append(blocks, block{Type: "divider"})
+			blocks = append(blocks, block{Type: "section", Text: ptr(mrkdwn(codeBlock("*Events*\n", events)))})
+		}
+	}
+
+	msg := message{
+		Channel:  s.config.Channel,
+		Username: botName,
+		Text:     alert.Title,
+		Attachments: []attachment{
+			{Fallback: fallback, Color: severityColor(alert.Severity), Blocks: blocks},
+		},
+	}
 	if s.config.BotEmoji != "" {
-		params["icon_emoji"] = s.config.BotEmoji
+		msg.IconEmoji = s.config.BotEmoji
 	}
+	return msg
+}
 
+// postWebhook posts the message via the incoming Webhook.
+func (s slack) postWebhook(ctx context.Context, msg message) error {
 	var body bytes.Buffer
-	enc := json.NewEncoder(&body)
-	err := enc.Encode(params)
+	if err := json.NewEncoder(&body).Encode(msg); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, &body)
 	if err != nil {
-		return nil
+		return err
 	}
-	//nolint:noctx
-	resp, err := s.client.Post(s.config.URL, "application/json", &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -115,20 +232,218 @@ func (s slack) Send(alert alertsender.Alert) error {
 		if err != nil {
 			return err
 		}
-		type response struct {
-			Error string `json:"error"`
+		var r response
+		if err := json.Unmarshal(body, &r); err == nil && r.Error != "" {
+			err = errors.New(r.Error)
+		} else {
+			err = fmt.Errorf("failed to send alert to Slack. code: %d content: %s", resp.StatusCode, string(body))
 		}
-		r := &response{Error: fmt.Sprintf("failed to send alert to Slack. code: %d content: %s", resp.StatusCode, string(body))}
-		b := bytes.NewReader(body)
-		dec := json.NewDecoder(b)
-		if err := dec.Decode(r); err != nil {
-			return err
+		if alertsender.IsPermanentStatus(resp.StatusCode) {
+			return alertsender.Permanent(err)
 		}
-		return errors.New(r.Error)
+		return err
 	}
 	return nil
 }
 
-func (s slack) Type() alertsender.Type { return alertsender.Slack }
-func (s slack) Shutdown() error        { return nil }
-func (s slack) SupportsMarkdown() bool { return true }
+// postAPI posts the message via the Web API with the bot token.
+func (s slack) postAPI(ctx context.Context, msg message) (*response, error) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(msg); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("failed to send alert to Slack. code: %d content: %s", resp.StatusCode, string(b))
+		if alertsender.IsPermanentStatus(resp.StatusCode) {
+			return nil, alertsender.Permanent(err)
+		}
+		return nil, err
+	}
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	if !r.OK {
+		err := fmt.Errorf("failed to send alert to Slack: %s", r.Error)
+		if !isRetryableAPIError(r.Error) {
+			return nil, alertsender.Permanent(err)
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// isRetryableAPIError determines if the Web API error
+// is transient. Other errors, such as invalid_auth or
+// channel_not_found, persist until the configuration
+// or the message is fixed.
+func isRetryableAPIError(code string) bool {
+	switch code {
+	case "ratelimited", "internal_error", "fatal_error":
+		return true
+	default:
+		return false
+	}
+}
+
+// severityColor returns the attachment color for the alert severity.
+func severityColor(severity alertsender.Severity) string {
+	switch severity {
+	case alertsender.Medium:
+		return "#fcd834"
+	case alertsender.High:
+		return "#f2784b"
+	case alertsender.Critical:
+		return "#d62d20"
+	default:
+		return "#29b33e"
+	}
+}
+
+// processTree renders the ancestry of processes
+// involved in the alert, starting from the root.
+func processTree(alert alertsender.Alert) string {
+	var (
+		b    strings.Builder
+		pids = make(map[uint32]bool)
+	)
+	for _, evt := range alert.Events {
+		ps := evt.PS
+		if ps == nil || pids[ps.PID] {
+			continue
+		}
+		pids[ps.PID] = true
+		procs := ps.Ancestors()
+		slices.Reverse(procs)
+		procs = append(procs, fmt.Sprintf("%s (%d)", ps.Name, ps.PID))
+		for i, proc := range procs {
+			if i == 0 {
+				b.WriteString(proc)
+			} else {
+				b.WriteString(strings.Repeat("   ", i-1) + "└─ " + proc)
+			}
+			b.WriteByte('\n')
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// eventsJSON returns the indented JSON representation of alert events.
+func eventsJSON(alert alertsender.Alert) string {
+	b, err := json.Marshal(alert)
+	if err != nil {
+		return ""
+	}
+	var a struct {
+		Events json.RawMessage `json:"events"`
+	}
+	if err := json.Unmarshal(b, &a); err != nil {
+		return ""
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, a.Events, "", "  "); err != nil {
+		return ""
+	}
+	return out.String()
+}
+
+// codeBlock wraps the text in the code block that fits the section block.
+func codeBlock(prefix, s string) string {
+	const fence = "```"
+	if n := maxSectionLength - len(prefix) - 2*len(fence); len(s) > n {
+		s = truncate(s, n)
+	}
+	return prefix + fence + s + fence
+}
+
+// truncate shortens the string to the maximum length in bytes.
+func truncate(s string, n int) string {
+	const ellipsis = "…"
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n-len(ellipsis)]
+	// don't split multibyte characters
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s + ellipsis
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// threadKey identifies the thread of repeat alerts for the same
+// rule and entity. The entity is the process executable of the
+// first event, or the host name if the process is not available.
+func threadKey(alert alertsender.Alert) string {
+	rule := alert.ID
+	if rule == "" {
+		rule = alert.Title
+	}
+	if len(alert.Events) == 0 {
+		return rule
+	}
+	evt := alert.Events[0]
+	if evt.PS != nil && evt.PS.Exe != "" {
+		return rule + "|" + strings.ToLower(evt.PS.Exe)
+	}
+	return rule + "|" + evt.Host
+}
+
+// threads tracks the timestamps of the thread parent messages.
+type threads struct {
+	mu     sync.Mutex
+	window time.Duration
+	ts     map[string]thread
+}
+
+type thread struct {
+	ts      string
+	expires time.Time
+}
+
+func newThreads(window time.Duration) *threads {
+	return &threads{window: window, ts: make(map[string]thread)}
+}
+
+// get returns the parent message timestamp if the thread is still open.
+func (t *threads) get(key string) (string, bool) {
+	if t.window <= 0 {
+		return "", false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	th, ok := t.ts[key]
+	if !ok || time.Now().After(th.expires) {
+		return "", false
+	}
+	return th.ts, true
+}
+
+// put opens the thread for the parent message.
+func (t *threads) put(key, ts string) {
+	if t.window <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for k, th := range t.ts {
+		if now.After(th.expires) {
+			delete(t.ts, k)
+		}
+	}
+	t.ts[key] = thread{ts: ts, expires: now.Add(t.window)}
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAlert(id string, exe string) alertsender.Alert {
+	alert := alertsender.NewAlertWithEvents(
+		"LSASS memory dumping via legitimate or offensive tools",
+		"`rundll32.exe` process dumped LSASS memory",
+		nil,
+		alertsender.Critical,
+		[]*event.Event{
+			{
+				Name:      "CreateFile",
+				Category:  event.File,
+				Timestamp: time.Now(),
+				PS: &pstypes.PS{
+					PID:  2436,
+					Name: "rundll32.exe",
+					Exe:  exe,
+					Parent: &pstypes.PS{
+						PID:    2034,
+						Name:   "cmd.exe",
+						Parent: &pstypes.PS{PID: 1024, Name: "explorer.exe"},
+					},
+				},
+			},
+		},
+	)
+	alert.ID = id
+	alert.Labels = map[string]string{
+		"tactic.name":    "Credential Access",
+		"tactic.ref":     "https://attack.mitre.org/tactics/TA0006/",
+		"technique.name": "OS Credential Dumping",
+	}
+	return alert
+}
+
+func TestSendWebhook(t *testing.T) {
+	var msg message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	s, err := makeSender(alertsender.Config{Type: alertsender.Slack, Sender: Config{URL: srv.URL, Channel: "alerts"}})
+	require.NoError(t, err)
+	require.NoError(t, s.Send(newAlert("1", `C:\Windows\System32\rundll32.exe`)))
+
+	assert.Equal(t, "alerts", msg.Channel)
+	assert.Equal(t, "LSASS memory dumping via legitimate or offensive tools", msg.Text)
+	require.Len(t, msg.Attachments, 1)
+	assert.Equal(t, "#d62d20", msg.Attachments[0].Color)
+
+	blocks := msg.Attachments[0].Blocks
+	require.True(t, len(blocks) >= 5)
+	assert.Equal(t, "header", blocks[0].Type)
+	assert.Equal(t, "LSASS memory dumping via legitimate or offensive tools", blocks[0].Text.Text)
+
+	fields := blocks[2].Fields
+	require.Len(t, fields, 3)
+	assert.Equal(t, "*Severity*\nCritical", fields[0].Text)
+	assert.Equal(t, "*Tactic*\n<https://attack.mitre.org/tactics/TA0006/|Credential Access>", fields[1].Text)
+	assert.Equal(t, "*Technique*\nOS Credential Dumping", fields[2].Text)
+
+	assert.Equal(t, "*Process tree*\n```explorer.exe (1024)\n└─ cmd.exe (2034)\n   └─ rundll32.exe (2436)```", blocks[3].Text.Text)
+	assert.True(t, strings.HasPrefix(blocks[len(blocks)-1].Text.Text, "*Events*\n```["))
+}
+
+func TestSendWebhookError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("no_service"))
+	}))
+	defer srv.Close()
+
+	s, err := makeSender(alertsender.Config{Type: alertsender.Slack, Sender: Config{URL: srv.URL}})
+	require.NoError(t, err)
+	require.EqualError(t, s.Send(newAlert("1", "")), "failed to send alert to Slack. code: 404 content: no_service")
+}
+
+func TestSendAPIThreads(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		msgs []message
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer xoxb-token", r.Header.Get("Authorization"))
+		var msg message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		mu.Lock()
+		msgs = append(msgs, msg)
+		ts := len(msgs)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(response{OK: true, Channel: "C123", TS: "1700000000.00000" + string(rune('0'+ts))})
+	}))
+	defer srv.Close()
+
+	apiURL = srv.URL
+	defer func() { apiURL = "https://slack.com/api/chat.postMessage" }()
+
+	s, err := makeSender(alertsender.Config{Type: alertsender.Slack, Sender: Config{Token: "xoxb-token", Channel: "alerts", ThreadWindow: time.Minute}})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Send(newAlert("1", `C:\Windows\System32\rundll32.exe`)))
+	require.NoError(t, s.Send(newAlert("1", `C:\Windows\System32\rundll32.exe`)))
+	require.NoError(t, s.Send(newAlert("2", `C:\Windows\System32\rundll32.exe`)))
+	require.NoError(t, s.Send(newAlert("1", `C:\Windows\System32\RUNDLL32.exe`)))
+
+	require.Len(t, msgs, 4)
+	assert.Empty(t, msgs[0].ThreadTS)
+	assert.Equal(t, "1700000000.000001", msgs[1].ThreadTS)
+	// different rule starts a new thread
+	assert.Empty(t, msgs[2].ThreadTS)
+	assert.Equal(t, "1700000000.000001", msgs[3].ThreadTS)
+}
+
+func TestSendAPIError(t *testing.T) {
+	var code string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(response{OK: false, Error: code})
+	}))
+	defer srv.Close()
+
+	apiURL = srv.URL
+	defer func() { apiURL = "https://slack.com/api/chat.postMessage" }()
+
+	s, err := makeSender(alertsender.Config{Type: alertsender.Slack, Sender: Config{Token: "xoxb-token"}})
+	require.NoError(t, err)
+
+	var tests = []struct {
+		code      string
+		permanent bool
+	}{
+		{"channel_not_found", true},
+		{"invalid_auth", true},
+		{"msg_too_long", true},
+		{"ratelimited", false},
+		{"internal_error", false},
+		{"fatal_error", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			code = tt.code
+			err := s.Send(newAlert("1", ""))
+			require.EqualError(t, err, "failed to send alert to Slack: "+tt.code)
+			assert.Equal(t, tt.permanent, alertsender.IsPermanent(err))
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 3))
+	assert.Equal(t, "a…", truncate("abcde", 4))
+	assert.Equal(t, "…", truncate("ššš", 4))
+}
@@ -0,0 +1,425 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alertsender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/cap/section"
+	capver "github.com/rabbitstack/fibratus/pkg/cap/version"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	ptypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// spoolDepth reports the number of pending alerts per sender instance
+	spoolDepth = expvar.NewMap("alertsender.spool.depth")
+	// spoolRetries counts failed delivery attempts per sender instance
+	spoolRetries = expvar.NewMap("alertsender.spool.retries")
+	// spoolDropped counts alerts dropped due to spool size or age limits per sender instance
+	spoolDropped = expvar.NewMap("alertsender.spool.dropped")
+	// spoolRejected counts alerts rejected by the sender or exceeding the maximum retries per sender instance
+	spoolRejected = expvar.NewMap("alertsender.spool.rejected")
+	// spoolReplayed counts alerts recovered from the spool on startup per sender instance
+	spoolReplayed = expvar.NewMap("alertsender.spool.replayed")
+)
+
+// spoolExt is the extension of spooled alert files
+const spoolExt = ".alert"
+
+// spooledEvent is the disk representation of the alert event. The
+// process state is stored separately since the raw event format
+// only retains the process state for process creation events.
+type spooledEvent struct {
+	Raw []byte `json:"raw"`
+	PS  []byte `json:"ps,omitempty"`
+}
+
+// spooledAlert is the disk representation of the alert.
+type spooledAlert struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Text        string            `json:"text"`
+	Tags        []string          `json:"tags,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Severity    Severity          `json:"severity"`
+	Events      []spooledEvent    `json:"events,omitempty"`
+}
+
+// item is the pending alert in the spool.
+type item struct {
+	seq       uint64
+	size      int64
+	timestamp time.Time
+	alert     Alert
+	attempts  int
+}
+
+// spool is the durable delivery queue that wraps the alert sender. Alerts are
+// written to disk before the delivery is attempted, and removed once the sender
+// successfully emits them. Failed deliveries are retried in the background with
+// exponential backoff, and pending alerts are replayed when the spool is opened.
+// Alerts permanently rejected by the sender or exceeding the maximum number of
+// retries are dropped from the spool, so they don't block the delivery of
+// subsequent alerts.
+type spool struct {
+	Sender
+	name   string
+	dir    string
+	config SpoolConfig
+
+	mu    sync.Mutex
+	queue []*item
+	size  int64
+	seq   uint64
+
+	depth  *expvar.Int
+	notify chan struct{}
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSpool wraps the sender with the on-disk delivery spool. Alerts left
+// in the spool directory by the previous run are replayed.
+func NewSpool(name string, sender Sender, config SpoolConfig) (Sender, error) {
+	s := &spool{
+		Sender: sender,
+		name:   name,
+		dir:    filepath.Join(spoolPath(config.Path), strings.ReplaceAll(name, ":", "-")),
+		config: config,
+		queue:  make([]*item, 0),
+		depth:  new(expvar.Int),
+		notify: make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+	}
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create %q spool directory: %v", s.dir, err)
+	}
+	spoolDepth.Set(name, s.depth)
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.run()
+
+	return s, nil
+}
+
+// Send writes the alert to the spool and wakes up the delivery loop.
+// If the alert can't be spooled, the delivery is attempted directly.
+// Once spooled, the delivery is retried by the spool, so the alert
+// is acknowledged to the caller.
+func (s *spool) Send(alert Alert) error {
+	return s.SendContext(context.Background(), alert)
+}
+
+// SendContext spools the alert. The context bounds the direct
+// delivery if the alert can't be spooled.
+func (s *spool) SendContext(ctx context.Context, alert Alert) error {
+	if err := s.enqueue(alert); err != nil {
+		log.Warnf("unable to spool alert for [%s] sender: %v", s.name, err)
+		return SendContext(ctx, s.Sender, alert)
+	}
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Shutdown stops the delivery loop and shuts down the underlying
+// sender. Pending alerts remain in the spool until the next run.
+func (s *spool) Shutdown() error {
+	close(s.quit)
+	s.wg.Wait()
+	return s.Sender.Shutdown()
+}
+
+// load recovers pending alerts from the spool directory.
+func (s *spool) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != spoolExt {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(e.Name(), spoolExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		file := filepath.Join(s.dir, e.Name())
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		alert, ts, err := decodeAlert(b)
+		if err != nil {
+			log.Warnf("removing corrupted %s spooled alert: %v", file, err)
+			_ = os.Remove(file)
+			continue
+		}
+		s.queue = append(s.queue, &item{seq: seq, size: int64(len(b)), timestamp: ts, alert: alert})
+		s.size += int64(len(b))
+		s.seq = max(s.seq, seq)
+	}
+	sort.Slice(s.queue, func(i, j int) bool { return s.queue[i].seq < s.queue[j].seq })
+
+	if n := len(s.queue); n > 0 {
+		log.Infof("replaying %d spooled alert(s) via [%s] sender", n, s.name)
+		spoolReplayed.Add(s.name, int64(n))
+	}
+	s.evict()
+	s.depth.Set(int64(len(s.queue)))
+
+	return nil
+}
+
+// enqueue persists the alert in the spool directory.
+func (s *spool) enqueue(alert Alert) error {
+	now := time.Now()
+	b, err := encodeAlert(alert, now)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	it := &item{seq: s.seq, size: int64(len(b)), timestamp: now, alert: alert}
+	if err := writeFile(s.file(it), b); err != nil {
+		return err
+	}
+	s.queue = append(s.queue, it)
+	s.size += it.size
+	s.evict()
+	s.depth.Set(int64(len(s.queue)))
+
+	return nil
+}
+
+// evict drops the oldest alerts exceeding spool size or age limits.
+// The caller must hold the lock.
+func (s *spool) evict() {
+	maxSize := int64(s.config.MaxSize) * 1024 * 1024
+	for len(s.queue) > 0 {
+		it := s.queue[0]
+		expired := s.config.MaxAge > 0 && time.Since(it.timestamp) > s.config.MaxAge
+		if !expired && (maxSize <= 0 || s.size <= maxSize) {
+			return
+		}
+		log.Warnf("dropping %q alert from [%s] sender spool", it.alert.Title, s.name)
+		spoolDropped.Add(s.name, 1)
+		s.remove(it)
+	}
+}
+
+// remove deletes the alert from the spool. The caller must hold the lock.
+func (s *spool) remove(it *item) {
+	if len(s.queue) == 0 || s.queue[0] != it {
+		// the alert was already evicted
+		return
+	}
+	s.queue = s.queue[1:]
+	s.size -= it.size
+	if err := os.Remove(s.file(it)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warnf("unable to remove spooled alert: %v", err)
+	}
+}
+
+// head returns the oldest pending alert.
+func (s *spool) head() *item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evict()
+	s.depth.Set(int64(len(s.queue)))
+	if len(s.queue) == 0 {
+		return nil
+	}
+	return s.queue[0]
+}
+
+// deliver sends pending alerts in order until the spool is drained
+// or the sender fails to emit the alert. Permanently rejected alerts
+// and alerts that exhausted the maximum number of retries are removed
+// from the spool.
+func (s *spool) deliver() error {
+	for {
+		select {
+		case <-s.quit:
+			return nil
+		default:
+		}
+		it := s.head()
+		if it == nil {
+			return nil
+		}
+		if err := s.Sender.Send(it.alert); err != nil {
+			it.attempts++
+			switch {
+			case IsPermanent(err):
+				log.Warnf("dropping %q alert rejected by [%s] sender: %v", it.alert.Title, s.name, err)
+			case s.config.MaxRetries > 0 && it.attempts > s.config.MaxRetries:
+				log.Warnf("dropping %q alert from [%s] sender spool after %d failed attempts: %v", it.alert.Title, s.name, it.attempts, err)
+			default:
+				spoolRetries.Add(s.name, 1)
+				return err
+			}
+			spoolRejected.Add(s.name, 1)
+		}
+		s.mu.Lock()
+		s.remove(it)
+		s.depth.Set(int64(len(s.queue)))
+		s.mu.Unlock()
+	}
+}
+
+// run is the delivery loop. When the delivery fails, the next attempt
+// is scheduled after the backoff interval that doubles on every failed
+// attempt until it reaches the maximum retry interval.
+func (s *spool) run() {
+	defer s.wg.Done()
+	backoff := s.config.RetryInterval
+	for {
+		err := s.deliver()
+		if err == nil {
+			backoff = s.config.RetryInterval
+			select {
+			case <-s.notify:
+			case <-s.quit:
+				return
+			}
+			continue
+		}
+		log.Warnf("unable to deliver spooled alert via [%s] sender: %v. Retrying in %v", s.name, err, backoff)
+		select {
+		case <-time.After(backoff):
+			backoff = min(backoff*2, max(s.config.MaxRetryInterval, s.config.RetryInterval))
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *spool) file(it *item) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", it.seq, spoolExt))
+}
+
+func writeFile(name string, b []byte) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// encodeAlert serializes the alert and its events to the spool format.
+func encodeAlert(alert Alert, ts time.Time) ([]byte, error) {
+	a := spooledAlert{
+		Timestamp:   ts,
+		ID:          alert.ID,
+		Title:       alert.Title,
+		Text:        alert.Text,
+		Tags:        alert.Tags,
+		Labels:      alert.Labels,
+		Description: alert.Description,
+		Severity:    alert.Severity,
+		Events:      make([]spooledEvent, 0, len(alert.Events)),
+	}
+	for _, e := range alert.Events {
+		evt := spooledEvent{Raw: e.MarshalRaw()}
+		if e.PS != nil && !e.IsCreateProcess() && !e.IsProcessRundown() {
+			evt.PS = e.PS.Marshal()
+		}
+		a.Events = append(a.Events, evt)
+	}
+	return json.Marshal(a)
+}
+
+// decodeAlert recovers the alert and its events from the spool format.
+func decodeAlert(b []byte) (Alert, time.Time, error) {
+	var a spooledAlert
+	if err := json.Unmarshal(b, &a); err != nil {
+		return Alert{}, time.Time{}, err
+	}
+	alert := Alert{
+		ID:          a.ID,
+		Title:       a.Title,
+		Text:        a.Text,
+		Tags:        a.Tags,
+		Labels:      a.Labels,
+		Description: a.Description,
+		Severity:    a.Severity,
+		Events:      make([]*event.Event, 0, len(a.Events)),
+	}
+	for _, evt := range a.Events {
+		e, err := event.NewFromCapture(evt.Raw, capver.EvtSecV2)
+		if err != nil {
+			return Alert{}, time.Time{}, err
+		}
+		if len(evt.PS) > 0 {
+			ps, err := ptypes.NewFromCapture(evt.PS, section.New(section.Process, capver.ProcessSecV4, 0, uint32(len(evt.PS))))
+			if err != nil {
+				return Alert{}, time.Time{}, err
+			}
+			e.PS = ps
+		}
+		alert.Events = append(alert.Events, e)
+	}
+	return alert, a.Timestamp, nil
+}
+
+// spoolPath returns the spool directory. If the path
+// is not specified, the Spool directory in the
+// installation path is used.
+func spoolPath(path string) string {
+	if path != "" {
+		return path
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return filepath.Join(os.Getenv("PROGRAMFILES"), "Fibratus", "Spool")
+	}
+	return filepath.Join(filepath.Dir(exe), "..", "Spool")
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alertsender
+
+import (
+	"errors"
+	"expvar"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flakySender struct {
+	mu     sync.Mutex
+	fails  int
+	reject string
+	alerts []Alert
+}
+
+func (s *flakySender) Send(alert Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if alert.Title == s.reject {
+		return Permanent(errors.New("invalid payload"))
+	}
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("connection refused")
+	}
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+func (s *flakySender) Type() Type             { return Mail }
+func (s *flakySender) Shutdown() error        { return nil }
+func (s *flakySender) SupportsMarkdown() bool { return true }
+
+func (s *flakySender) titles() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	titles := make([]string, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		titles = append(titles, alert.Title)
+	}
+	return titles
+}
+
+func counter(m *expvar.Map, name string) int64 {
+	if v, ok := m.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func spoolConfig(path string) SpoolConfig {
+	return SpoolConfig{
+		Enabled:          true,
+		Path:             path,
+		MaxSize:          1,
+		MaxAge:           time.Hour,
+		RetryInterval:    time.Millisecond * 10,
+		MaxRetryInterval: time.Millisecond * 40,
+	}
+}
+
+func TestSpoolRetry(t *testing.T) {
+	path := t.TempDir()
+	sender := &flakySender{fails: 3}
+	retries := counter(spoolRetries, "mail:soc")
+	s, err := NewSpool("mail:soc", sender, spoolConfig(path))
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	require.NoError(t, s.Send(NewAlert("LSASS memory dump", "", nil, High)))
+	require.NoError(t, s.Send(NewAlert("Suspicious DLL loaded", "", nil, Critical)))
+
+	require.Eventually(t, func() bool { return len(sender.titles()) == 2 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, []string{"LSASS memory dump", "Suspicious DLL loaded"}, sender.titles())
+	assert.Equal(t, int64(3), counter(spoolRetries, "mail:soc")-retries)
+	assert.Equal(t, "0", spoolDepth.Get("mail:soc").String())
+
+	entries, err := os.ReadDir(filepath.Join(path, "mail-soc"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 0)
+}
+
+func TestSpoolReplay(t *testing.T) {
+	path := t.TempDir()
+	s, err := NewSpool("slack", &flakySender{fails: math.MaxInt}, spoolConfig(path))
+	require.NoError(t, err)
+
+	alert := NewAlert("LSASS memory dump", "Credential dumping via **comsvcs.dll**", []string{"Credential Access"}, High)
+	alert.ID = "335795af-831d-4c5b-b1b6-6c8c1d8e0f1e"
+	alert.Labels = map[string]string{"tactic.id": "TA0006"}
+	alert.Description = "Adversaries may attempt to dump credentials"
+	require.NoError(t, s.Send(alert))
+	require.NoError(t, s.Send(NewAlert("Suspicious DLL loaded", "", nil, Critical)))
+	require.Eventually(t, func() bool { return spoolDepth.Get("slack").String() == "2" }, time.Second*5, time.Millisecond*10)
+	require.NoError(t, s.Shutdown())
+
+	sender := &flakySender{}
+	replayed := counter(spoolReplayed, "slack")
+	s, err = NewSpool("slack", sender, spoolConfig(path))
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	require.Eventually(t, func() bool { return len(sender.titles()) == 2 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, []string{"LSASS memory dump", "Suspicious DLL loaded"}, sender.titles())
+	assert.Equal(t, int64(2), counter(spoolReplayed, "slack")-replayed)
+
+	r := sender.alerts[0]
+	assert.Equal(t, alert.ID, r.ID)
+	assert.Equal(t, alert.Text, r.Text)
+	assert.Equal(t, alert.Tags, r.Tags)
+	assert.Equal(t, alert.Labels, r.Labels)
+	assert.Equal(t, alert.Description, r.Description)
+	assert.Equal(t, High, r.Severity)
+}
+
+func TestSpoolEviction(t *testing.T) {
+	config := spoolConfig(t.TempDir())
+	config.MaxAge = time.Millisecond * 100
+	dropped := counter(spoolDropped, "webhook")
+	s, err := NewSpool("webhook", &flakySender{fails: math.MaxInt}, config)
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	require.NoError(t, s.Send(NewAlert("LSASS memory dump", "", nil, High)))
+	time.Sleep(time.Millisecond * 150)
+	require.NoError(t, s.Send(NewAlert("Suspicious DLL loaded", "", nil, Critical)))
+
+	assert.Equal(t, int64(1), counter(spoolDropped, "webhook")-dropped)
+	assert.Equal(t, "1", spoolDepth.Get("webhook").String())
+}
+
+func TestSpoolRejected(t *testing.T) {
+	path := t.TempDir()
+	sender := &flakySender{reject: "LSASS memory dump"}
+	rejected := counter(spoolRejected, "pagerduty")
+	retries := counter(spoolRetries, "pagerduty")
+	s, err := NewSpool("pagerduty", sender, spoolConfig(path))
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	require.NoError(t, s.Send(NewAlert("LSASS memory dump", "", nil, High)))
+	require.NoError(t, s.Send(NewAlert("Suspicious DLL loaded", "", nil, Critical)))
+
+	// the rejected alert doesn't block subsequent alerts
+	require.Eventually(t, func() bool { return len(sender.titles()) == 1 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, []string{"Suspicious DLL loaded"}, sender.titles())
+	assert.Equal(t, int64(1), counter(spoolRejected, "pagerduty")-rejected)
+	assert.Equal(t, int64(0), counter(spoolRetries, "pagerduty")-retries)
+
+	entries, err := os.ReadDir(filepath.Join(path, "pagerduty"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 0)
+}
+
+func TestSpoolMaxRetries(t *testing.T) {
+	path := t.TempDir()
+	sender := &flakySender{fails: 3}
+	rejected := counter(spoolRejected, "webhook")
+	retries := counter(spoolRetries, "webhook")
+	config := spoolConfig(path)
+	config.MaxRetries = 2
+	s, err := NewSpool("webhook", sender, config)
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	require.NoError(t, s.Send(NewAlert("LSASS memory dump", "", nil, High)))
+	require.NoError(t, s.Send(NewAlert("Suspicious DLL loaded", "", nil, Critical)))
+
+	// the alert that exhausted retries doesn't block subsequent alerts
+	require.Eventually(t, func() bool { return len(sender.titles()) == 1 }, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, []string{"Suspicious DLL loaded"}, sender.titles())
+	assert.Equal(t, int64(1), counter(spoolRejected, "webhook")-rejected)
+	assert.Equal(t, int64(2), counter(spoolRetries, "webhook")-retries)
+}
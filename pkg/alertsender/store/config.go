@@ -0,0 +1,62 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package store
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	enabled = "alertstore.enabled"
+	path    = "alertstore.path"
+	maxSize = "alertstore.max-size"
+	maxAge  = "alertstore.max-age"
+)
+
+// Config contains the settings of the local alert store.
+type Config struct {
+	// Enabled indicates if alerts are recorded in the local alert store.
+	Enabled bool `json:"alertstore.enabled" yaml:"alertstore.enabled"`
+	// Path is the directory where alert store segments are written.
+	Path string `json:"alertstore.path" yaml:"alertstore.path"`
+	// MaxSize is the maximum size of the alert store in megabytes.
+	// The oldest segments are removed when the size is exceeded.
+	MaxSize int `json:"alertstore.max-size" yaml:"alertstore.max-size"`
+	// MaxAge is the retention period of the recorded alerts.
+	MaxAge time.Duration `json:"alertstore.max-age" yaml:"alertstore.max-age"`
+}
+
+// AddFlags registers persistent flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(enabled, true, "Indicates if alerts are recorded in the local alert store")
+	flags.String(path, "", "Specifies the directory where alerts are stored. Defaults to the Alerts directory in the installation path")
+	flags.Int(maxSize, 100, "Specifies the maximum size of the alert store in megabytes")
+	flags.Duration(maxAge, time.Hour*24*30, "Specifies the retention period of the recorded alerts")
+}
+
+// InitFromViper initializes alert store config from Viper.
+func (c *Config) InitFromViper(v *viper.Viper) {
+	c.Enabled = v.GetBool(enabled)
+	c.Path = v.GetString(path)
+	c.MaxSize = v.GetInt(maxSize)
+	c.MaxAge = v.GetDuration(maxAge)
+}
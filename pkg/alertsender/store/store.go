@@ -0,0 +1,510 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package store implements the local append-only alert store. Alerts
+// are appended to segment files as JSON lines. Acknowledgements are
+// appended as separate entries, so the segment files are never
+// rewritten. The store is bounded by size and age. When either limit
+// is exceeded, the oldest segments are removed.
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNotFound is returned when the alert doesn't exist in the store
+var ErrNotFound = errors.New("alert not found")
+
+const (
+	// segmentExt is the extension of the segment files
+	segmentExt = ".log"
+	// minSegmentSize is the minimum size of the segment file
+	minSegmentSize = 1024 * 1024
+	// maxLineSize is the maximum size of a single store entry
+	maxLineSize = 64 * 1024 * 1024
+
+	opAlert = "alert"
+	opAck   = "ack"
+)
+
+var (
+	// alertsRecorded counts the number of alerts written to the store
+	alertsRecorded = expvar.NewInt("alertstore.alerts.recorded")
+	// segmentsEvicted counts the number of removed segments
+	segmentsEvicted = expvar.NewInt("alertstore.segments.evicted")
+)
+
+// Record is the alert stored in the alert store.
+type Record struct {
+	// ID uniquely identifies the alert in the store.
+	ID string `json:"id"`
+	// Timestamp is the time the alert was recorded.
+	Timestamp time.Time `json:"timestamp"`
+	// RuleID is the alert identifier. For detection rules, this
+	// is the rule identifier.
+	RuleID string `json:"rule_id,omitempty"`
+	// Rule is the alert title. For detection rules, this is the rule name.
+	Rule string `json:"rule"`
+	// Severity is the alert severity.
+	Severity string `json:"severity"`
+	// Tags contains alert tags.
+	Tags []string `json:"tags,omitempty"`
+	// Acked indicates if the alert is acknowledged.
+	Acked bool `json:"acked"`
+	// AckedAt is the time the alert was acknowledged.
+	AckedAt *time.Time `json:"acked_at,omitempty"`
+	// Alert is the JSON representation of the alert with its events.
+	Alert json.RawMessage `json:"alert,omitempty"`
+}
+
+// Query contains the conditions the alerts returned from the store must satisfy.
+type Query struct {
+	// Rule matches alerts by rule identifier or the substring of the rule name.
+	Rule string
+	// Severities matches alerts with any of the severities.
+	Severities []string
+	// Since matches alerts recorded at or after the time.
+	Since time.Time
+	// Until matches alerts recorded before the time.
+	Until time.Time
+	// Acked matches alerts by acknowledgement state if not nil.
+	Acked *bool
+	// Limit is the maximum number of returned alerts. Zero means no limit.
+	Limit int
+}
+
+// Matches determines if the record satisfies all query conditions.
+func (q Query) Matches(r Record) bool {
+	if q.Rule != "" && r.RuleID != q.Rule && !strings.Contains(strings.ToLower(r.Rule), strings.ToLower(q.Rule)) {
+		return false
+	}
+	if len(q.Severities) > 0 && !slices.ContainsFunc(q.Severities, func(s string) bool { return strings.EqualFold(s, r.Severity) }) {
+		return false
+	}
+	if !q.Since.IsZero() && r.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !r.Timestamp.Before(q.Until) {
+		return false
+	}
+	if q.Acked != nil && *q.Acked != r.Acked {
+		return false
+	}
+	return true
+}
+
+// entry is a single line in the segment file
+type entry struct {
+	Op        string    `json:"op"`
+	Record    *Record   `json:"record,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// segment is the append-only file storing store entries
+type segment struct {
+	seq  uint64
+	path string
+	size int64
+	last time.Time
+}
+
+// index points to the alert entry in the segment file
+type index struct {
+	Record
+	seq    uint64
+	offset int64
+	length int
+}
+
+// Store is the append-only alert store.
+type Store struct {
+	mu sync.RWMutex
+
+	dir         string
+	maxSize     int64
+	maxAge      time.Duration
+	segmentSize int64
+
+	segments []*segment
+	w        *os.File
+
+	indices []*index
+	ids     map[string]*index
+}
+
+// Open opens the alert store located in the configured directory. Existing
+// segments are scanned to rebuild the alert index.
+func Open(c Config) (*Store, error) {
+	s := &Store{
+		dir:     storePath(c.Path),
+		maxSize: int64(c.MaxSize) * 1024 * 1024,
+		maxAge:  c.MaxAge,
+		ids:     make(map[string]*index),
+	}
+	s.segmentSize = max(s.maxSize/10, minSegmentSize)
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create alert store directory: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(s.dir, "*"+segmentExt))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(file), segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		s.segments = append(s.segments, &segment{seq: seq, path: file})
+	}
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i].seq < s.segments[j].seq })
+
+	for _, seg := range s.segments {
+		if err := s.load(seg); err != nil {
+			return nil, fmt.Errorf("unable to load alert store segment %s: %v", seg.path, err)
+		}
+	}
+
+	if len(s.segments) == 0 || s.segments[len(s.segments)-1].size >= s.segmentSize {
+		if err := s.rotate(); err != nil {
+			return nil, err
+		}
+	} else {
+		seg := s.segments[len(s.segments)-1]
+		s.w, err = os.OpenFile(seg.path, os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.evict()
+
+	return s, nil
+}
+
+// load reads all entries from the segment and populates the alert index.
+func (s *Store) load(seg *segment) error {
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// discard the partially written entry
+			if len(line) > 0 {
+				if err := os.Truncate(seg.path, offset); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var e entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			log.Warnf("skipping malformed alert store entry in %s at offset %d: %v", seg.path, offset, err)
+			offset += int64(len(line))
+			continue
+		}
+		s.apply(e, seg, offset, len(line))
+		offset += int64(len(line))
+	}
+	seg.size = offset
+	return nil
+}
+
+// apply updates the alert index from the store entry.
+func (s *Store) apply(e entry, seg *segment, offset int64, length int) {
+	switch e.Op {
+	case opAlert:
+		if e.Record == nil {
+			return
+		}
+		idx := &index{Record: *e.Record, seq: seg.seq, offset: offset, length: length}
+		idx.Alert = nil
+		s.indices = append(s.indices, idx)
+		s.ids[idx.ID] = idx
+		seg.last = idx.Timestamp
+	case opAck:
+		if idx, ok := s.ids[e.ID]; ok {
+			ts := e.Timestamp
+			idx.Acked = true
+			idx.AckedAt = &ts
+		}
+		seg.last = e.Timestamp
+	}
+}
+
+// Record appends the alert to the store.
+func (s *Store) Record(alert alertsender.Alert) error {
+	b, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	rec := &Record{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		RuleID:    alert.ID,
+		Rule:      alert.Title,
+		Severity:  alert.Severity.String(),
+		Tags:      alert.Tags,
+		Alert:     b,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.append(entry{Op: opAlert, Record: rec}); err != nil {
+		return err
+	}
+	alertsRecorded.Add(1)
+
+	return nil
+}
+
+// Ack acknowledges the alert with the specified identifier.
+func (s *Store) Ack(id string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx, ok := s.ids[id]
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+	if idx.Acked {
+		return idx.Record, nil
+	}
+	if err := s.append(entry{Op: opAck, ID: id, Timestamp: time.Now()}); err != nil {
+		return Record{}, err
+	}
+	return idx.Record, nil
+}
+
+// append writes the entry to the active segment and updates the index.
+// The active segment is rotated when it reaches the segment size.
+func (s *Store) append(e entry) error {
+	if s.w == nil {
+		return os.ErrClosed
+	}
+	seg := s.segments[len(s.segments)-1]
+	// rotate the segment with expired entries so it can be evicted
+	if seg.size > 0 && s.maxAge > 0 && time.Since(seg.last) > s.maxAge {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+		seg = s.segments[len(s.segments)-1]
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if _, err := s.w.Write(b); err != nil {
+		return err
+	}
+	s.apply(e, seg, seg.size, len(b))
+	seg.size += int64(len(b))
+
+	if seg.size >= s.segmentSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	s.evict()
+
+	return nil
+}
+
+// rotate closes the active segment and creates a new one.
+func (s *Store) rotate() error {
+	var seq uint64 = 1
+	if len(s.segments) > 0 {
+		seq = s.segments[len(s.segments)-1].seq + 1
+	}
+	seg := &segment{seq: seq, path: filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, segmentExt))}
+	f, err := os.OpenFile(seg.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to create alert store segment: %v", err)
+	}
+	if s.w != nil {
+		if err := s.w.Close(); err != nil {
+			log.Warnf("unable to close alert store segment: %v", err)
+		}
+	}
+	s.w = f
+	s.segments = append(s.segments, seg)
+	return nil
+}
+
+// evict removes the oldest segments if the store exceeds the maximum
+// size or contains expired alerts. The active segment is never removed.
+func (s *Store) evict() {
+	var size int64
+	for _, seg := range s.segments {
+		size += seg.size
+	}
+	for len(s.segments) > 1 {
+		seg := s.segments[0]
+		expired := s.maxAge > 0 && time.Since(seg.last) > s.maxAge
+		if !expired && (s.maxSize <= 0 || size <= s.maxSize) {
+			break
+		}
+		if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+			log.Warnf("unable to remove alert store segment %s: %v", seg.path, err)
+			break
+		}
+		size -= seg.size
+		s.segments = s.segments[1:]
+		n := 0
+		for n < len(s.indices) && s.indices[n].seq == seg.seq {
+			delete(s.ids, s.indices[n].ID)
+			n++
+		}
+		s.indices = s.indices[n:]
+		segmentsEvicted.Add(1)
+	}
+}
+
+// Query returns alerts satisfying the query conditions, most recent
+// alerts first. Returned records don't contain the alert body.
+func (s *Store) Query(q Query) []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records := make([]Record, 0)
+	for i := len(s.indices) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(records) >= q.Limit {
+			break
+		}
+		if r := s.indices[i].Record; q.Matches(r) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// Get returns the alert record along with the alert body.
+func (s *Store) Get(id string) (Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx, ok := s.ids[id]
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+	return s.read(idx)
+}
+
+// Export writes alerts satisfying the query conditions to the writer
+// as JSON lines in the order they were recorded. Exported records
+// contain the alert body.
+func (s *Store) Export(q Query, w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	enc := json.NewEncoder(w)
+	n := 0
+	for _, idx := range s.indices {
+		if q.Limit > 0 && n >= q.Limit {
+			break
+		}
+		if !q.Matches(idx.Record) {
+			continue
+		}
+		r, err := s.read(idx)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+		n++
+	}
+	return nil
+}
+
+// read reads the alert body from the segment file.
+func (s *Store) read(idx *index) (Record, error) {
+	f, err := os.Open(filepath.Join(s.dir, fmt.Sprintf("%020d%s", idx.seq, segmentExt)))
+	if err != nil {
+		return Record{}, err
+	}
+	defer f.Close()
+	if idx.length > maxLineSize {
+		return Record{}, fmt.Errorf("alert entry exceeds the maximum size: %d", idx.length)
+	}
+	b := make([]byte, idx.length)
+	if _, err := f.ReadAt(b, idx.offset); err != nil {
+		return Record{}, err
+	}
+	var e entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return Record{}, err
+	}
+	if e.Record == nil {
+		return Record{}, ErrNotFound
+	}
+	r := idx.Record
+	r.Alert = e.Record.Alert
+	return r, nil
+}
+
+// Close closes the active segment.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w == nil {
+		return nil
+	}
+	err := s.w.Close()
+	s.w = nil
+	return err
+}
+
+// storePath returns the alert store directory.
+func storePath(path string) string {
+	if path != "" {
+		return path
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return filepath.Join(os.Getenv("PROGRAMFILES"), "Fibratus", "Alerts")
+	}
+	return filepath.Join(filepath.Dir(exe), "..", "Alerts")
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Config{Path: dir, MaxSize: 10, MaxAge: time.Hour})
+	require.NoError(t, err)
+
+	alert := alertsender.NewAlert("LSASS memory dumping via legitimate or offensive tools", "", []string{"credential access"}, alertsender.Critical)
+	alert.ID = "335795af-246b-483e-8657-09a30c102e63"
+	require.NoError(t, s.Record(alert))
+	require.NoError(t, s.Record(alertsender.NewAlert("Suspicious DLL loaded by Microsoft Office process", "", nil, alertsender.Medium)))
+	require.NoError(t, s.Record(alertsender.NewAlert("Unsigned DLL injection via remote thread", "", nil, alertsender.High)))
+
+	records := s.Query(Query{})
+	require.Len(t, records, 3)
+	// most recent alerts come first
+	assert.Equal(t, "Unsigned DLL injection via remote thread", records[0].Rule)
+	assert.Nil(t, records[0].Alert)
+
+	var tests = []struct {
+		q     Query
+		rules []string
+	}{
+		{Query{Rule: "335795af-246b-483e-8657-09a30c102e63"}, []string{"LSASS memory dumping via legitimate or offensive tools"}},
+		{Query{Rule: "dll"}, []string{"Unsigned DLL injection via remote thread", "Suspicious DLL loaded by Microsoft Office process"}},
+		{Query{Severities: []string{"critical", "HIGH"}}, []string{"Unsigned DLL injection via remote thread", "LSASS memory dumping via legitimate or offensive tools"}},
+		{Query{Limit: 1}, []string{"Unsigned DLL injection via remote thread"}},
+		{Query{Since: time.Now().Add(time.Minute)}, []string{}},
+		{Query{Until: time.Now().Add(-time.Minute)}, []string{}},
+	}
+
+	for _, tt := range tests {
+		rules := make([]string, 0)
+		for _, r := range s.Query(tt.q) {
+			rules = append(rules, r.Rule)
+		}
+		assert.Equal(t, tt.rules, rules)
+	}
+
+	id := records[2].ID
+	r, err := s.Get(id)
+	require.NoError(t, err)
+	var a map[string]any
+	require.NoError(t, json.Unmarshal(r.Alert, &a))
+	assert.Equal(t, "335795af-246b-483e-8657-09a30c102e63", a["id"])
+	assert.Equal(t, "critical", a["severity"])
+
+	_, err = s.Ack("unknown")
+	require.ErrorIs(t, err, ErrNotFound)
+	r, err = s.Ack(id)
+	require.NoError(t, err)
+	assert.True(t, r.Acked)
+	require.NotNil(t, r.AckedAt)
+
+	acked := true
+	assert.Len(t, s.Query(Query{Acked: &acked}), 1)
+	acked = false
+	assert.Len(t, s.Query(Query{Acked: &acked}), 2)
+
+	require.NoError(t, s.Close())
+
+	// reopen the store and ensure the index and
+	// acknowledgement state are rebuilt
+	s, err = Open(Config{Path: dir, MaxSize: 10, MaxAge: time.Hour})
+	require.NoError(t, err)
+	defer s.Close()
+
+	records = s.Query(Query{})
+	require.Len(t, records, 3)
+	assert.Equal(t, id, records[2].ID)
+	assert.True(t, records[2].Acked)
+
+	var b bytes.Buffer
+	require.NoError(t, s.Export(Query{Acked: &acked}, &b))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 2)
+	var rec Record
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	assert.Equal(t, "Suspicious DLL loaded by Microsoft Office process", rec.Rule)
+	assert.NotEmpty(t, rec.Alert)
+}
+
+func TestStoreEviction(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Config{Path: dir, MaxSize: 2, MaxAge: time.Hour})
+	require.NoError(t, err)
+	defer s.Close()
+
+	// each alert is larger than 100KB, so the segment is rotated
+	// after every ten alerts and the oldest segments are evicted
+	// once the store exceeds 2MB
+	text := strings.Repeat("a", 100*1024)
+	for i := 0; i < 50; i++ {
+		require.NoError(t, s.Record(alertsender.NewAlert("Suspicious DLL loaded by Microsoft Office process", text, nil, alertsender.Medium)))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	require.NoError(t, err)
+	var size int64
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		require.NoError(t, err)
+		size += fi.Size()
+	}
+	assert.LessOrEqual(t, size, int64(2*1024*1024+minSegmentSize))
+	records := s.Query(Query{})
+	assert.Less(t, len(records), 50)
+	_, err = s.Get(records[len(records)-1].ID)
+	require.NoError(t, err)
+}
+
+func TestStoreExpiredSegments(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Config{Path: dir, MaxSize: 10, MaxAge: time.Millisecond * 50})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Record(alertsender.NewAlert("LSASS memory dumping via legitimate or offensive tools", "", nil, alertsender.Critical)))
+	time.Sleep(time.Millisecond * 100)
+	require.NoError(t, s.Record(alertsender.NewAlert("Suspicious DLL loaded by Microsoft Office process", "", nil, alertsender.Medium)))
+
+	records := s.Query(Query{})
+	require.Len(t, records, 1)
+	assert.Equal(t, "Suspicious DLL loaded by Microsoft Office process", records[0].Rule)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	enabled  = "alertsenders.syslog.enabled"
+	network  = "alertsenders.syslog.network"
+	address  = "alertsenders.syslog.address"
+	format   = "alertsenders.syslog.format"
+	payload  = "alertsenders.syslog.payload"
+	framing  = "alertsenders.syslog.framing"
+	facility = "alertsenders.syslog.facility"
+	appName  = "alertsenders.syslog.app-name"
+	pen      = "alertsenders.syslog.enterprise-id"
+	timeout  = "alertsenders.syslog.timeout"
+)
+
+// Config contains the configuration for the syslog alert sender.
+type Config struct {
+	// Enabled indicates whether syslog alert sender is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Network is the transport protocol. Can be udp, tcp, or tls.
+	Network string `mapstructure:"network"`
+	// Address is the syslog server address in the host:port format.
+	Address string `mapstructure:"address"`
+	// Format is the syslog message format. Can be rfc5424 or rfc3164.
+	Format string `mapstructure:"format"`
+	// Payload is the format of the message content. Can be text, json, cef, or leef.
+	Payload string `mapstructure:"payload"`
+	// Framing is the message framing method on stream transports. Can be
+	// octet-counting or non-transparent.
+	Framing string `mapstructure:"framing"`
+	// Facility is the syslog facility keyword, e.g. local0.
+	Facility string `mapstructure:"facility"`
+	// AppName identifies the application that originates messages.
+	AppName string `mapstructure:"app-name"`
+	// EnterpriseID is the private enterprise number used in structured data element identifiers.
+	EnterpriseID int `mapstructure:"enterprise-id"`
+	// Timeout is the connection and write timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// TLSCA represents the path of the certificate file that is associated with the Certification Authority (CA).
+	TLSCA string `mapstructure:"tls-ca"`
+	// TLSCert is the path to the certificate file.
+	TLSCert string `mapstructure:"tls-cert"`
+	// TLSKey represents the path to the public/private key file.
+	TLSKey string `mapstructure:"tls-key"`
+	// TLSInsecureSkipVerify skips the chain and host verification.
+	TLSInsecureSkipVerify bool `mapstructure:"tls-insecure-skip-verify"`
+}
+
+// AddFlags registers persistent flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(enabled, false, "Indicates whether syslog alert sender is enabled")
+	flags.String(network, "udp", "Specifies the transport protocol. Possible values are udp, tcp, and tls")
+	flags.String(address, "localhost:514", "Specifies the syslog server address in the host:port format")
+	flags.String(format, "rfc5424", "Specifies the syslog message format. Possible values are rfc5424 and rfc3164")
+	flags.String(payload, "text", "Specifies the format of the message content. Possible values are text, json, cef, and leef")
+	flags.String(framing, "octet-counting", "Specifies the message framing method on tcp and tls transports. Possible values are octet-counting and non-transparent")
+	flags.String(facility, "local0", "Specifies the syslog facility keyword")
+	flags.String(appName, "fibratus", "Identifies the application that originates messages")
+	flags.Int(pen, 32473, "Specifies the private enterprise number used in structured data element identifiers")
+	flags.Duration(timeout, time.Second*5, "Specifies the connection and write timeout")
+}
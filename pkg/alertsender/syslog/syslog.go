@@ -0,0 +1,212 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/util/syslog"
+	"github.com/rabbitstack/fibratus/pkg/util/tls"
+	"github.com/rabbitstack/fibratus/pkg/util/version"
+)
+
+// severities maps alert severities to syslog severities.
+var severities = map[alertsender.Severity]syslog.Severity{
+	alertsender.Normal:   syslog.Notice,
+	alertsender.Medium:   syslog.Warning,
+	alertsender.High:     syslog.Error,
+	alertsender.Critical: syslog.Critical,
+}
+
+// cefSeverities maps alert severities to CEF severities.
+var cefSeverities = map[alertsender.Severity]int{
+	alertsender.Normal:   3,
+	alertsender.Medium:   5,
+	alertsender.High:     8,
+	alertsender.Critical: 10,
+}
+
+type sys struct {
+	writer   *syslog.Writer
+	format   syslog.Format
+	payload  string
+	facility syslog.Facility
+	appName  string
+	sdID     string
+}
+
+func init() {
+	alertsender.Register(alertsender.Syslog, makeSender)
+}
+
+// makeSender constructs a new instance of the syslog alert sender.
+// The connection to the syslog server is established on the first
+// alert.
+func makeSender(config alertsender.Config) (alertsender.Sender, error) {
+	c, ok := config.Sender.(Config)
+	if !ok {
+		return nil, alertsender.ErrInvalidConfig(alertsender.Syslog)
+	}
+	format, err := syslog.ParseFormat(c.Format)
+	if err != nil {
+		return nil, err
+	}
+	framing, err := syslog.ParseFraming(c.Framing)
+	if err != nil {
+		return nil, err
+	}
+	facility, err := syslog.ParseFacility(c.Facility)
+	if err != nil {
+		return nil, err
+	}
+	switch c.Payload {
+	case "text", "json", "cef", "leef":
+	case "":
+		c.Payload = "text"
+	default:
+		return nil, fmt.Errorf("unknown syslog payload format %q", c.Payload)
+	}
+	if c.EnterpriseID == 0 {
+		c.EnterpriseID = 32473
+	}
+	tlsConfig, err := tls.MakeConfig(c.TLSCert, c.TLSKey, c.TLSCA, c.TLSInsecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := syslog.NewWriter(c.Network, c.Address, tlsConfig, framing, c.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &sys{
+		writer:   writer,
+		format:   format,
+		payload:  c.Payload,
+		facility: facility,
+		appName:  c.AppName,
+		sdID:     "alert@" + strconv.Itoa(c.EnterpriseID),
+	}, nil
+}
+
+// Send delivers the alert to the syslog server. If the
+// connection is dropped, the sender reconnects and
+// tries to deliver the alert once again.
+func (s *sys) Send(alert alertsender.Alert) error {
+	msg, err := s.message(alert)
+	if err != nil {
+		return err
+	}
+	b := msg.Encode(s.format)
+	err = s.writer.Write(b)
+	if err == nil {
+		return nil
+	}
+	if err := s.writer.Connect(); err != nil {
+		return fmt.Errorf("unable to connect to syslog server: %v", err)
+	}
+	return s.writer.Write(b)
+}
+
+func (s *sys) message(alert alertsender.Alert) (syslog.Message, error) {
+	m := syslog.Message{
+		Facility:  s.facility,
+		Severity:  severities[alert.Severity],
+		Timestamp: time.Now(),
+		AppName:   s.appName,
+		MsgID:     "alert",
+	}
+	if len(alert.Events) > 0 {
+		m.Hostname = alert.Events[0].Host
+		m.ProcID = strconv.FormatUint(uint64(alert.Events[0].PID), 10)
+	}
+	if s.format == syslog.RFC5424 {
+		m.StructuredData = []syslog.SDElement{s.structuredData(alert)}
+	}
+	switch s.payload {
+	case "json":
+		b, err := json.Marshal(alert)
+		if err != nil {
+			return m, err
+		}
+		m.Msg = string(b)
+	case "cef":
+		m.Msg = syslog.CEF("Fibratus", "Fibratus", version.Get(), alert.ID, alert.Title, cefSeverities[alert.Severity], s.fields(alert))
+	case "leef":
+		attrs := append([]syslog.Field{{Key: "sev", Value: strconv.Itoa(cefSeverities[alert.Severity])}}, s.fields(alert)...)
+		m.Msg = syslog.LEEF("Fibratus", "Fibratus", version.Get(), alert.ID, attrs)
+	default:
+		m.Msg = alert.String(false)
+	}
+	return m, nil
+}
+
+// structuredData builds the alert structured data element.
+func (s *sys) structuredData(alert alertsender.Alert) syslog.SDElement {
+	e := syslog.SDElement{
+		ID: s.sdID,
+		Params: []syslog.SDParam{
+			{Name: "id", Value: alert.ID},
+			{Name: "title", Value: alert.Title},
+			{Name: "severity", Value: alert.Severity.String()},
+		},
+	}
+	if len(alert.Tags) > 0 {
+		e.Params = append(e.Params, syslog.SDParam{Name: "tags", Value: strings.Join(alert.Tags, ",")})
+	}
+	labels := make([]string, 0, len(alert.Labels))
+	for k := range alert.Labels {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+	for _, k := range labels {
+		e.Params = append(e.Params, syslog.SDParam{Name: k, Value: alert.Labels[k]})
+	}
+	return e
+}
+
+// fields returns the CEF extension or LEEF attributes
+// describing the alert and the originating process.
+func (s *sys) fields(alert alertsender.Alert) []syslog.Field {
+	fields := []syslog.Field{
+		{Key: "msg", Value: alert.Text},
+		{Key: "cat", Value: strings.Join(alert.Tags, ",")},
+	}
+	if len(alert.Events) == 0 {
+		return fields
+	}
+	evt := alert.Events[0]
+	fields = append(fields, syslog.Field{Key: "dvchost", Value: evt.Host})
+	if evt.PS != nil {
+		fields = append(fields,
+			syslog.Field{Key: "spid", Value: strconv.FormatUint(uint64(evt.PS.PID), 10)},
+			syslog.Field{Key: "sproc", Value: evt.PS.Exe},
+			syslog.Field{Key: "suser", Value: evt.PS.Username},
+		)
+	}
+	return fields
+}
+
+func (s *sys) Type() alertsender.Type { return alertsender.Syslog }
+func (s *sys) Shutdown() error        { return s.writer.Close() }
+func (s *sys) SupportsMarkdown() bool { return false }
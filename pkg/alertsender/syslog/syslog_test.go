@@ -0,0 +1,86 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogSender(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var tests = []struct {
+		payload  string
+		expected []string
+	}{
+		{
+			"text",
+			[]string{`<130>1 `, ` archrabbit fibratus 4024 alert [alert@32473 id="335795af" title="LSASS memory dumping" severity="critical" tags="T1003" tactic.id="TA0006"] LSASS memory dumping` + "\n\nrundll32.exe accessed lsass.exe"},
+		},
+		{
+			"cef",
+			[]string{`|335795af|LSASS memory dumping|10|msg=rundll32.exe accessed lsass.exe cat=T1003 dvchost=archrabbit spid=4024 sproc=C:\\Windows\\System32\\rundll32.exe suser=admin`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			s, err := alertsender.Load(alertsender.Config{
+				Type: alertsender.Syslog,
+				Sender: Config{
+					Enabled:  true,
+					Network:  "udp",
+					Address:  conn.LocalAddr().String(),
+					Payload:  tt.payload,
+					Facility: "local0",
+					AppName:  "fibratus",
+					Timeout:  time.Second,
+				},
+			})
+			require.NoError(t, err)
+			defer s.Shutdown()
+
+			alert := alertsender.NewAlertWithEvents("LSASS memory dumping", "rundll32.exe accessed lsass.exe", []string{"T1003"}, alertsender.Critical,
+				[]*event.Event{{PID: 4024, Host: "archrabbit", PS: &pstypes.PS{PID: 4024, Exe: `C:\Windows\System32\rundll32.exe`, Username: "admin"}}})
+			alert.ID = "335795af"
+			alert.Labels = map[string]string{"tactic.id": "TA0006"}
+			require.NoError(t, s.Send(alert))
+
+			buf := make([]byte, 2048)
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+			n, _, err := conn.ReadFrom(buf)
+			require.NoError(t, err)
+			msg := string(buf[:n])
+			for _, expected := range tt.expected {
+				assert.True(t, strings.Contains(msg, expected), msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	enabled = "alertsenders.webhook.enabled"
+)
+
+// Config contains the configuration for the webhook alert sender. Each webhook
+// endpoint is declared as a separate sender instance, e.g. webhook:teams.
+type Config struct {
+	// Enabled indicates whether webhook alert sender is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// URL is the webhook endpoint URL.
+	URL string `mapstructure:"url"`
+	// Method is the HTTP method used to submit alerts.
+	Method string `mapstructure:"method"`
+	// Headers contains additional HTTP headers sent with every request.
+	Headers map[string]string `mapstructure:"headers"`
+	// ContentType represents the request body content type.
+	ContentType string `mapstructure:"content-type"`
+	// Template is the Go template which renders the request body from
+	// the alert. Sprig template functions are available. If empty, the
+	// alert is rendered as JSON.
+	Template string `mapstructure:"template"`
+	// Secret is the key for signing the request body with HMAC-SHA256.
+	Secret string `mapstructure:"secret"`
+	// SignatureHeader is the header that carries the request signature.
+	SignatureHeader string `mapstructure:"signature-header"`
+	// Timeout is the maximum time a single request is allowed to take.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// TLSInsecureSkipVerify indicates if the server certificate verification is skipped.
+	TLSInsecureSkipVerify bool `mapstructure:"tls-insecure-skip-verify"`
+}
+
+// AddFlags registers persistent flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(enabled, false, "Indicates whether webhook alert sender is enabled")
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/util/version"
+)
+
+const (
+	defaultMethod          = http.MethodPost
+	defaultContentType     = "application/json"
+	defaultSignatureHeader = "X-Fibratus-Signature"
+	defaultTimeout         = time.Second * 10
+	defaultTemplate        = "{{ toJson . }}"
+)
+
+// errUnexpectedStatus is returned when the endpoint responds with a non-2xx status code
+var errUnexpectedStatus = func(name string, code int, body []byte) error {
+	return fmt.Errorf("[%s] webhook responded with %d status code: %s", name, code, strings.TrimSpace(string(body)))
+}
+
+type webhook struct {
+	name   string
+	config Config
+	tmpl   *template.Template
+	client *http.Client
+}
+
+func init() {
+	alertsender.Register(alertsender.Webhook, makeSender)
+}
+
+// makeSender constructs a new instance of the webhook alert sender.
+func makeSender(config alertsender.Config) (alertsender.Sender, error) {
+	c, ok := config.Sender.(Config)
+	if !ok {
+		return nil, alertsender.ErrInvalidConfig(alertsender.Webhook)
+	}
+	name := config.InstanceName()
+	if c.URL == "" {
+		return nil, fmt.Errorf("[%s] webhook url is required", name)
+	}
+	if c.Method == "" {
+		c.Method = defaultMethod
+	}
+	if c.ContentType == "" {
+		c.ContentType = defaultContentType
+	}
+	if c.SignatureHeader == "" {
+		c.SignatureHeader = defaultSignatureHeader
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultTimeout
+	}
+	text := c.Template
+	if text == "" {
+		text = defaultTemplate
+	}
+	tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid [%s] webhook template: %v", name, err)
+	}
+	client := &http.Client{
+		Timeout: c.Timeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+			//nolint:gosec
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: c.TLSInsecureSkipVerify},
+		},
+	}
+	return &webhook{name: name, config: c, tmpl: tmpl, client: client}, nil
+}
+
+func (w *webhook) Send(alert alertsender.Alert) error {
+	return w.SendContext(context.Background(), alert)
+}
+
+// SendContext renders and posts the alert to the webhook endpoint. The
+// sender doesn't retry failed requests. Instead, connection errors,
+// timeouts, 429, and 5xx responses are returned as transient errors, so
+// the delivery is retried by the rule action executor or the spool. The
+// template rendering errors and other client errors are permanent.
+func (w *webhook) SendContext(ctx context.Context, alert alertsender.Alert) error {
+	var body bytes.Buffer
+	if err := w.tmpl.Execute(&body, alert); err != nil {
+		return alertsender.Permanent(fmt.Errorf("unable to render [%s] webhook template: %v", w.name, err))
+	}
+	req, err := http.NewRequestWithContext(ctx, w.config.Method, w.config.URL, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return alertsender.Permanent(err)
+	}
+	req.Header.Set("Content-Type", w.config.ContentType)
+	req.Header.Set("User-Agent", version.ProductToken())
+	for k, v := range w.config.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.config.Secret != "" {
+		req.Header.Set(w.config.SignatureHeader, Sign([]byte(w.config.Secret), body.Bytes()))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = errUnexpectedStatus(w.name, resp.StatusCode, b)
+	if alertsender.IsPermanentStatus(resp.StatusCode) {
+		return alertsender.Permanent(err)
+	}
+	return err
+}
+
+// Sign computes the HMAC-SHA256 signature of the request body.
+// The signature is hex-encoded and prefixed with the sha256=
+// algorithm identifier.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *webhook) Type() alertsender.Type { return alertsender.Webhook }
+func (w *webhook) Shutdown() error        { return nil }
+func (w *webhook) SupportsMarkdown() bool { return true }
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSender(t *testing.T) {
+	var (
+		teams []byte
+		soar  []byte
+		sig   string
+		auth  string
+	)
+	teamsSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		teams, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer teamsSrv.Close()
+	soarSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		soar, _ = io.ReadAll(r.Body)
+		sig = r.Header.Get("X-Signature")
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer soarSrv.Close()
+
+	teamsSender, err := alertsender.Load(alertsender.Config{
+		Type: alertsender.Webhook,
+		Name: "webhook:teams",
+		Sender: Config{
+			Enabled:  true,
+			URL:      teamsSrv.URL,
+			Template: `{"text": {{ printf "%s: %s" .Title .Text | toJson }}, "severity": "{{ .Severity.String | upper }}"}`,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, alertsender.Webhook, teamsSender.Type())
+	soarSender, err := alertsender.Load(alertsender.Config{
+		Type: alertsender.Webhook,
+		Name: "webhook:soar",
+		Sender: Config{
+			Enabled:         true,
+			URL:             soarSrv.URL,
+			Headers:         map[string]string{"Authorization": "Bearer token"},
+			Secret:          "s3cr3t",
+			SignatureHeader: "X-Signature",
+		},
+	})
+	require.NoError(t, err)
+
+	alert := alertsender.NewAlert("LSASS memory dumping", "rundll32.exe accessed lsass.exe", []string{"T1003"}, alertsender.Critical)
+	alert.ID = "335795af-246b-483e-8657-09a30c102e63"
+	require.NoError(t, teamsSender.Send(alert))
+	require.NoError(t, soarSender.Send(alert))
+
+	var msg map[string]string
+	require.NoError(t, json.Unmarshal(teams, &msg))
+	assert.Equal(t, "LSASS memory dumping: rundll32.exe accessed lsass.exe", msg["text"])
+	assert.Equal(t, "CRITICAL", msg["severity"])
+
+	var a map[string]any
+	require.NoError(t, json.Unmarshal(soar, &a))
+	assert.Equal(t, "335795af-246b-483e-8657-09a30c102e63", a["id"])
+	assert.Equal(t, "critical", a["severity"])
+	assert.Equal(t, "Bearer token", auth)
+	assert.Equal(t, Sign([]byte("s3cr3t"), soar), sig)
+}
+
+func TestWebhookSenderTransientErrors(t *testing.T) {
+	var (
+		requests atomic.Int32
+		status   atomic.Int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+
+	s, err := alertsender.Load(alertsender.Config{
+		Type:   alertsender.Webhook,
+		Name:   "webhook:soar",
+		Sender: Config{Enabled: true, URL: srv.URL},
+	})
+	require.NoError(t, err)
+
+	// failed requests are retried by the caller
+	for _, code := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusRequestTimeout} {
+		status.Store(int32(code))
+		err = s.Send(alertsender.NewAlert("Suspicious DLL loaded", "", nil, alertsender.Medium))
+		require.Error(t, err)
+		assert.False(t, alertsender.IsPermanent(err))
+	}
+	assert.Equal(t, int32(3), requests.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = alertsender.SendContext(ctx, s, alertsender.NewAlert("Suspicious DLL loaded", "", nil, alertsender.Medium))
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, alertsender.IsPermanent(err))
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestWebhookSenderErrors(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid payload"))
+	}))
+	defer srv.Close()
+
+	s, err := alertsender.Load(alertsender.Config{
+		Type:   alertsender.Webhook,
+		Name:   "webhook:discord",
+		Sender: Config{Enabled: true, URL: srv.URL},
+	})
+	require.NoError(t, err)
+	err = s.Send(alertsender.NewAlert("Suspicious DLL loaded", "", nil, alertsender.Medium))
+	require.EqualError(t, err, "[webhook:discord] webhook responded with 400 status code: invalid payload")
+	assert.True(t, alertsender.IsPermanent(err))
+	assert.Equal(t, int32(1), requests.Load())
+
+	s, err = alertsender.Load(alertsender.Config{
+		Type:   alertsender.Webhook,
+		Sender: Config{Enabled: true, URL: srv.URL, Template: `{{ fail "missing field" }}`},
+	})
+	require.NoError(t, err)
+	err = s.Send(alertsender.NewAlert("Suspicious DLL loaded", "", nil, alertsender.Medium))
+	require.Error(t, err)
+	assert.True(t, alertsender.IsPermanent(err))
+	assert.Equal(t, int32(1), requests.Load())
+
+	_, err = alertsender.Load(alertsender.Config{
+		Type:   alertsender.Webhook,
+		Name:   "webhook:teams",
+		Sender: Config{Enabled: true},
+	})
+	require.EqualError(t, err, "[webhook:teams] webhook url is required")
+
+	_, err = alertsender.Load(alertsender.Config{
+		Type:   alertsender.Webhook,
+		Name:   "webhook:teams",
+		Sender: Config{Enabled: true, URL: srv.URL, Template: "{{ .Title "},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender/store"
+)
+
+// Alerts is the handler that serves alerts from the local alert store.
+// The following endpoints are exposed:
+//
+//   - GET /alerts lists alerts, most recent first
+//   - GET /alerts/export streams alerts with their events as JSON lines
+//   - GET /alerts/{id} returns the alert with its events
+//   - POST /alerts/{id}/ack acknowledges the alert
+//
+// Listed and exported alerts can be filtered by the rule, severity,
+// since, until, acked, and limit query parameters.
+func Alerts(s *store.Store) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /alerts", func(w http.ResponseWriter, r *http.Request) {
+		q, err := parseAlertQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, s.Query(q))
+	})
+	mux.HandleFunc("GET /alerts/export", func(w http.ResponseWriter, r *http.Request) {
+		q, err := parseAlertQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		if err := s.Export(q, w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	mux.HandleFunc("GET /alerts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		rec, err := s.Get(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), statusCode(err))
+			return
+		}
+		writeJSON(w, rec)
+	})
+	mux.HandleFunc("POST /alerts/{id}/ack", func(w http.ResponseWriter, r *http.Request) {
+		rec, err := s.Ack(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), statusCode(err))
+			return
+		}
+		writeJSON(w, rec)
+	})
+	return mux
+}
+
+// parseAlertQuery builds the alert store query from request query parameters.
+// The since and until parameters are expected in RFC3339 format.
+func parseAlertQuery(r *http.Request) (store.Query, error) {
+	var (
+		q      store.Query
+		err    error
+		values = r.URL.Query()
+	)
+	q.Rule = values.Get("rule")
+	if sev := values.Get("severity"); sev != "" {
+		q.Severities = strings.Split(sev, ",")
+	}
+	if since := values.Get("since"); since != "" {
+		q.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return q, errors.New("invalid since parameter: " + err.Error())
+		}
+	}
+	if until := values.Get("until"); until != "" {
+		q.Until, err = time.Parse(time.RFC3339, until)
+		if err != nil {
+			return q, errors.New("invalid until parameter: " + err.Error())
+		}
+	}
+	if acked := values.Get("acked"); acked != "" {
+		v, err := strconv.ParseBool(acked)
+		if err != nil {
+			return q, errors.New("invalid acked parameter: " + err.Error())
+		}
+		q.Acked = &v
+	}
+	if limit := values.Get("limit"); limit != "" {
+		q.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return q, errors.New("invalid limit parameter: " + err.Error())
+		}
+	}
+	return q, nil
+}
+
+func statusCode(err error) int {
+	if errors.Is(err, store.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
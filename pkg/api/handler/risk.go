@@ -0,0 +1,48 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rabbitstack/fibratus/pkg/rules/risk"
+)
+
+// Risk is the handler that serves entity risk scores sorted by score
+// in descending order. Scores can be filtered by the entity type
+// with the type query parameter.
+func Risk(s *risk.Scorer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scores := s.Scores()
+		if typ := r.URL.Query().Get("type"); typ != "" {
+			filtered := make([]risk.Score, 0, len(scores))
+			for _, score := range scores {
+				if string(score.Entity.Type) == typ {
+					filtered = append(filtered, score)
+				}
+			}
+			scores = filtered
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(scores); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
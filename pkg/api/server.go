@@ -30,10 +30,22 @@ import (
 	"strings"
 )
 
+// handlers contains additional handlers registered by components
+var handlers = make(map[string]http.Handler)
+
+// RegisterHandler registers the handler for the given pattern. Handlers
+// must be registered before the server is started.
+func RegisterHandler(pattern string, h http.Handler) {
+	handlers[pattern] = h
+}
+
 func setupServer(lis net.Listener, c *config.Config) {
 	mux := http.NewServeMux()
 	mux.Handle("/config", handler.Config(c))
 	mux.Handle("/debug/vars", expvar.Handler())
+	for pattern, h := range handlers {
+		mux.Handle(pattern, h)
+	}
 
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	actionsWorkers        = "actions.workers"
+	actionsQueueSize      = "actions.queue-size"
+	actionsTimeout        = "actions.timeout"
+	actionsMaxRetries     = "actions.max-retries"
+	actionsBackoff        = "actions.backoff"
+	actionsDeadLetterPath = "actions.dead-letter-path"
+)
+
+// ActionsConfig contains the settings of the rule action executor.
+type ActionsConfig struct {
+	// Workers is the number of concurrent action workers.
+	Workers int `json:"actions.workers" yaml:"actions.workers"`
+	// QueueSize is the maximum number of pending actions. Actions
+	// submitted when the queue is full are sent to the dead-letter log.
+	QueueSize int `json:"actions.queue-size" yaml:"actions.queue-size"`
+	// Timeout is the maximum time a single action attempt is allowed to run.
+	Timeout time.Duration `json:"actions.timeout" yaml:"actions.timeout"`
+	// MaxRetries is the number of times the failed action is retried.
+	MaxRetries int `json:"actions.max-retries" yaml:"actions.max-retries"`
+	// Backoff is the initial delay between retries. It is doubled on each retry.
+	Backoff time.Duration `json:"actions.backoff" yaml:"actions.backoff"`
+	// DeadLetterPath is the path of the file where failed actions are logged.
+	DeadLetterPath string `json:"actions.dead-letter-path" yaml:"actions.dead-letter-path"`
+}
+
+func (c *ActionsConfig) initFromViper(v *viper.Viper) {
+	c.Workers = v.GetInt(actionsWorkers)
+	c.QueueSize = v.GetInt(actionsQueueSize)
+	c.Timeout = v.GetDuration(actionsTimeout)
+	c.MaxRetries = v.GetInt(actionsMaxRetries)
+	c.Backoff = v.GetDuration(actionsBackoff)
+	c.DeadLetterPath = v.GetString(actionsDeadLetterPath)
+}
@@ -24,9 +24,14 @@ import (
 	"github.com/rabbitstack/fibratus/pkg/alertsender"
 	"github.com/rabbitstack/fibratus/pkg/alertsender/eventlog"
 	"github.com/rabbitstack/fibratus/pkg/alertsender/mail"
+	"github.com/rabbitstack/fibratus/pkg/alertsender/pagerduty"
 	"github.com/rabbitstack/fibratus/pkg/alertsender/slack"
+	syslogsender "github.com/rabbitstack/fibratus/pkg/alertsender/syslog"
 	"github.com/rabbitstack/fibratus/pkg/alertsender/systray"
+	"github.com/rabbitstack/fibratus/pkg/alertsender/webhook"
 	"reflect"
+	"slices"
+	"strings"
 )
 
 var errNoAlertsendersSection = errors.New("no alertsenders section in config")
@@ -52,59 +57,127 @@ func (c *Config) tryLoadAlertSenders() error {
 		return fmt.Errorf("expected map[string]interface{} type for alertsenders but found %s", reflect.TypeOf(alertsenders))
 	}
 
-	for typ, config := range mapping {
+	for key, config := range mapping {
+		if key == "routing" {
+			if err := decode(config, &c.AlertRouting); err != nil {
+				return fmt.Errorf("invalid alert routing config: %v", err)
+			}
+			continue
+		}
+		// multiple instances of the same alert sender
+		// type are declared with the type:name key
+		typ, _, _ := strings.Cut(key, ":")
+		spool := struct {
+			Spool alertsender.SpoolConfig `mapstructure:"spool"`
+		}{alertsender.DefaultSpoolConfig()}
+		if err := decode(config, &spool); err != nil {
+			return errAlertsenderConfig(key, err)
+		}
 		switch typ {
 		case "mail":
 			var mailConfig mail.Config
 			if err := decode(config, &mailConfig); err != nil {
-				return errAlertsenderConfig(typ, err)
+				return errAlertsenderConfig(key, err)
 			}
 			if !mailConfig.Enabled {
 				continue
 			}
 			config := alertsender.Config{
 				Type:   alertsender.Mail,
+				Name:   key,
 				Sender: mailConfig,
+				Spool:  spool.Spool,
 			}
 			configs = append(configs, config)
 		case "slack":
 			var slackConfig slack.Config
 			if err := decode(config, &slackConfig); err != nil {
-				return errAlertsenderConfig(typ, err)
+				return errAlertsenderConfig(key, err)
 			}
 			if !slackConfig.Enabled {
 				continue
 			}
 			config := alertsender.Config{
 				Type:   alertsender.Slack,
+				Name:   key,
 				Sender: slackConfig,
+				Spool:  spool.Spool,
 			}
 			configs = append(configs, config)
 		case "systray":
 			var systrayConfig systray.Config
 			if err := decode(config, &systrayConfig); err != nil {
-				return errAlertsenderConfig(typ, err)
+				return errAlertsenderConfig(key, err)
 			}
 			if !systrayConfig.Enabled {
 				continue
 			}
 			config := alertsender.Config{
 				Type:   alertsender.Systray,
+				Name:   key,
 				Sender: systrayConfig,
+				Spool:  spool.Spool,
 			}
 			configs = append(configs, config)
 
 		case "eventlog":
 			var eventlogConfig eventlog.Config
 			if err := decode(config, &eventlogConfig); err != nil {
-				return errAlertsenderConfig(typ, err)
+				return errAlertsenderConfig(key, err)
 			}
 			if !eventlogConfig.Enabled {
 				continue
 			}
 			config := alertsender.Config{
 				Type:   alertsender.Eventlog,
+				Name:   key,
 				Sender: eventlogConfig,
+				Spool:  spool.Spool,
+			}
+			configs = append(configs, config)
+		case "webhook":
+			var webhookConfig webhook.Config
+			if err := decode(config, &webhookConfig); err != nil {
+				return errAlertsenderConfig(key, err)
+			}
+			if !webhookConfig.Enabled {
+				continue
+			}
+			config := alertsender.Config{
+				Type:   alertsender.Webhook,
+				Name:   key,
+				Sender: webhookConfig,
+				Spool:  spool.Spool,
+			}
+			configs = append(configs, config)
+		case "pagerduty":
+			var pagerdutyConfig pagerduty.Config
+			if err := decode(config, &pagerdutyConfig); err != nil {
+				return errAlertsenderConfig(key, err)
+			}
+			if !pagerdutyConfig.Enabled {
+				continue
+			}
+			config := alertsender.Config{
+				Type:   alertsender.PagerDuty,
+				Name:   key,
+				Sender: pagerdutyConfig,
+				Spool:  spool.Spool,
+			}
+			configs = append(configs, config)
+		case "syslog":
+			var syslogConfig syslogsender.Config
+			if err := decode(config, &syslogConfig); err != nil {
+				return errAlertsenderConfig(key, err)
+			}
+			if !syslogConfig.Enabled {
+				continue
+			}
+			config := alertsender.Config{
+				Type:   alertsender.Syslog,
+				Name:   key,
+				Sender: syslogConfig,
+				Spool:  spool.Spool,
 			}
 			configs = append(configs, config)
 		}
@@ -112,5 +185,26 @@ func (c *Config) tryLoadAlertSenders() error {
 
 	c.Alertsenders = configs
 
+	return c.validateAlertRouting()
+}
+
+// validateAlertRouting ensures the routing table only references enabled alert sender instances.
+func (c *Config) validateAlertRouting() error {
+	names := make(map[string]bool)
+	for _, config := range c.Alertsenders {
+		names[config.InstanceName()] = true
+	}
+	refs := slices.Clone(c.AlertRouting.Default)
+	for _, r := range c.AlertRouting.Routes {
+		if len(r.Senders) == 0 {
+			return errors.New("alert route requires at least one sender")
+		}
+		refs = append(refs, r.Senders...)
+	}
+	for _, name := range refs {
+		if !names[name] {
+			return fmt.Errorf("alert routing references %q alert sender which is unknown or disabled", name)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/alertsender/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertsenderInstances(t *testing.T) {
+	c := NewWithOpts(WithRun())
+
+	err := c.flags.Parse([]string{"--config-file=_fixtures/alertsenders.yml"})
+	require.NoError(t, c.viper.BindPFlags(c.flags))
+	require.NoError(t, err)
+	require.NoError(t, c.TryLoadFile(c.GetConfigFile()))
+
+	require.NoError(t, c.Init())
+
+	names := make(map[string]alertsender.Config)
+	for _, config := range c.Alertsenders {
+		names[config.InstanceName()] = config
+	}
+	require.Len(t, names, 3)
+	require.Contains(t, names, "mail:credential-team")
+	assert.Equal(t, alertsender.Mail, names["mail:credential-team"].Type)
+	assert.Equal(t, []string{"credential-team@corp.local"}, names["mail:credential-team"].Sender.(mail.Config).To)
+	assert.Equal(t, []string{"soc@corp.local"}, names["mail"].Sender.(mail.Config).To)
+
+	assert.Equal(t, alertsender.DefaultSpoolConfig(), names["mail"].Spool)
+	assert.False(t, names["mail"].Spool.Enabled)
+	spool := names["mail:credential-team"].Spool
+	assert.True(t, spool.Enabled)
+	assert.Equal(t, 10, spool.MaxSize)
+	assert.Equal(t, time.Second*30, spool.RetryInterval)
+	assert.Equal(t, time.Minute*5, spool.MaxRetryInterval)
+	assert.Equal(t, 10, spool.MaxRetries)
+
+	require.Len(t, c.AlertRouting.Routes, 2)
+	assert.Equal(t, "high", c.AlertRouting.Routes[0].MinSeverity)
+	assert.Equal(t, []string{"mail:credential-team"}, c.AlertRouting.Routes[1].Senders)
+	assert.Equal(t, "TA0006", c.AlertRouting.Routes[1].Labels["tactic.id"])
+	assert.Equal(t, []string{"eventlog"}, c.AlertRouting.Default)
+}
+
+func TestAlertRoutingUnknownSender(t *testing.T) {
+	c := NewWithOpts(WithRun())
+
+	err := c.flags.Parse([]string{"--config-file=_fixtures/alertsenders-invalid-routing.yml"})
+	require.NoError(t, c.viper.BindPFlags(c.flags))
+	require.NoError(t, err)
+	require.NoError(t, c.TryLoadFile(c.GetConfigFile()))
+
+	require.Error(t, c.Init())
+}
@@ -28,7 +28,12 @@ import (
 
 	"github.com/rabbitstack/fibratus/pkg/outputs/eventlog"
 
+	"github.com/rabbitstack/fibratus/pkg/outputs/file"
 	"github.com/rabbitstack/fibratus/pkg/outputs/http"
+	"github.com/rabbitstack/fibratus/pkg/outputs/kafka"
+	"github.com/rabbitstack/fibratus/pkg/outputs/otlp"
+	"github.com/rabbitstack/fibratus/pkg/outputs/splunk"
+	"github.com/rabbitstack/fibratus/pkg/outputs/syslog"
 
 	"github.com/rabbitstack/fibratus/pkg/aggregator"
 	"github.com/rabbitstack/fibratus/pkg/aggregator/transformers"
@@ -53,11 +58,17 @@ import (
 	"github.com/rabbitstack/fibratus/pkg/alertsender"
 	eventlogsender "github.com/rabbitstack/fibratus/pkg/alertsender/eventlog"
 	mailsender "github.com/rabbitstack/fibratus/pkg/alertsender/mail"
+	pagerdutysender "github.com/rabbitstack/fibratus/pkg/alertsender/pagerduty"
 	slacksender "github.com/rabbitstack/fibratus/pkg/alertsender/slack"
+	alertstore "github.com/rabbitstack/fibratus/pkg/alertsender/store"
+	syslogsender "github.com/rabbitstack/fibratus/pkg/alertsender/syslog"
 	systraysender "github.com/rabbitstack/fibratus/pkg/alertsender/systray"
+	webhooksender "github.com/rabbitstack/fibratus/pkg/alertsender/webhook"
 	"github.com/rabbitstack/fibratus/pkg/outputs"
 	"github.com/rabbitstack/fibratus/pkg/outputs/console"
 	"github.com/rabbitstack/fibratus/pkg/pe"
+	"github.com/rabbitstack/fibratus/pkg/quarantine"
+	"github.com/rabbitstack/fibratus/pkg/rules/risk"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -88,8 +99,8 @@ type Config struct {
 	Filament FilamentConfig `json:"filament" yaml:"filament"`
 	// PE contains the settings that influences the behaviour of the PE (Portable Executable) reader.
 	PE pe.Config `json:"pe" yaml:"pe"`
-	// Output stores the currently active output config
-	Output outputs.Config
+	// Outputs stores the configurations of all active outputs
+	Outputs []outputs.Config
 	// InitHandleSnapshot indicates whether initial handle snapshot is built
 	InitHandleSnapshot bool `json:"init-handle-snapshot" yaml:"init-handle-snapshot"`
 	// EnumerateHandles indicates if process handles are collected during startup or
@@ -123,6 +134,10 @@ type Config struct {
 	Transformers []transformers.Config
 	// Alertsenders stores alert sender configurations
 	Alertsenders []alertsender.Config
+	// AlertRouting is the routing table that determines which alert senders receive the alert
+	AlertRouting alertsender.RoutingConfig
+	// AlertStore contains the settings of the local alert store
+	AlertStore alertstore.Config `json:"alertstore" yaml:"alertstore"`
 
 	// Filters contains filter/rule definitions
 	Filters *Filters `json:"filters" yaml:"filters"`
@@ -130,6 +145,15 @@ type Config struct {
 	// Evasion controls the detection of evasion behaviours.
 	Evasion evasion.Config `json:"evasion" yaml:"evasion"`
 
+	// Risk contains the settings for entity risk scoring.
+	Risk risk.Config `json:"risk" yaml:"risk"`
+
+	// Actions contains the settings of the rule action executor.
+	Actions ActionsConfig `json:"actions" yaml:"actions"`
+
+	// Quarantine contains the settings of the file quarantine.
+	Quarantine quarantine.Config `json:"quarantine" yaml:"quarantine"`
+
 	flags *pflag.FlagSet
 	viper *viper.Viper
 	opts  *Options
@@ -137,12 +161,14 @@ type Config struct {
 
 // Options determines which config flags are toggled depending on the command type.
 type Options struct {
-	capture  bool
-	replay   bool
-	run      bool
-	list     bool
-	stats    bool
-	validate bool
+	capture    bool
+	replay     bool
+	run        bool
+	list       bool
+	stats      bool
+	validate   bool
+	alerts     bool
+	quarantine bool
 }
 
 // Option is the type alias for the config option.
@@ -183,6 +209,20 @@ func WithStats() Option {
 	}
 }
 
+// WithAlerts determines the alerts command is executed.
+func WithAlerts() Option {
+	return func(o *Options) {
+		o.alerts = true
+	}
+}
+
+// WithQuarantine determines the quarantine command is executed.
+func WithQuarantine() Option {
+	return func(o *Options) {
+		o.quarantine = true
+	}
+}
+
 // WithValidate determines the validate command is executed.
 func WithValidate() Option {
 	return func(o *Options) {
@@ -224,6 +264,11 @@ func NewWithOpts(options ...Option) *Config {
 		amqp.AddFlags(flagSet)
 		elasticsearch.AddFlags(flagSet)
 		http.AddFlags(flagSet)
+		syslog.AddFlags(flagSet)
+		kafka.AddFlags(flagSet)
+		splunk.AddFlags(flagSet)
+		otlp.AddFlags(flagSet)
+		file.AddFlags(flagSet)
 		eventlog.AddFlags(flagSet)
 		removet.AddFlags(flagSet)
 		replacet.AddFlags(flagSet)
@@ -234,13 +279,22 @@ func NewWithOpts(options ...Option) *Config {
 		slacksender.AddFlags(flagSet)
 		systraysender.AddFlags(flagSet)
 		eventlogsender.AddFlags(flagSet)
+		webhooksender.AddFlags(flagSet)
+		pagerdutysender.AddFlags(flagSet)
+		syslogsender.AddFlags(flagSet)
 		yara.AddFlags(flagSet)
+		risk.AddFlags(flagSet)
+		alertstore.AddFlags(flagSet)
 	}
 
 	if opts.run || opts.capture {
 		pe.AddFlags(flagSet)
 	}
 
+	if opts.run || opts.replay || opts.quarantine {
+		quarantine.AddFlags(flagSet)
+	}
+
 	if opts.run {
 		evasion.AddFlags(flagSet)
 	}
@@ -281,11 +335,17 @@ func (c *Config) Init() error {
 	c.EventSource.initFromViper(c.viper)
 	c.Filament.initFromViper(c.viper)
 	c.API.initFromViper(c.viper)
+	c.Actions.initFromViper(c.viper)
 	c.PE.InitFromViper(c.viper)
 	c.Aggregator.InitFromViper(c.viper)
 	c.Log.InitFromViper(c.viper)
 	c.Yara.InitFromViper(c.viper)
-	c.Filters.initFromViper(c.viper)
+	if err := c.Filters.initFromViper(c.viper); err != nil {
+		return err
+	}
+	c.Risk.InitFromViper(c.viper)
+	c.AlertStore.InitFromViper(c.viper)
+	c.Quarantine.InitFromViper(c.viper)
 
 	c.InitHandleSnapshot = c.viper.GetBool(initHandleSnapshot)
 	c.EnumerateHandles = c.viper.GetBool(enumerateHandles)
@@ -397,7 +457,15 @@ func (c *Config) addFlags() {
 		c.flags.StringSlice(rulesFromPaths, []string{filepath.Join(dir, "*")}, "Comma-separated list of rules files")
 		c.flags.StringSlice(macrosFromPaths, []string{filepath.Join(dir, "Macros", "*")}, "Comma-separated list of macro files")
 		c.flags.StringSlice(rulesFromURLs, []string{}, "Comma-separated list of rules URL resources")
+		c.flags.StringSlice(rulesPublicKeys, []string{}, "Comma-separated list of base64-encoded Ed25519 public keys trusted to sign rule bundles")
 		c.flags.Bool(matchAll, true, "Indicates if the match all strategy is enabled for the rule engine. If the match all strategy is enabled, a single event can trigger multiple rules")
+
+		c.flags.Int(actionsWorkers, 4, "Specifies the number of concurrent rule action workers")
+		c.flags.Int(actionsQueueSize, 1024, "Specifies the maximum number of pending rule actions")
+		c.flags.Duration(actionsTimeout, time.Second*30, "Specifies the maximum time a single rule action attempt is allowed to run")
+		c.flags.Int(actionsMaxRetries, 3, "Specifies the number of times the failed rule action is retried")
+		c.flags.Duration(actionsBackoff, time.Second, "Specifies the initial delay between rule action retries")
+		c.flags.String(actionsDeadLetterPath, "", "Specifies the path of the file where failed rule actions are logged. Defaults to actions-dead-letter.log in the logs directory")
 	}
 	if c.opts.capture {
 		c.flags.StringP(capFile, "o", "", "The path of the output cap file")
@@ -408,7 +476,7 @@ func (c *Config) addFlags() {
 	if c.opts.run || c.opts.replay || c.opts.list || c.opts.validate {
 		c.flags.String(filamentPath, filepath.Join(os.Getenv("PROGRAMFILES"), "fibratus", "filaments"), "Denotes the directory where filaments are located")
 	}
-	if c.opts.run || c.opts.replay || c.opts.capture || c.opts.stats {
+	if c.opts.run || c.opts.replay || c.opts.capture || c.opts.stats || c.opts.alerts {
 		c.flags.String(transport, `localhost:8080`, "Specifies the underlying transport protocol for the API HTTP server")
 		c.flags.Duration(timeout, time.Second*15, "Determines the timeout for the API server responses")
 	}
@@ -30,9 +30,12 @@ import (
 	"slices"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	"github.com/rabbitstack/fibratus/pkg/rules/bundle"
 	"github.com/rabbitstack/fibratus/pkg/util/convert"
 	"github.com/rabbitstack/fibratus/pkg/util/multierror"
 	log "github.com/sirupsen/logrus"
@@ -57,6 +60,30 @@ type FilterConfig struct {
 	MinEngineVersion string            `json:"min-engine-version" yaml:"min-engine-version"`
 	Enabled          *bool             `json:"enabled" yaml:"enabled"`
 	Authors          []string          `json:"authors" yaml:"authors"`
+	Suppress         *SuppressConfig   `json:"suppress" yaml:"suppress"`
+	Risk             int               `json:"risk" yaml:"risk"`
+	Status           string            `json:"status" yaml:"status"`
+	// Extends contains the identifier of the rule extended by this overlay.
+	Extends string `json:"extends" yaml:"extends"`
+	// And is the condition fragment the overlay appends with the and operator.
+	And string `json:"and" yaml:"and"`
+	// AndNot is the condition fragment the overlay appends with the and not operator.
+	AndNot string `json:"and-not" yaml:"and-not"`
+	// Overlays contains the resources of overlays applied to this rule.
+	Overlays []string `json:"-" yaml:"-"`
+
+	resource string
+}
+
+// SuppressConfig determines how duplicate rule matches are
+// suppressed. Matches are considered duplicate if the values
+// of all fields are equal to the values of the first match
+// within the suppression window.
+type SuppressConfig struct {
+	// By contains the fields that identify duplicate matches.
+	By []string `json:"by" yaml:"by"`
+	// For specifies the duration of the suppression window.
+	For time.Duration `json:"for" yaml:"for"`
 }
 
 // FilterAction wraps all possible filter actions.
@@ -73,6 +100,10 @@ type IsolateAction struct {
 	Whitelist []net.IP `mapstructure:"whitelist"`
 }
 
+// QuarantineAction defines an action for moving the
+// files referenced by matched events to the quarantine.
+type QuarantineAction struct{}
+
 // DecodeActions converts raw YAML map to
 // typed action structures.
 func (f FilterConfig) DecodeActions() ([]any, error) {
@@ -103,6 +134,11 @@ func (f FilterConfig) DecodeActions() ([]any, error) {
 			if err := dec(m, isolate); err != nil {
 				return nil, err
 			}
+		case "quarantine":
+			var quarantine QuarantineAction
+			if err := dec(m, quarantine); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -112,6 +148,9 @@ func (f FilterConfig) DecodeActions() ([]any, error) {
 // IsDisabled determines if this filter is disabled.
 func (f FilterConfig) IsDisabled() bool { return f.Enabled != nil && !*f.Enabled }
 
+// IsSuppressed determines if duplicate matches of this filter are suppressed.
+func (f FilterConfig) IsSuppressed() bool { return f.Suppress != nil && f.Suppress.For > 0 }
+
 // HasLabel determines if the filter has the given label.
 func (f FilterConfig) HasLabel(l string) bool { return f.Labels[l] != "" }
 
@@ -122,8 +161,14 @@ type Filters struct {
 	// MatchAll indicates if the match all strategy is enabled for the rule engine.
 	// If the match all strategy is enabled, a single event can trigger multiple rules.
 	MatchAll bool `json:"match-all" yaml:"match-all"`
-	macros   map[string]*Macro
-	filters  []*FilterConfig
+	// Enable selects the rules that are enabled. If the enable policy is
+	// given, rules not matching all policy criteria are disabled.
+	Enable *RulePolicy `json:"enable" yaml:"enable"`
+	// Disable selects the rules that are disabled. Rules matching any
+	// of the policy criteria are disabled.
+	Disable *RulePolicy `json:"disable" yaml:"disable"`
+	macros  map[string]*Macro
+	filters []*FilterConfig
 }
 
 // FiltersWithMacros builds the filter config with the map of
@@ -138,6 +183,10 @@ type Rules struct {
 	Enabled   bool     `json:"enabled" yaml:"enabled"`
 	FromPaths []string `json:"from-paths" yaml:"from-paths"`
 	FromURLs  []string `json:"from-urls" yaml:"from-urls"`
+	// PublicKeys contains base64-encoded Ed25519 public keys
+	// that are trusted to sign rule bundles. If any key is
+	// given, only signed bundles are accepted from URLs.
+	PublicKeys []string `json:"public-keys" yaml:"public-keys"`
 }
 
 // Macros contains attributes that describe the location of
@@ -182,6 +231,33 @@ func (ctx *ActionContext) UniquePids() []uint32 {
 	return convert.MapKeysToSlice(pids)
 }
 
+// UniqueFiles returns a set of file paths from each
+// matched event to be used in actions such as the file
+// quarantine action. For file events, the path of the
+// file is used. For process creation events, the path
+// of the created process executable is used. The
+// executable of the process that generated any other
+// event is only used if the process is killed before
+// it is quarantined.
+func (ctx *ActionContext) UniqueFiles(killed bool) []string {
+	files := make(map[string]struct{})
+	for _, e := range ctx.Events {
+		var path string
+		switch {
+		case e.Category == event.File:
+			path, _ = e.Params.GetString(params.FilePath)
+		case e.IsCreateProcess():
+			path, _ = e.Params.GetString(params.Exe)
+		case killed && e.PS != nil:
+			path = e.PS.Exe
+		}
+		if path != "" {
+			files[path] = struct{}{}
+		}
+	}
+	return convert.MapKeysToSlice(files)
+}
+
 // RulesCompileResult contains the stats of the
 // compiled ruleset, like which event types or
 // categories are used. This information permits
@@ -313,16 +389,28 @@ const (
 	rulesEnabled    = "filters.rules.enabled"
 	rulesFromPaths  = "filters.rules.from-paths"
 	rulesFromURLs   = "filters.rules.from-urls"
+	rulesPublicKeys = "filters.rules.public-keys"
 	macrosFromPaths = "filters.macros.from-paths"
 	matchAll        = "filters.match-all"
+	rulesEnable     = "filters.enable"
+	rulesDisable    = "filters.disable"
 )
 
-func (f *Filters) initFromViper(v *viper.Viper) {
+func (f *Filters) initFromViper(v *viper.Viper) error {
 	f.Rules.Enabled = v.GetBool(rulesEnabled)
 	f.Rules.FromPaths = v.GetStringSlice(rulesFromPaths)
 	f.Rules.FromURLs = v.GetStringSlice(rulesFromURLs)
+	f.Rules.PublicKeys = v.GetStringSlice(rulesPublicKeys)
 	f.Macros.FromPaths = v.GetStringSlice(macrosFromPaths)
 	f.MatchAll = v.GetBool(matchAll)
+
+	var err error
+	f.Enable, err = decodeRulePolicy(v, rulesEnable)
+	if err != nil {
+		return err
+	}
+	f.Disable, err = decodeRulePolicy(v, rulesDisable)
+	return err
 }
 
 func (f Filters) HasMacros() bool           { return len(f.macros) > 0 }
@@ -353,38 +441,46 @@ func (f *Filters) LoadMacros() error {
 			if err != nil {
 				return fmt.Errorf("couldn't load macros from file: %v", err)
 			}
-			// validate macro yaml structure
-			var out interface{}
-			err = yaml.Unmarshal(buf, &out)
-			if err != nil {
-				return fmt.Errorf("%q is invalid macro yaml file: %v", path, err)
-			}
-			valid, errs := validate(macrosSchema, out)
-			if !valid || len(errs) > 0 {
-				b, err := yaml.Marshal(&out)
-				if err == nil {
-					out = string(b)
-				}
-				return fmt.Errorf("invalid macro definition: \n\n"+
-					"%v in %s: %v", out, path, multierror.Wrap(errs...))
-			}
-			buf, err = renderTmpl(path, buf)
-			if err != nil {
-				return err
-			}
-			// unmarshal macros and transform to map
-			var macros []Macro
-			if err := yaml.Unmarshal(buf, &macros); err != nil {
+			if err := f.decodeMacros(path, buf); err != nil {
 				return err
 			}
-			for _, m := range macros {
-				f.macros[m.ID] = &Macro{
-					ID:          m.ID,
-					Description: m.Description,
-					Expr:        m.Expr,
-					List:        m.List,
-				}
-			}
+		}
+	}
+	return nil
+}
+
+// decodeMacros validates the macro yaml structure, renders
+// the template and stores the decoded macros.
+func (f *Filters) decodeMacros(resource string, buf []byte) error {
+	var out interface{}
+	err := yaml.Unmarshal(buf, &out)
+	if err != nil {
+		return fmt.Errorf("%q is invalid macro yaml file: %v", resource, err)
+	}
+	valid, errs := validate(macrosSchema, out)
+	if !valid || len(errs) > 0 {
+		b, err := yaml.Marshal(&out)
+		if err == nil {
+			out = string(b)
+		}
+		return fmt.Errorf("invalid macro definition: \n\n"+
+			"%v in %s: %v", out, resource, multierror.Wrap(errs...))
+	}
+	buf, err = renderTmpl(resource, buf)
+	if err != nil {
+		return err
+	}
+	// unmarshal macros and transform to map
+	var macros []Macro
+	if err := yaml.Unmarshal(buf, &macros); err != nil {
+		return err
+	}
+	for _, m := range macros {
+		f.macros[m.ID] = &Macro{
+			ID:          m.ID,
+			Description: m.Description,
+			Expr:        m.Expr,
+			List:        m.List,
 		}
 	}
 	return nil
@@ -418,11 +514,9 @@ func (f *Filters) LoadFilters() error {
 			if err != nil {
 				return err
 			}
-			if ids[flt.ID] {
-				return fmt.Errorf("%q rule uses duplicate id %s", flt.Name, flt.ID)
+			if err := f.appendFilter(flt, ids); err != nil {
+				return err
 			}
-			ids[flt.ID] = true
-			f.filters = append(f.filters, flt)
 		}
 	}
 	for _, url := range f.Rules.FromURLs {
@@ -447,15 +541,36 @@ func (f *Filters) LoadFilters() error {
 		if err != nil {
 			return fmt.Errorf("cannot copy rule file from %q: %v", url, err)
 		}
+
+		// signed bundles carry a set of rules and macros
+		if bundle.IsBundle(rawConfig.Bytes()) {
+			filters, err := f.loadBundle(url, rawConfig.Bytes())
+			if err != nil {
+				return err
+			}
+			for _, flt := range filters {
+				if err := f.appendFilter(flt, ids); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if len(f.Rules.PublicKeys) > 0 {
+			return fmt.Errorf("%q is not a signed rule bundle. Only signed "+
+				"rule bundles are accepted when public keys are configured", url)
+		}
+
 		flt, err := decodeFilter(url, rawConfig.Bytes())
 		if err != nil {
 			return err
 		}
-		if ids[flt.ID] {
-			return fmt.Errorf("%q rule uses duplicate id %s", flt.Name, flt.ID)
+		if err := f.appendFilter(flt, ids); err != nil {
+			return err
 		}
-		ids[flt.ID] = true
-		f.filters = append(f.filters, flt)
+	}
+
+	if err := f.applyOverlays(); err != nil {
+		return err
 	}
 
 	if len(f.filters) == 0 {
@@ -465,6 +580,55 @@ func (f *Filters) LoadFilters() error {
 	return nil
 }
 
+// appendFilter adds the rule to the list of loaded rules. Rule
+// identifiers must be unique, except for overlays which
+// reference the rule they extend.
+func (f *Filters) appendFilter(flt *FilterConfig, ids map[string]bool) error {
+	if !flt.IsOverlay() {
+		if ids[flt.ID] {
+			return fmt.Errorf("%q rule uses duplicate id %s", flt.Name, flt.ID)
+		}
+		ids[flt.ID] = true
+	}
+	f.filters = append(f.filters, flt)
+	return nil
+}
+
+// loadBundle verifies the signature of the rule bundle
+// against trusted public keys and decodes the bundled
+// macros and rules.
+func (f *Filters) loadBundle(url string, b []byte) ([]*FilterConfig, error) {
+	keys, err := bundle.ParsePublicKeys(f.Rules.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+	bun, err := bundle.Open(b, keys)
+	if err != nil {
+		return nil, fmt.Errorf("refusing to load rules from %q: %v", url, err)
+	}
+	log.Infof("verified rule bundle %s signed by key %s on %s", url, bun.KeyID, bun.Manifest.Created)
+
+	if f.macros == nil {
+		f.macros = make(map[string]*Macro)
+	}
+	for _, m := range bun.Macros {
+		if err := f.decodeMacros(url+"#"+m.Name, m.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	filters := make([]*FilterConfig, 0, len(bun.Rules))
+	for _, r := range bun.Rules {
+		flt, err := decodeFilter(url+"#"+r.Name, r.Data)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, flt)
+	}
+
+	return filters, nil
+}
+
 func decodeFilter(resource string, b []byte) (*FilterConfig, error) {
 	var out interface{}
 	err := yaml.Unmarshal(b, &out)
@@ -492,6 +656,7 @@ func decodeFilter(resource string, b []byte) (*FilterConfig, error) {
 	if err := yaml.Unmarshal(b, &flt); err != nil {
 		return nil, err
 	}
+	flt.resource = resource
 	return &flt, nil
 }
 
@@ -19,6 +19,10 @@
 package config
 
 import (
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/rabbitstack/fibratus/pkg/rules/bundle"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"net"
@@ -38,6 +42,8 @@ func TestLoadRulesFromPaths(t *testing.T) {
 		},
 		Macros{FromPaths: nil},
 		false,
+		nil,
+		nil,
 		map[string]*Macro{},
 		[]*FilterConfig{},
 	}
@@ -64,6 +70,7 @@ func TestLoadRulesFromPaths(t *testing.T) {
 	require.NoError(t, err)
 	require.IsType(t, KillAction{}, acts[0])
 	require.IsType(t, IsolateAction{}, acts[1])
+	require.IsType(t, QuarantineAction{}, acts[2])
 
 	isolate := acts[1].(IsolateAction)
 	require.Len(t, isolate.Whitelist, 2)
@@ -86,6 +93,8 @@ func TestLoadRulesFromPathsWithTemplate(t *testing.T) {
 		},
 		Macros{FromPaths: nil},
 		false,
+		nil,
+		nil,
 		map[string]*Macro{},
 		[]*FilterConfig{},
 	}
@@ -125,6 +134,8 @@ func TestLoadGroupsFromURLs(t *testing.T) {
 		},
 		Macros{FromPaths: nil},
 		false,
+		nil,
+		nil,
 		map[string]*Macro{},
 		[]*FilterConfig{},
 	}
@@ -148,3 +159,136 @@ func TestLoadGroupsFromURLs(t *testing.T) {
 
 	assert.Equal(t, "2.0.0", f1.MinEngineVersion)
 }
+
+func TestLoadRulesFromSignedBundle(t *testing.T) {
+	pub, priv, err := bundle.GenerateKey()
+	require.NoError(t, err)
+
+	rule, err := os.ReadFile("_fixtures/filters/default.yml")
+	require.NoError(t, err)
+
+	b := bundle.NewBuilder()
+	b.AddRule("default.yml", rule)
+	b.AddMacro("macros.yml", []byte("- macro: spawn_process\n  expr: evt.name = 'CreateProcess'\n"))
+	signed, err := b.Sign(priv)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rules.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(signed)
+	})
+	mux.HandleFunc("/default.yml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(rule)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var tests = []struct {
+		url  string
+		keys []string
+		err  bool
+	}{
+		{srv.URL + "/rules.tar.gz", []string{bundle.EncodePublicKey(pub)}, false},
+		{srv.URL + "/rules.tar.gz", nil, true},
+		{srv.URL + "/default.yml", []string{bundle.EncodePublicKey(pub)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			filters := Filters{
+				Rules{
+					FromURLs:   []string{tt.url},
+					PublicKeys: tt.keys,
+				},
+				Macros{FromPaths: nil},
+				false,
+				nil,
+				nil,
+				map[string]*Macro{},
+				[]*FilterConfig{},
+			}
+			err := filters.LoadFilters()
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, filters.filters, 1)
+			assert.Equal(t, "only network category", filters.filters[0].Name)
+			assert.NotNil(t, filters.GetMacro("spawn_process"))
+		})
+	}
+}
+
+func TestLoadRulesWithOverlays(t *testing.T) {
+	filters := Filters{
+		Rules{
+			FromPaths: []string{
+				"_fixtures/filters/default.yml",
+				"_fixtures/filters/default1.yml",
+				"_fixtures/overlays/default-overlay.yml",
+			},
+		},
+		Macros{FromPaths: nil},
+		false,
+		nil,
+		nil,
+		map[string]*Macro{},
+		[]*FilterConfig{},
+	}
+	err := filters.LoadFilters()
+	require.NoError(t, err)
+	require.Len(t, filters.filters, 2)
+
+	f1 := filters.filters[0]
+	assert.Equal(t, "313933e7-8eb9-45d9-81af-0305fee70e29", f1.ID)
+	assert.Equal(t, "only network category", f1.Name)
+	assert.Equal(t, "high", f1.Severity)
+	assert.Equal(t, "soc", f1.Labels["team"])
+	assert.Len(t, f1.Action, 1)
+	assert.Equal(t, "((evt.category = 'net') and (net.dport = 443)) and not (ps.name = 'svchost.exe')", f1.Condition)
+	assert.Equal(t, []string{"_fixtures/overlays/default-overlay.yml"}, f1.Overlays)
+
+	filters.Rules.FromPaths = []string{
+		"_fixtures/filters/default.yml",
+		"_fixtures/overlays/unknown-overlay.yml",
+	}
+	require.Error(t, filters.LoadFilters())
+}
+
+func TestActionContextUniqueFiles(t *testing.T) {
+	ctx := &ActionContext{
+		Events: []*event.Event{
+			{
+				Type:     event.CreateFile,
+				Category: event.File,
+				Params: event.Params{
+					params.FilePath: {Name: params.FilePath, Type: params.UnicodeString, Value: "C:\\Temp\\dropper.exe"},
+				},
+				PS: &pstypes.PS{Exe: "C:\\Windows\\System32\\cmd.exe"},
+			},
+			{
+				Type:     event.CreateProcess,
+				Category: event.Process,
+				Params: event.Params{
+					params.Exe: {Name: params.Exe, Type: params.UnicodeString, Value: "C:\\Temp\\dropper.exe"},
+				},
+				PS: &pstypes.PS{Exe: "C:\\Windows\\System32\\cmd.exe"},
+			},
+			{
+				Type:     event.LoadModule,
+				Category: event.Module,
+				Params:   event.Params{},
+				PS:       &pstypes.PS{Exe: "C:\\Temp\\dropper.exe"},
+			},
+			{
+				Type:     event.ConnectTCPv4,
+				Category: event.Net,
+				Params:   event.Params{},
+				PS:       &pstypes.PS{Exe: "C:\\Users\\admin\\AppData\\Local\\Temp\\beacon.exe"},
+			},
+		},
+	}
+	assert.ElementsMatch(t, []string{"C:\\Temp\\dropper.exe"}, ctx.UniqueFiles(false))
+	assert.ElementsMatch(t, []string{"C:\\Temp\\dropper.exe", "C:\\Users\\admin\\AppData\\Local\\Temp\\beacon.exe"}, ctx.UniqueFiles(true))
+}
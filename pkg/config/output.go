@@ -21,8 +21,9 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
-	"strconv"
+	"slices"
 
 	"github.com/rabbitstack/fibratus/pkg/outputs/eventlog"
 
@@ -30,8 +31,13 @@ import (
 	"github.com/rabbitstack/fibratus/pkg/outputs/amqp"
 	"github.com/rabbitstack/fibratus/pkg/outputs/console"
 	"github.com/rabbitstack/fibratus/pkg/outputs/elasticsearch"
+	"github.com/rabbitstack/fibratus/pkg/outputs/file"
 	"github.com/rabbitstack/fibratus/pkg/outputs/http"
+	"github.com/rabbitstack/fibratus/pkg/outputs/kafka"
 	"github.com/rabbitstack/fibratus/pkg/outputs/null"
+	"github.com/rabbitstack/fibratus/pkg/outputs/otlp"
+	"github.com/rabbitstack/fibratus/pkg/outputs/splunk"
+	"github.com/rabbitstack/fibratus/pkg/outputs/syslog"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sys/windows/svc"
 )
@@ -50,31 +56,23 @@ func (c *Config) tryLoadOutput() error {
 		return fmt.Errorf("expected map[string]interface{} type for output but found %s", reflect.TypeOf(output))
 	}
 
-	humNum := func(n int) string {
-		switch n {
-		case 2:
-			return "two"
-		case 3:
-			return "three"
-		case 4:
-			return "four"
-		case 5:
-			return "five"
-		case 6:
-			return "six"
-		case 7:
-			return "seven"
-		default:
-			return strconv.Itoa(n)
-		}
-	}
-	// don't permit if there are various outputs enabled at a time
-	activeOutputs := findActiveOutputs(mapping)
-	if len(activeOutputs) > 1 {
-		return fmt.Errorf("expected one but found %s active outputs: %s", humNum(len(activeOutputs)), activeOutputs)
-	}
+	c.Outputs = make([]outputs.Config, 0)
 
-	for typ, config := range mapping {
+	// multiple outputs may be active at a time. Each
+	// output receives events selected by the optional
+	// filter expression
+	for _, typ := range slices.Sorted(maps.Keys(mapping)) {
+		config := mapping[typ]
+		var policy struct {
+			Filter   string           `mapstructure:"filter"`
+			Sampling outputs.Sampling `mapstructure:"sampling"`
+		}
+		if err := decode(config, &policy); err != nil {
+			return errOutputConfig(typ, err)
+		}
+		if err := policy.Sampling.Validate(); err != nil {
+			return errOutputConfig(typ, err)
+		}
 		switch outputs.TypeFromString(typ) {
 		case outputs.Console:
 			var consoleConfig console.Config
@@ -84,7 +82,15 @@ func (c *Config) tryLoadOutput() error {
 			if !consoleConfig.Enabled {
 				continue
 			}
-			c.Output.Type, c.Output.Output = outputs.Console, consoleConfig
+			// if it is not an interactive session but the console
+			// output is enabled, we skip the console output and warn
+			// about that
+			if isWindowsService() {
+				log.Warn("running in non-interactive session with console output. " +
+					"Please configure a different output type. Skipping console output")
+				continue
+			}
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.Console, Output: consoleConfig, Filter: policy.Filter, Sampling: policy.Sampling})
 
 		case outputs.AMQP:
 			var amqpConfig amqp.Config
@@ -94,7 +100,7 @@ func (c *Config) tryLoadOutput() error {
 			if !amqpConfig.Enabled {
 				continue
 			}
-			c.Output.Type, c.Output.Output = outputs.AMQP, amqpConfig
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.AMQP, Output: amqpConfig, Filter: policy.Filter, Sampling: policy.Sampling})
 
 		case outputs.Elasticsearch:
 			var esConfig elasticsearch.Config
@@ -104,7 +110,7 @@ func (c *Config) tryLoadOutput() error {
 			if !esConfig.Enabled {
 				continue
 			}
-			c.Output.Type, c.Output.Output = outputs.Elasticsearch, esConfig
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.Elasticsearch, Output: esConfig, Filter: policy.Filter, Sampling: policy.Sampling})
 
 		case outputs.HTTP:
 			var httpConfig http.Config
@@ -114,7 +120,7 @@ func (c *Config) tryLoadOutput() error {
 			if !httpConfig.Enabled {
 				continue
 			}
-			c.Output.Type, c.Output.Output = outputs.HTTP, httpConfig
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.HTTP, Output: httpConfig, Filter: policy.Filter, Sampling: policy.Sampling})
 
 		case outputs.Eventlog:
 			var eventlogConfig eventlog.Config
@@ -124,41 +130,69 @@ func (c *Config) tryLoadOutput() error {
 			if !eventlogConfig.Enabled {
 				continue
 			}
-			c.Output.Type, c.Output.Output = outputs.Eventlog, eventlogConfig
-		}
-	}
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.Eventlog, Output: eventlogConfig, Filter: policy.Filter, Sampling: policy.Sampling})
+
+		case outputs.Syslog:
+			var syslogConfig syslog.Config
+			if err := decode(config, &syslogConfig); err != nil {
+				return errOutputConfig(typ, err)
+			}
+			if !syslogConfig.Enabled {
+				continue
+			}
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.Syslog, Output: syslogConfig, Filter: policy.Filter, Sampling: policy.Sampling})
+
+		case outputs.Kafka:
+			var kafkaConfig kafka.Config
+			if err := decode(config, &kafkaConfig); err != nil {
+				return errOutputConfig(typ, err)
+			}
+			if !kafkaConfig.Enabled {
+				continue
+			}
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.Kafka, Output: kafkaConfig, Filter: policy.Filter, Sampling: policy.Sampling})
+
+		case outputs.Splunk:
+			var splunkConfig splunk.Config
+			if err := decode(config, &splunkConfig); err != nil {
+				return errOutputConfig(typ, err)
+			}
+			if !splunkConfig.Enabled {
+				continue
+			}
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.Splunk, Output: splunkConfig, Filter: policy.Filter, Sampling: policy.Sampling})
 
-	// if it is not an interactive session but the console output is enabled
-	// we default to null output and warn about that
-	if isWindowsService() && c.Output.Output != nil {
-		if c.Output.Type == outputs.Console {
-			log.Warn("running in non-interactive session with console output. " +
-				"Please configure a different output type. Defaulting to null output")
-			c.Output.Type, c.Output.Output = outputs.Null, &null.Config{}
-			return nil
+		case outputs.OTLP:
+			var otlpConfig otlp.Config
+			if err := decode(config, &otlpConfig); err != nil {
+				return errOutputConfig(typ, err)
+			}
+			if !otlpConfig.Enabled {
+				continue
+			}
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.OTLP, Output: otlpConfig, Filter: policy.Filter, Sampling: policy.Sampling})
+
+		case outputs.File:
+			var fileConfig file.Config
+			if err := decode(config, &fileConfig); err != nil {
+				return errOutputConfig(typ, err)
+			}
+			if !fileConfig.Enabled {
+				continue
+			}
+			c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.File, Output: fileConfig, Filter: policy.Filter, Sampling: policy.Sampling})
 		}
 	}
 
 	// default to null output
-	if c.Output.Output == nil {
+	if len(c.Outputs) == 0 {
 		log.Warn("all outputs disabled. Defaulting to null output")
-		c.Output.Type, c.Output.Output = outputs.Null, &null.Config{}
+		c.Outputs = append(c.Outputs, outputs.Config{Type: outputs.Null, Output: &null.Config{}})
 	}
 
 	return nil
 }
 
-func findActiveOutputs(outputs map[string]interface{}) []string {
-	outputTypes := make([]string, 0)
-	for typ, rawConfig := range outputs {
-		enabled, ok := rawConfig.(map[string]interface{})["enabled"].(bool)
-		if ok && enabled {
-			outputTypes = append(outputTypes, typ)
-		}
-	}
-	return outputTypes
-}
-
 // isWindowsService returns true if the process is running inside Windows Service.
 func isWindowsService() bool {
 	isWinService, err := svc.IsWindowsService()
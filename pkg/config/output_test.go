@@ -24,8 +24,14 @@ import (
 
 	"github.com/rabbitstack/fibratus/pkg/outputs/eventlog"
 
+	"github.com/rabbitstack/fibratus/pkg/outputs"
 	"github.com/rabbitstack/fibratus/pkg/outputs/amqp"
+	"github.com/rabbitstack/fibratus/pkg/outputs/elasticsearch"
+	"github.com/rabbitstack/fibratus/pkg/outputs/file"
 	"github.com/rabbitstack/fibratus/pkg/outputs/http"
+	"github.com/rabbitstack/fibratus/pkg/outputs/kafka"
+	"github.com/rabbitstack/fibratus/pkg/outputs/otlp"
+	"github.com/rabbitstack/fibratus/pkg/outputs/splunk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -40,10 +46,10 @@ func TestAMQPOutput(t *testing.T) {
 
 	require.NoError(t, c.Init())
 
-	require.NotNil(t, c.Output)
-	require.IsType(t, amqp.Config{}, c.Output.Output)
+	require.Len(t, c.Outputs, 1)
+	require.IsType(t, amqp.Config{}, c.Outputs[0].Output)
 
-	amqpConfig := c.Output.Output.(amqp.Config)
+	amqpConfig := c.Outputs[0].Output.(amqp.Config)
 	assert.Equal(t, "amqp://localhost:5672", amqpConfig.URL)
 	assert.Equal(t, time.Second*5, amqpConfig.Timeout)
 	assert.Equal(t, "fibratus", amqpConfig.Exchange)
@@ -62,10 +68,10 @@ func TestHTTPOutput(t *testing.T) {
 
 	require.NoError(t, c.Init())
 
-	require.NotNil(t, c.Output)
-	require.IsType(t, http.Config{}, c.Output.Output)
+	require.Len(t, c.Outputs, 1)
+	require.IsType(t, http.Config{}, c.Outputs[0].Output)
 
-	httpConfig := c.Output.Output.(http.Config)
+	httpConfig := c.Outputs[0].Output.(http.Config)
 	assert.True(t, httpConfig.Enabled)
 	assert.Len(t, httpConfig.Endpoints, 2)
 	assert.Contains(t, httpConfig.Endpoints, "http://localhost:8081")
@@ -90,10 +96,134 @@ func TestEventlogOutput(t *testing.T) {
 
 	require.NoError(t, c.Init())
 
-	require.NotNil(t, c.Output)
-	require.IsType(t, eventlog.Config{}, c.Output.Output)
+	require.Len(t, c.Outputs, 1)
+	require.IsType(t, eventlog.Config{}, c.Outputs[0].Output)
 
-	eventlogConfig := c.Output.Output.(eventlog.Config)
+	eventlogConfig := c.Outputs[0].Output.(eventlog.Config)
 	assert.True(t, eventlogConfig.Enabled)
 	assert.Equal(t, "INFO", eventlogConfig.Level)
 }
+
+func TestMultipleOutputs(t *testing.T) {
+	c := NewWithOpts(WithRun())
+
+	err := c.flags.Parse([]string{"--config-file=_fixtures/outputs.yml"})
+	require.NoError(t, c.viper.BindPFlags(c.flags))
+	require.NoError(t, err)
+	require.NoError(t, c.TryLoadFile(c.GetConfigFile()))
+
+	require.NoError(t, c.Init())
+
+	require.Len(t, c.Outputs, 2)
+
+	assert.Equal(t, outputs.Elasticsearch, c.Outputs[0].Type)
+	assert.IsType(t, elasticsearch.Config{}, c.Outputs[0].Output)
+	assert.Empty(t, c.Outputs[0].Filter)
+
+	assert.Equal(t, outputs.HTTP, c.Outputs[1].Type)
+	assert.IsType(t, http.Config{}, c.Outputs[1].Output)
+	assert.Equal(t, "evt.category = 'process'", c.Outputs[1].Filter)
+	assert.False(t, c.Outputs[0].Sampling.IsEnabled())
+	assert.Equal(t, map[string]float64{"readfile": 10, "registry": 25.5}, c.Outputs[1].Sampling.Rates)
+	assert.Equal(t, float64(5000), c.Outputs[1].Sampling.MaxRate)
+}
+
+func TestKafkaOutput(t *testing.T) {
+	c := NewWithOpts(WithRun())
+
+	err := c.flags.Parse([]string{"--config-file=_fixtures/kafka-output.yml"})
+	require.NoError(t, c.viper.BindPFlags(c.flags))
+	require.NoError(t, err)
+	require.NoError(t, c.TryLoadFile(c.GetConfigFile()))
+
+	require.NoError(t, c.Init())
+
+	require.Len(t, c.Outputs, 1)
+	require.IsType(t, kafka.Config{}, c.Outputs[0].Output)
+
+	kafkaConfig := c.Outputs[0].Output.(kafka.Config)
+	assert.True(t, kafkaConfig.Enabled)
+	assert.Equal(t, []string{"kafka1:9092", "kafka2:9092"}, kafkaConfig.Brokers)
+	assert.Equal(t, "fibratus-{{ .Category }}", kafkaConfig.Topic)
+	assert.Equal(t, kafka.PsUUID, kafkaConfig.PartitionKey)
+	assert.Equal(t, "zstd", kafkaConfig.Compression)
+	assert.True(t, kafkaConfig.Idempotent)
+	assert.Equal(t, "scram-sha-256", kafkaConfig.SASLMechanism)
+	assert.Equal(t, "fibratus", kafkaConfig.Username)
+	assert.Equal(t, time.Second, kafkaConfig.FlushFrequency)
+	assert.Equal(t, outputs.ECS, kafkaConfig.Serializer)
+}
+
+func TestSplunkOutput(t *testing.T) {
+	c := NewWithOpts(WithRun())
+
+	err := c.flags.Parse([]string{"--config-file=_fixtures/splunk-output.yml"})
+	require.NoError(t, c.viper.BindPFlags(c.flags))
+	require.NoError(t, err)
+	require.NoError(t, c.TryLoadFile(c.GetConfigFile()))
+
+	require.NoError(t, c.Init())
+
+	require.Len(t, c.Outputs, 1)
+	require.IsType(t, splunk.Config{}, c.Outputs[0].Output)
+
+	splunkConfig := c.Outputs[0].Output.(splunk.Config)
+	assert.True(t, splunkConfig.Enabled)
+	assert.Equal(t, []string{"https://splunk:8088"}, splunkConfig.Endpoints)
+	assert.Equal(t, "11111111-2222-3333-4444-555555555555", splunkConfig.Token)
+	assert.Equal(t, "edr", splunkConfig.Index)
+	assert.Equal(t, "fibratus:process", splunkConfig.Sourcetypes["process"])
+	assert.Equal(t, "fibratus:network", splunkConfig.Sourcetypes["net"])
+	assert.True(t, splunkConfig.EnableGzip)
+	assert.True(t, splunkConfig.Ack)
+	assert.Equal(t, time.Second*30, splunkConfig.AckTimeout)
+}
+
+func TestOTLPOutput(t *testing.T) {
+	c := NewWithOpts(WithRun())
+
+	err := c.flags.Parse([]string{"--config-file=_fixtures/otlp-output.yml"})
+	require.NoError(t, c.viper.BindPFlags(c.flags))
+	require.NoError(t, err)
+	require.NoError(t, c.TryLoadFile(c.GetConfigFile()))
+
+	require.NoError(t, c.Init())
+
+	require.Len(t, c.Outputs, 1)
+	require.IsType(t, otlp.Config{}, c.Outputs[0].Output)
+
+	otlpConfig := c.Outputs[0].Output.(otlp.Config)
+	assert.True(t, otlpConfig.Enabled)
+	assert.Equal(t, "https://collector:4318", otlpConfig.Endpoint)
+	assert.Equal(t, otlp.HTTPJSON, otlpConfig.Protocol)
+	assert.Equal(t, "secret", otlpConfig.Headers["x-api-key"])
+	assert.Equal(t, []string{"deployment.environment=production"}, otlpConfig.ResourceAttributes)
+	assert.Equal(t, 256, otlpConfig.MaxBatchSize)
+	assert.True(t, otlpConfig.EnableGzip)
+	assert.Equal(t, 3, otlpConfig.MaxRetries)
+	assert.Equal(t, "fibratus", otlpConfig.ServiceName)
+}
+
+func TestFileOutput(t *testing.T) {
+	c := NewWithOpts(WithRun())
+
+	err := c.flags.Parse([]string{"--config-file=_fixtures/file-output.yml"})
+	require.NoError(t, c.viper.BindPFlags(c.flags))
+	require.NoError(t, err)
+	require.NoError(t, c.TryLoadFile(c.GetConfigFile()))
+
+	require.NoError(t, c.Init())
+
+	require.Len(t, c.Outputs, 1)
+	require.IsType(t, file.Config{}, c.Outputs[0].Output)
+
+	fileConfig := c.Outputs[0].Output.(file.Config)
+	assert.True(t, fileConfig.Enabled)
+	assert.Equal(t, `C:\Events`, fileConfig.Path)
+	assert.Equal(t, "{{ .Host }}-{{ .Date }}.ndjson", fileConfig.Name)
+	assert.Equal(t, 50, fileConfig.MaxSize)
+	assert.Equal(t, time.Hour, fileConfig.RotationInterval)
+	assert.Equal(t, 5, fileConfig.MaxBackups)
+	assert.Equal(t, file.Zstd, fileConfig.Compression)
+	assert.Equal(t, time.Second*5, fileConfig.FsyncInterval)
+}
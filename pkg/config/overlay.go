@@ -0,0 +1,102 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// IsOverlay determines if this rule is an overlay that extends another rule.
+func (f FilterConfig) IsOverlay() bool { return f.Extends != "" }
+
+// applyOverlays merges overlays into the rules they extend. Overlays
+// are applied in the order they are loaded. The effective rule replaces
+// the extended rule, whereas overlays are removed from the ruleset.
+func (f *Filters) applyOverlays() error {
+	rules := make(map[string]*FilterConfig)
+	filters := make([]*FilterConfig, 0, len(f.filters))
+	overlays := make([]*FilterConfig, 0)
+	for _, flt := range f.filters {
+		if flt.IsOverlay() {
+			overlays = append(overlays, flt)
+			continue
+		}
+		rules[flt.ID] = flt
+		filters = append(filters, flt)
+	}
+	if len(overlays) == 0 {
+		return nil
+	}
+
+	for _, overlay := range overlays {
+		rule, ok := rules[overlay.Extends]
+		if !ok {
+			return fmt.Errorf("overlay %s extends unknown rule %s", overlay.resource, overlay.Extends)
+		}
+		if err := rule.applyOverlay(overlay); err != nil {
+			return fmt.Errorf("unable to apply overlay %s to %q rule: %v", overlay.resource, rule.Name, err)
+		}
+	}
+	f.filters = filters
+
+	return nil
+}
+
+// applyOverlay overrides the severity, labels, actions, and
+// the enabled state of the rule. Condition fragments are
+// appended to the rule condition.
+func (f *FilterConfig) applyOverlay(overlay *FilterConfig) error {
+	if overlay.Condition != "" {
+		return fmt.Errorf("overlays can't replace the condition. Use and/and-not fragments instead")
+	}
+	if (overlay.And != "" || overlay.AndNot != "") && f.isSequenceCondition() {
+		return fmt.Errorf("condition fragments can't be appended to sequence rules")
+	}
+
+	if overlay.Severity != "" {
+		f.Severity = overlay.Severity
+	}
+	if len(overlay.Labels) > 0 {
+		labels := make(map[string]string, len(f.Labels)+len(overlay.Labels))
+		maps.Copy(labels, f.Labels)
+		maps.Copy(labels, overlay.Labels)
+		f.Labels = labels
+	}
+	if overlay.Action != nil {
+		f.Action = overlay.Action
+	}
+	if overlay.Enabled != nil {
+		f.Enabled = overlay.Enabled
+	}
+	if overlay.And != "" {
+		f.Condition = fmt.Sprintf("(%s) and (%s)", strings.TrimSpace(f.Condition), strings.TrimSpace(overlay.And))
+	}
+	if overlay.AndNot != "" {
+		f.Condition = fmt.Sprintf("(%s) and not (%s)", strings.TrimSpace(f.Condition), strings.TrimSpace(overlay.AndNot))
+	}
+	f.Overlays = append(f.Overlays, overlay.resource)
+
+	return nil
+}
+
+func (f FilterConfig) isSequenceCondition() bool {
+	return strings.HasPrefix(strings.TrimSpace(f.Condition), "sequence")
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/spf13/viper"
+)
+
+// Rule maturity statuses.
+const (
+	// StatusExperimental designates rules that are in early development stages
+	StatusExperimental = "experimental"
+	// StatusTest designates rules that are tested but may produce false positives
+	StatusTest = "test"
+	// StatusStable designates rules that are production-ready
+	StatusStable = "stable"
+	// StatusDeprecated designates rules that are superseded and will be removed
+	StatusDeprecated = "deprecated"
+)
+
+// RulePolicy selects rules by their identifiers, MITRE ATT&CK
+// labels, tags, maturity status, or minimum severity.
+type RulePolicy struct {
+	// IDs contains rule identifiers.
+	IDs []string `json:"ids" yaml:"ids" mapstructure:"ids"`
+	// Tactics contains MITRE tactic identifiers matched against the tactic.id label.
+	Tactics []string `json:"tactics" yaml:"tactics" mapstructure:"tactics"`
+	// Techniques contains MITRE technique identifiers matched against the technique.id label.
+	Techniques []string `json:"techniques" yaml:"techniques" mapstructure:"techniques"`
+	// Tags contains rule tags.
+	Tags []string `json:"tags" yaml:"tags" mapstructure:"tags"`
+	// Status contains rule maturity statuses.
+	Status []string `json:"status" yaml:"status" mapstructure:"status"`
+	// MinSeverity is the minimum rule severity.
+	MinSeverity string `json:"min-severity" yaml:"min-severity" mapstructure:"min-severity"`
+}
+
+// RuleState describes whether the rule is enabled and the reason of the decision.
+type RuleState struct {
+	Enabled bool
+	Reason  string
+}
+
+// EffectiveStatus returns the rule maturity status. Rules
+// without explicit status are considered stable.
+func (f FilterConfig) EffectiveStatus() string {
+	if f.Status == "" {
+		return StatusStable
+	}
+	return f.Status
+}
+
+// RuleState determines if the rule is enabled. The rule is disabled if it is
+// explicitly disabled in the rule definition, matches any criteria of the
+// disable policy, or doesn't match all criteria of the enable policy.
+func (f Filters) RuleState(rule *FilterConfig) RuleState {
+	if rule.IsDisabled() {
+		return RuleState{Reason: "disabled in rule definition"}
+	}
+	if f.Disable != nil {
+		if reason, ok := f.Disable.matchesAny(rule); ok {
+			return RuleState{Reason: "disable policy: " + reason}
+		}
+	}
+	if f.Enable != nil {
+		if reason, ok := f.Enable.matchesAll(rule); !ok {
+			return RuleState{Reason: "enable policy: " + reason}
+		}
+		return RuleState{Enabled: true, Reason: "enable policy"}
+	}
+	return RuleState{Enabled: true, Reason: "enabled by default"}
+}
+
+// matchesAny returns true and the matching criterion
+// if the rule satisfies any criteria of the policy.
+func (p RulePolicy) matchesAny(rule *FilterConfig) (string, bool) {
+	switch {
+	case slices.Contains(p.IDs, rule.ID):
+		return fmt.Sprintf("id %s", rule.ID), true
+	case containsFold(p.Tactics, rule.Labels["tactic.id"]):
+		return fmt.Sprintf("tactic %s", rule.Labels["tactic.id"]), true
+	case containsFold(p.Techniques, rule.Labels["technique.id"]):
+		return fmt.Sprintf("technique %s", rule.Labels["technique.id"]), true
+	case slices.ContainsFunc(rule.Tags, func(tag string) bool { return containsFold(p.Tags, tag) }):
+		return "tags", true
+	case containsFold(p.Status, rule.EffectiveStatus()):
+		return fmt.Sprintf("status %s", rule.EffectiveStatus()), true
+	case p.MinSeverity != "" && severity(rule.Severity) >= severity(p.MinSeverity):
+		return fmt.Sprintf("severity %s", rule.Severity), true
+	}
+	return "", false
+}
+
+// matchesAll returns true if the rule satisfies all non-empty
+// criteria of the policy. Otherwise, the first unsatisfied
+// criterion is returned.
+func (p RulePolicy) matchesAll(rule *FilterConfig) (string, bool) {
+	switch {
+	case len(p.IDs) > 0 && !slices.Contains(p.IDs, rule.ID):
+		return fmt.Sprintf("id %s not in %v", rule.ID, p.IDs), false
+	case len(p.Tactics) > 0 && !containsFold(p.Tactics, rule.Labels["tactic.id"]):
+		return fmt.Sprintf("tactic %q not in %v", rule.Labels["tactic.id"], p.Tactics), false
+	case len(p.Techniques) > 0 && !containsFold(p.Techniques, rule.Labels["technique.id"]):
+		return fmt.Sprintf("technique %q not in %v", rule.Labels["technique.id"], p.Techniques), false
+	case len(p.Tags) > 0 && !slices.ContainsFunc(rule.Tags, func(tag string) bool { return containsFold(p.Tags, tag) }):
+		return fmt.Sprintf("tags %v not in %v", rule.Tags, p.Tags), false
+	case len(p.Status) > 0 && !containsFold(p.Status, rule.EffectiveStatus()):
+		return fmt.Sprintf("status %s not in %v", rule.EffectiveStatus(), p.Status), false
+	case p.MinSeverity != "" && severity(rule.Severity) < severity(p.MinSeverity):
+		return fmt.Sprintf("severity %s below %s", rule.Severity, p.MinSeverity), false
+	}
+	return "", true
+}
+
+func decodeRulePolicy(v *viper.Viper, key string) (*RulePolicy, error) {
+	raw := v.Get(key)
+	if raw == nil {
+		return nil, nil
+	}
+	var p RulePolicy
+	if err := decode(raw, &p); err != nil {
+		return nil, fmt.Errorf("invalid %s policy: %v", key, err)
+	}
+	return &p, nil
+}
+
+func severity(s string) alertsender.Severity { return alertsender.ParseSeverityFromString(s) }
+
+func containsFold(s []string, v string) bool {
+	if v == "" {
+		return false
+	}
+	return slices.ContainsFunc(s, func(e string) bool { return strings.EqualFold(e, v) })
+}
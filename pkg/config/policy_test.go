@@ -0,0 +1,76 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleState(t *testing.T) {
+	disabled := false
+	credAccess := &FilterConfig{
+		ID:       "e7f3a8b2-3c1d-4e5f-9a6b-7c8d9e0f1a2b",
+		Name:     "LSASS memory dump",
+		Severity: "high",
+		Status:   StatusStable,
+		Labels:   map[string]string{"tactic.id": "TA0006", "technique.id": "T1003"},
+	}
+	defenseEvasion := &FilterConfig{
+		ID:       "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d",
+		Name:     "Suspicious DLL sideloading",
+		Severity: "medium",
+		Status:   StatusExperimental,
+		Labels:   map[string]string{"tactic.id": "TA0005"},
+	}
+	unlabeled := &FilterConfig{
+		ID:       "9f8e7d6c-5b4a-3928-1706-f5e4d3c2b1a0",
+		Name:     "Unlabeled rule",
+		Severity: "critical",
+	}
+
+	var tests = []struct {
+		name    string
+		filters Filters
+		rule    *FilterConfig
+		enabled bool
+		reason  string
+	}{
+		{"no policies", Filters{}, credAccess, true, "enabled by default"},
+		{"disabled in rule", Filters{}, &FilterConfig{Enabled: &disabled}, false, "disabled in rule definition"},
+		{"enable policy match", Filters{Enable: &RulePolicy{Tactics: []string{"TA0006"}, MinSeverity: "high", Status: []string{StatusStable}}}, credAccess, true, "enable policy"},
+		{"enable policy tactic mismatch", Filters{Enable: &RulePolicy{Tactics: []string{"TA0006"}}}, defenseEvasion, false, `enable policy: tactic "TA0005" not in [TA0006]`},
+		{"enable policy status mismatch", Filters{Enable: &RulePolicy{Status: []string{StatusStable}}}, defenseEvasion, false, "enable policy: status experimental not in [stable]"},
+		{"enable policy severity mismatch", Filters{Enable: &RulePolicy{MinSeverity: "high"}}, defenseEvasion, false, "enable policy: severity medium below high"},
+		{"missing status is stable", Filters{Enable: &RulePolicy{Status: []string{StatusStable}}}, unlabeled, true, "enable policy"},
+		{"disable policy by id", Filters{Disable: &RulePolicy{IDs: []string{credAccess.ID}}}, credAccess, false, "disable policy: id " + credAccess.ID},
+		{"disable policy by status", Filters{Disable: &RulePolicy{Status: []string{StatusExperimental}}}, defenseEvasion, false, "disable policy: status experimental"},
+		{"disable policy takes precedence", Filters{Enable: &RulePolicy{Tactics: []string{"TA0006"}}, Disable: &RulePolicy{Techniques: []string{"T1003"}}}, credAccess, false, "disable policy: technique T1003"},
+		{"disable policy no match", Filters{Disable: &RulePolicy{IDs: []string{credAccess.ID}}}, unlabeled, true, "enabled by default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.filters.RuleState(tt.rule)
+			assert.Equal(t, tt.enabled, state.Enabled)
+			assert.Equal(t, tt.reason, state.Reason)
+		})
+	}
+}
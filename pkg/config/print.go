@@ -43,7 +43,7 @@ func (c *Config) printMap(m map[string]interface{}) string {
 			buffer.WriteString(" ")
 			buffer.WriteString(k)
 			buffer.WriteString("=>")
-			if strings.Contains(k, "password") {
+			if isSensitive(k) {
 				buffer.WriteString("********")
 			} else {
 				buffer.WriteString(val)
@@ -54,6 +54,11 @@ func (c *Config) printMap(m map[string]interface{}) string {
 	return buffer.String()
 }
 
+// isSensitive determines if the option value is masked out when printed.
+func isSensitive(k string) bool {
+	return strings.Contains(k, "password") || strings.Contains(k, "token") || strings.Contains(k, "secret")
+}
+
 func (c *Config) print(value interface{}) string {
 	t := reflect.TypeOf(value)
 	switch t.Kind() {
@@ -0,0 +1,550 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ecs maps events to Elastic Common Schema documents.
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/rabbitstack/fibratus/pkg/util/version"
+)
+
+// Version is the ECS version the documents conform to.
+const Version = "8.11.0"
+
+// Document is the ECS representation of the event.
+type Document struct {
+	Timestamp   time.Time         `json:"@timestamp"`
+	ECS         ECS               `json:"ecs"`
+	Agent       Agent             `json:"agent"`
+	Message     string            `json:"message,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Event       Event             `json:"event"`
+	Host        Host              `json:"host"`
+	Process     *Process          `json:"process,omitempty"`
+	User        *User             `json:"user,omitempty"`
+	File        *File             `json:"file,omitempty"`
+	DLL         *DLL              `json:"dll,omitempty"`
+	Source      *Endpoint         `json:"source,omitempty"`
+	Destination *Endpoint         `json:"destination,omitempty"`
+	Network     *Network          `json:"network,omitempty"`
+	DNS         *DNS              `json:"dns,omitempty"`
+	Registry    *Registry         `json:"registry,omitempty"`
+	Rule        *Rule             `json:"rule,omitempty"`
+	Fibratus    Fibratus          `json:"fibratus"`
+}
+
+// ECS holds the schema version.
+type ECS struct {
+	Version string `json:"version"`
+}
+
+// Agent describes the agent that produced the event.
+type Agent struct {
+	Type    string `json:"type"`
+	Version string `json:"version"`
+}
+
+// Event contains the event classification fields.
+type Event struct {
+	Kind     string   `json:"kind"`
+	Category []string `json:"category,omitempty"`
+	Type     []string `json:"type,omitempty"`
+	Action   string   `json:"action"`
+	Sequence uint64   `json:"sequence"`
+	Module   string   `json:"module"`
+	Provider string   `json:"provider"`
+	Dataset  string   `json:"dataset"`
+	Severity int      `json:"severity,omitempty"`
+	Outcome  string   `json:"outcome,omitempty"`
+}
+
+// Host describes the host where the event originated.
+type Host struct {
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	OS       OS     `json:"os"`
+}
+
+// OS describes the operating system.
+type OS struct {
+	Type   string `json:"type"`
+	Family string `json:"family"`
+}
+
+// Process contains process fields.
+type Process struct {
+	PID              uint32     `json:"pid"`
+	Name             string     `json:"name,omitempty"`
+	Executable       string     `json:"executable,omitempty"`
+	CommandLine      string     `json:"command_line,omitempty"`
+	Args             []string   `json:"args,omitempty"`
+	WorkingDirectory string     `json:"working_directory,omitempty"`
+	Start            *time.Time `json:"start,omitempty"`
+	Thread           *Thread    `json:"thread,omitempty"`
+	Parent           *Process   `json:"parent,omitempty"`
+}
+
+// Thread contains thread fields.
+type Thread struct {
+	ID uint32 `json:"id"`
+}
+
+// User contains user fields.
+type User struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Domain string `json:"domain,omitempty"`
+}
+
+// File contains file fields.
+type File struct {
+	Path      string `json:"path"`
+	Name      string `json:"name,omitempty"`
+	Directory string `json:"directory,omitempty"`
+	Extension string `json:"extension,omitempty"`
+}
+
+// DLL contains fields of the loaded or unloaded module.
+type DLL struct {
+	Path string `json:"path"`
+	Name string `json:"name,omitempty"`
+}
+
+// Endpoint represents the source or destination of the network flow.
+type Endpoint struct {
+	IP    string `json:"ip,omitempty"`
+	Port  uint16 `json:"port,omitempty"`
+	Bytes uint32 `json:"bytes,omitempty"`
+}
+
+// Network contains network flow fields.
+type Network struct {
+	Transport string `json:"transport,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Protocol  string `json:"protocol,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	Bytes     uint32 `json:"bytes,omitempty"`
+}
+
+// DNS contains DNS query and answer fields.
+type DNS struct {
+	Type         string      `json:"type"`
+	Question     DNSQuestion `json:"question"`
+	ResponseCode string      `json:"response_code,omitempty"`
+	Answers      []DNSAnswer `json:"answers,omitempty"`
+	ResolvedIP   []string    `json:"resolved_ip,omitempty"`
+}
+
+// DNSQuestion describes the queried name.
+type DNSQuestion struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// DNSAnswer describes the single answer record.
+type DNSAnswer struct {
+	Data string `json:"data"`
+}
+
+// Registry contains registry key and value fields.
+type Registry struct {
+	Path  string        `json:"path"`
+	Hive  string        `json:"hive,omitempty"`
+	Key   string        `json:"key,omitempty"`
+	Value string        `json:"value,omitempty"`
+	Data  *RegistryData `json:"data,omitempty"`
+}
+
+// RegistryData contains the registry value data.
+type RegistryData struct {
+	Type    string   `json:"type,omitempty"`
+	Strings []string `json:"strings,omitempty"`
+}
+
+// Rule contains the name of the rule that matched the event.
+type Rule struct {
+	Name string `json:"name"`
+}
+
+// Fibratus contains event fields without the ECS counterpart.
+type Fibratus struct {
+	Category string            `json:"category"`
+	CPU      uint8             `json:"cpu"`
+	Params   map[string]string `json:"params,omitempty"`
+}
+
+// hives maps registry root keys to ECS hive abbreviations.
+var hives = map[string]string{
+	"HKEY_LOCAL_MACHINE":  "HKLM",
+	"HKEY_CURRENT_USER":   "HKCU",
+	"HKEY_USERS":          "HKU",
+	"HKEY_CLASSES_ROOT":   "HKCR",
+	"HKEY_CURRENT_CONFIG": "HKCC",
+}
+
+// severities maps rule severities to ECS numeric severities.
+var severities = map[string]int{
+	"low":      21,
+	"medium":   47,
+	"high":     73,
+	"critical": 99,
+}
+
+// Marshal serializes the event to the ECS JSON document.
+func Marshal(evt *event.Event) ([]byte, error) {
+	return json.Marshal(New(evt))
+}
+
+// New maps the event to the ECS document.
+func New(evt *event.Event) *Document {
+	doc := &Document{
+		Timestamp: evt.Timestamp.UTC(),
+		ECS:       ECS{Version: Version},
+		Agent:     Agent{Type: "fibratus", Version: version.Get()},
+		Message:   evt.Description,
+		Event: Event{
+			Kind:     "event",
+			Action:   evt.Name,
+			Sequence: evt.Seq,
+			Module:   "fibratus",
+			Provider: "fibratus",
+			Dataset:  "fibratus." + string(evt.Category),
+		},
+		Host: Host{
+			Name:     evt.Host,
+			Hostname: evt.Host,
+			OS:       OS{Type: "windows", Family: "windows"},
+		},
+		Fibratus: Fibratus{Category: string(evt.Category), CPU: evt.CPU},
+	}
+
+	if rule := evt.GetMetaAsString(event.RuleNameKey); rule != "" {
+		doc.Event.Kind = "alert"
+		doc.Rule = &Rule{Name: rule}
+		doc.Event.Severity = severities[strings.ToLower(evt.GetMetaAsString(event.RuleSeverityKey))]
+		if doc.Event.Severity == 0 {
+			doc.Event.Severity = severities["low"]
+		}
+	}
+
+	for k, v := range evt.Metadata {
+		if strings.HasPrefix(k.String(), "rule.") {
+			continue
+		}
+		if doc.Labels == nil {
+			doc.Labels = make(map[string]string)
+		}
+		doc.Labels[strings.ReplaceAll(k.String(), ".", "_")] = fmt.Sprintf("%v", v)
+	}
+
+	if len(evt.Params) > 0 {
+		doc.Fibratus.Params = make(map[string]string, len(evt.Params))
+		for name, par := range evt.Params {
+			doc.Fibratus.Params[name] = par.String()
+		}
+	}
+
+	doc.Process, doc.User = process(evt)
+	doc.Event.Category, doc.Event.Type = classify(evt)
+
+	switch evt.Category {
+	case event.File:
+		doc.File = file(evt.GetParamAsString(params.FilePath))
+	case event.Module:
+		path := evt.GetParamAsString(params.ModulePath)
+		if path != "" {
+			_, name := split(path)
+			doc.DLL = &DLL{Path: path, Name: name}
+		}
+	case event.Registry:
+		doc.Registry = registry(evt)
+	case event.Net:
+		if evt.Type == event.QueryDNS || evt.Type == event.ReplyDNS {
+			doc.DNS = dns(evt)
+			doc.Network = &Network{Protocol: "dns"}
+			break
+		}
+		doc.Source, doc.Destination, doc.Network = network(evt)
+	}
+
+	return doc
+}
+
+// classify resolves ECS event categorization fields.
+func classify(evt *event.Event) ([]string, []string) {
+	switch evt.Type {
+	case event.CreateProcess:
+		return []string{"process"}, []string{"start"}
+	case event.TerminateProcess:
+		return []string{"process"}, []string{"end"}
+	case event.OpenProcess:
+		return []string{"process"}, []string{"access"}
+	case event.ProcessRundown:
+		return []string{"process"}, []string{"info"}
+	case event.CreateFile:
+		if _, err := evt.Params.GetUint32(params.FileOperation); err != nil {
+			return []string{"file"}, []string{"access"}
+		}
+		switch {
+		case evt.IsCreateDisposition():
+			return []string{"file"}, []string{"creation"}
+		case evt.IsOverwriteDisposition():
+			return []string{"file"}, []string{"change"}
+		}
+		return []string{"file"}, []string{"access"}
+	case event.ReadFile, event.EnumDirectory, event.MapViewFile:
+		return []string{"file"}, []string{"access"}
+	case event.WriteFile, event.RenameFile, event.SetFileInformation:
+		return []string{"file"}, []string{"change"}
+	case event.DeleteFile:
+		return []string{"file"}, []string{"deletion"}
+	case event.RegCreateKey:
+		return []string{"registry"}, []string{"creation"}
+	case event.RegDeleteKey, event.RegDeleteValue:
+		return []string{"registry"}, []string{"deletion"}
+	case event.RegSetValue:
+		return []string{"registry"}, []string{"change"}
+	case event.RegOpenKey, event.RegQueryKey, event.RegQueryValue:
+		return []string{"registry"}, []string{"access"}
+	case event.LoadModule:
+		return []string{"library"}, []string{"start"}
+	case event.UnloadModule:
+		return []string{"library"}, []string{"end"}
+	case event.ConnectTCPv4, event.ConnectTCPv6, event.AcceptTCPv4, event.AcceptTCPv6,
+		event.ReconnectTCPv4, event.ReconnectTCPv6:
+		return []string{"network"}, []string{"connection", "start"}
+	case event.DisconnectTCPv4, event.DisconnectTCPv6:
+		return []string{"network"}, []string{"connection", "end"}
+	case event.QueryDNS, event.ReplyDNS:
+		return []string{"network"}, []string{"protocol"}
+	}
+	switch evt.Category {
+	case event.Process:
+		return []string{"process"}, []string{"info"}
+	case event.File:
+		return []string{"file"}, []string{"info"}
+	case event.Net:
+		return []string{"network"}, []string{"connection"}
+	case event.Registry:
+		return []string{"registry"}, []string{"info"}
+	case event.Module:
+		return []string{"library"}, []string{"info"}
+	case event.Driver:
+		return []string{"driver"}, []string{"info"}
+	}
+	return nil, []string{"info"}
+}
+
+// process maps the process and user fields. The subject of the
+// process creation event is the spawned process, so its fields
+// are taken from event parameters while the creator process
+// becomes the parent.
+func process(evt *event.Event) (*Process, *User) {
+	if evt.Type == event.CreateProcess {
+		proc := &Process{
+			PID:         evt.Params.TryGetUint32(params.ProcessID),
+			Name:        evt.GetParamAsString(params.ProcessName),
+			Executable:  evt.GetParamAsString(params.Exe),
+			CommandLine: evt.GetParamAsString(params.Cmdline),
+			Parent:      fromPS(evt.PS),
+		}
+		if start, err := evt.Params.GetTime(params.StartTime); err == nil && !start.IsZero() {
+			start = start.UTC()
+			proc.Start = &start
+		}
+		if proc.Parent == nil && evt.Params.Contains(params.ProcessParentID) {
+			proc.Parent = &Process{PID: evt.Params.TryGetUint32(params.ProcessParentID)}
+		}
+		return proc, user(
+			evt.GetParamAsString(params.UserSID),
+			evt.GetParamAsString(params.Username),
+			evt.GetParamAsString(params.Domain),
+		)
+	}
+
+	proc := fromPS(evt.PS)
+	if proc == nil {
+		proc = &Process{PID: evt.PID}
+	}
+	proc.Thread = &Thread{ID: evt.Tid}
+	if evt.PS == nil {
+		return proc, nil
+	}
+	return proc, user(evt.PS.SID, evt.PS.Username, evt.PS.Domain)
+}
+
+// fromPS maps the process state to ECS process fields.
+func fromPS(ps *pstypes.PS) *Process {
+	if ps == nil {
+		return nil
+	}
+	proc := &Process{
+		PID:              ps.PID,
+		Name:             ps.Name,
+		Executable:       ps.Exe,
+		CommandLine:      ps.Cmdline,
+		Args:             ps.Args,
+		WorkingDirectory: ps.Cwd,
+	}
+	if !ps.StartTime.IsZero() {
+		start := ps.StartTime.UTC()
+		proc.Start = &start
+	}
+	if ps.Parent != nil {
+		proc.Parent = &Process{
+			PID:         ps.Parent.PID,
+			Name:        ps.Parent.Name,
+			Executable:  ps.Parent.Exe,
+			CommandLine: ps.Parent.Cmdline,
+		}
+	} else if ps.Ppid != 0 {
+		proc.Parent = &Process{PID: ps.Ppid}
+	}
+	return proc
+}
+
+func user(sid, name, domain string) *User {
+	if sid == "" && name == "" {
+		return nil
+	}
+	return &User{ID: sid, Name: name, Domain: domain}
+}
+
+// file maps the file path to ECS file fields.
+func file(path string) *File {
+	if path == "" {
+		return nil
+	}
+	dir, name := split(path)
+	f := &File{Path: path, Name: name, Directory: dir}
+	if n := strings.LastIndexByte(name, '.'); n > 0 {
+		f.Extension = strings.ToLower(name[n+1:])
+	}
+	return f
+}
+
+// registry maps the registry key path to ECS registry fields.
+// Value events carry the value name as the last path component.
+func registry(evt *event.Event) *Registry {
+	path := evt.GetParamAsString(params.RegPath)
+	if path == "" {
+		return nil
+	}
+	reg := &Registry{Path: path}
+	root, key, _ := strings.Cut(path, "\\")
+	reg.Hive = hives[root]
+	reg.Key = key
+
+	switch evt.Type {
+	case event.RegSetValue, event.RegQueryValue, event.RegDeleteValue:
+		key, value := split(key)
+		reg.Key, reg.Value = key, value
+	}
+
+	if evt.Type == event.RegSetValue && evt.Params.Contains(params.RegData) {
+		reg.Data = &RegistryData{
+			Type:    evt.GetParamAsString(params.RegValueType),
+			Strings: []string{evt.GetParamAsString(params.RegData)},
+		}
+	}
+
+	return reg
+}
+
+// network maps network parameters to ECS source, destination and network fields.
+func network(evt *event.Event) (*Endpoint, *Endpoint, *Network) {
+	src := &Endpoint{Port: evt.Params.TryGetUint16(params.NetSport)}
+	dst := &Endpoint{Port: evt.Params.TryGetUint16(params.NetDport)}
+	nw := &Network{
+		Transport: strings.ToLower(evt.GetParamAsString(params.NetL4Proto)),
+		Bytes:     evt.Params.TryGetUint32(params.NetSize),
+	}
+
+	if ip, err := evt.Params.GetIP(params.NetSIP); err == nil {
+		src.IP = ip.String()
+		nw.Type = ipType(ip)
+	}
+	if ip, err := evt.Params.GetIP(params.NetDIP); err == nil {
+		dst.IP = ip.String()
+		nw.Type = ipType(ip)
+	}
+
+	switch evt.Type {
+	case event.AcceptTCPv4, event.AcceptTCPv6, event.RecvTCPv4, event.RecvTCPv6, event.RecvUDPv4, event.RecvUDPv6:
+		nw.Direction = "ingress"
+		dst.Bytes = nw.Bytes
+	case event.ConnectTCPv4, event.ConnectTCPv6, event.SendTCPv4, event.SendTCPv6, event.SendUDPv4, event.SendUDPv6:
+		nw.Direction = "egress"
+		src.Bytes = nw.Bytes
+	}
+
+	return src, dst, nw
+}
+
+// dns maps the DNS query or reply parameters to ECS DNS fields.
+func dns(evt *event.Event) *DNS {
+	d := &DNS{
+		Type: "query",
+		Question: DNSQuestion{
+			Name: evt.GetParamAsString(params.DNSName),
+			Type: evt.GetParamAsString(params.DNSRR),
+		},
+	}
+	if evt.Type != event.ReplyDNS {
+		return d
+	}
+
+	d.Type = "answer"
+	d.ResponseCode = evt.GetParamAsString(params.DNSRcode)
+	answers, _ := evt.Params.GetStringSlice(params.DNSAnswers)
+	for _, answer := range answers {
+		d.Answers = append(d.Answers, DNSAnswer{Data: answer})
+		if ip := net.ParseIP(answer); ip != nil {
+			d.ResolvedIP = append(d.ResolvedIP, ip.String())
+		}
+	}
+	sort.Strings(d.ResolvedIP)
+
+	return d
+}
+
+func ipType(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// split splits the Windows path into the parent and the last component.
+func split(path string) (string, string) {
+	n := strings.LastIndexByte(path, '\\')
+	if n < 0 {
+		return "", path
+	}
+	return path[:n], path[n+1:]
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ecs
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/rabbitstack/fibratus/pkg/event/eventtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestMarshal(t *testing.T) {
+	for _, f := range eventtest.Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			b, err := Marshal(f.Event)
+			require.NoError(t, err)
+			eventtest.Golden(t, f.Name, b, *update)
+		})
+	}
+}
+
+func TestMarshalFields(t *testing.T) {
+	var tests = map[string]map[string]any{
+		"create-process": {
+			"event.kind":         "alert",
+			"event.category":     []any{"process"},
+			"event.type":         []any{"start"},
+			"process.pid":        float64(5120),
+			"process.parent.pid": float64(2484),
+			"process.executable": "C:\\Windows\\System32\\whoami.exe",
+			"rule.name":          "System discovery via whoami",
+		},
+		"create-file": {
+			"event.kind":     "event",
+			"event.category": []any{"file"},
+			"event.type":     []any{"creation"},
+			"process.pid":    float64(2484),
+			"file.path":      "C:\\Users\\admin\\Desktop\\Report.DOCX",
+		},
+		"set-value": {
+			"event.category": []any{"registry"},
+			"event.type":     []any{"change"},
+			"registry.path":  "HKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Run\\Updater",
+		},
+		"connect": {
+			"event.category":   []any{"network"},
+			"event.type":       []any{"connection", "start"},
+			"destination.ip":   "140.82.121.4",
+			"destination.port": float64(443),
+		},
+		"reply-dns": {
+			"event.category":    []any{"network"},
+			"dns.question.name": "github.com",
+		},
+		"load-module": {
+			"event.category": []any{"library"},
+			"process.pid":    float64(2484),
+		},
+		"virtual-alloc": {
+			"event.type":  []any{"info"},
+			"process.pid": float64(2484),
+		},
+	}
+
+	for _, f := range eventtest.Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			require.Contains(t, tests, f.Name)
+			b, err := Marshal(f.Event)
+			require.NoError(t, err)
+			for path, want := range tests[f.Name] {
+				assert.Equal(t, want, eventtest.Field(t, b, path), path)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventpb
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format designates the binary serialization format of the event stream.
+type Format string
+
+const (
+	// Protobuf designates the Protobuf wire format. Event streams are
+	// framed by prefixing each message with its varint-encoded size.
+	Protobuf Format = "protobuf"
+	// Msgpack designates the MessagePack format. MessagePack documents
+	// are self-delimiting, so event streams are plain concatenations.
+	Msgpack Format = "msgpack"
+)
+
+// UnmarshalBatch decodes the batch of events as published by the HTTP
+// and AMQP outputs in the request body or message payload respectively.
+func UnmarshalBatch(b []byte, format Format) ([]*Event, error) {
+	batch := &Batch{}
+	switch format {
+	case Protobuf:
+		if err := proto.Unmarshal(b, batch); err != nil {
+			return nil, err
+		}
+	case Msgpack:
+		if err := UnmarshalMsgpack(b, batch); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return batch.Events, nil
+}
+
+// Decoder reads events from the stream written by the file output.
+type Decoder struct {
+	format Format
+	r      *bufio.Reader
+	dec    *msgpack.Decoder
+}
+
+// NewDecoder creates a new decoder that reads events in the given format.
+func NewDecoder(r io.Reader, format Format) *Decoder {
+	d := &Decoder{format: format, r: bufio.NewReader(r)}
+	if format == Msgpack {
+		d.dec = msgpack.NewDecoder(d.r)
+		d.dec.SetCustomStructTag(structTag)
+	}
+	return d
+}
+
+// Decode reads the next event from the stream. It returns io.EOF
+// when there are no more events to read.
+func (d *Decoder) Decode() (*Event, error) {
+	e := &Event{}
+	switch d.format {
+	case Protobuf:
+		if err := protodelim.UnmarshalFrom(d.r, e); err != nil {
+			return nil, err
+		}
+	case Msgpack:
+		if err := d.dec.Decode(e); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", d.format)
+	}
+	return e, nil
+}
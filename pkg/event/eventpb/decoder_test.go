@@ -0,0 +1,135 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventpb
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newEvent(seq uint64) *Event {
+	return &Event{
+		Seq:       seq,
+		Pid:       4024,
+		Name:      "CreateProcess",
+		Category:  "process",
+		Host:      "archrabbit",
+		Timestamp: timestamppb.New(time.Date(2025, 3, 14, 10, 11, 12, 0, time.UTC)),
+		Params: []*Param{
+			{Name: "cmdline", Type: "unicode", Value: &Param_StringValue{StringValue: `C:\Windows\System32\cmd.exe /c dir`}},
+			{Name: "exit_status", Type: "int32", Value: &Param_IntValue{IntValue: -1}},
+			{Name: "pid", Type: "pid", Value: &Param_UintValue{UintValue: 4024}},
+			{Name: "entropy", Type: "double", Value: &Param_DoubleValue{DoubleValue: 6.36}},
+			{Name: "is_dir", Type: "bool", Value: &Param_BoolValue{BoolValue: true}},
+			{Name: "start_time", Type: "time", Value: &Param_TimeValue{TimeValue: timestamppb.New(time.Date(2025, 3, 14, 10, 11, 10, 0, time.UTC))}},
+			{Name: "dip_names", Type: "slice", Value: &Param_StringList{StringList: &StringList{Values: []string{"dns.google."}}}},
+			{Name: "empty", Type: "unknown"},
+		},
+		Metadata: map[string]string{"foo": "bar"},
+		Ps: &Process{
+			Pid:     4024,
+			Name:    "cmd.exe",
+			Args:    []string{"/c", "dir"},
+			Parent:  &Process{Pid: 2048, Name: "explorer.exe"},
+			Modules: []*Module{{Name: `C:\Windows\System32\kernel32.dll`, Size: 12354, BaseAddress: 0x7ffb5c1d0000}},
+			Pe:      &PE{Nsections: 2, Resources: map[string]string{"CompanyName": "Microsoft Corporation"}},
+		},
+		Callstack: []*Frame{{Addr: 0x7ffb5c1d0396, Offset: 0x61, Symbol: "CreateFileW", Module: "kernelbase.dll"}},
+	}
+}
+
+func TestUnmarshalBatch(t *testing.T) {
+	batch := &Batch{Events: []*Event{newEvent(1), newEvent(2)}}
+
+	var tests = []struct {
+		format  Format
+		marshal func(*Batch) ([]byte, error)
+	}{
+		{Protobuf, func(b *Batch) ([]byte, error) { return proto.Marshal(b) }},
+		{Msgpack, func(b *Batch) ([]byte, error) { return MarshalMsgpack(b) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			b, err := tt.marshal(batch)
+			require.NoError(t, err)
+			events, err := UnmarshalBatch(b, tt.format)
+			require.NoError(t, err)
+			require.Len(t, events, 2)
+			for i, evt := range events {
+				assert.True(t, proto.Equal(batch.Events[i], evt), "event %d: %v", i, evt)
+			}
+		})
+	}
+
+	_, err := UnmarshalBatch([]byte{}, "xml")
+	require.Error(t, err)
+}
+
+func TestDecoder(t *testing.T) {
+	var tests = []struct {
+		format Format
+		write  func(io.Writer, *Event) error
+	}{
+		{Protobuf, func(w io.Writer, e *Event) error { _, err := protodelim.MarshalTo(w, e); return err }},
+		{Msgpack, func(w io.Writer, e *Event) error {
+			b, err := MarshalMsgpack(e)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(b)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			for seq := uint64(1); seq <= 3; seq++ {
+				require.NoError(t, tt.write(&buf, newEvent(seq)))
+			}
+
+			dec := NewDecoder(&buf, tt.format)
+			for seq := uint64(1); seq <= 3; seq++ {
+				evt, err := dec.Decode()
+				require.NoError(t, err)
+				assert.True(t, proto.Equal(newEvent(seq), evt), "event %d: %v", seq, evt)
+			}
+			_, err := dec.Decode()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestMsgpackParamFieldNames(t *testing.T) {
+	b, err := MarshalMsgpack(&Param{Name: "pid", Type: "pid", Value: &Param_UintValue{UintValue: 4024}})
+	require.NoError(t, err)
+
+	var m map[string]any
+	require.NoError(t, UnmarshalMsgpack(b, &m))
+	assert.Equal(t, map[string]any{"name": "pid", "type": "pid", "uint_value": uint16(4024)}, m)
+}
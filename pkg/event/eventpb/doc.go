@@ -0,0 +1,26 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package eventpb contains the Protobuf schema of the event along with the
+// helpers for encoding and decoding the compact binary streams produced by
+// the outputs configured with the protobuf or msgpack serializer. The package
+// doesn't depend on any platform-specific code, so consumers can import it
+// to read the event stream on any operating system.
+package eventpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative event.proto
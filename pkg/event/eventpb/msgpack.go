@@ -0,0 +1,161 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventpb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// structTag is the struct tag used to derive MessagePack field names. The
+// generated messages carry JSON tags named after the Protobuf fields, so
+// MessagePack documents share field names with the schema.
+const structTag = "json"
+
+// MarshalMsgpack encodes the event or the batch of events in MessagePack
+// format. Integers are encoded in the most compact representation.
+func MarshalMsgpack(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag(structTag)
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalMsgpack decodes the MessagePack document into the event or the batch.
+func UnmarshalMsgpack(b []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(b))
+	dec.SetCustomStructTag(structTag)
+	return dec.Decode(v)
+}
+
+// EncodeMsgpack encodes the parameter as a map where the value is keyed by
+// the name of the populated oneof field, e.g. {"name": "pid", "type": "pid",
+// "uint_value": 4}, so the value type survives the round trip.
+func (p *Param) EncodeMsgpack(enc *msgpack.Encoder) error {
+	key, value := p.oneof()
+	n := 2
+	if key != "" {
+		n++
+	}
+	if err := enc.EncodeMapLen(n); err != nil {
+		return err
+	}
+	if err := enc.EncodeString("name"); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(p.Name); err != nil {
+		return err
+	}
+	if err := enc.EncodeString("type"); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(p.Type); err != nil {
+		return err
+	}
+	if key == "" {
+		return nil
+	}
+	if err := enc.EncodeString(key); err != nil {
+		return err
+	}
+	return enc.Encode(value)
+}
+
+// DecodeMsgpack decodes the parameter encoded by EncodeMsgpack.
+func (p *Param) DecodeMsgpack(dec *msgpack.Decoder) error {
+	n, err := dec.DecodeMapLen()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		key, err := dec.DecodeString()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "name":
+			p.Name, err = dec.DecodeString()
+		case "type":
+			p.Type, err = dec.DecodeString()
+		case "string_value":
+			var v string
+			v, err = dec.DecodeString()
+			p.Value = &Param_StringValue{StringValue: v}
+		case "int_value":
+			var v int64
+			v, err = dec.DecodeInt64()
+			p.Value = &Param_IntValue{IntValue: v}
+		case "uint_value":
+			var v uint64
+			v, err = dec.DecodeUint64()
+			p.Value = &Param_UintValue{UintValue: v}
+		case "double_value":
+			var v float64
+			v, err = dec.DecodeFloat64()
+			p.Value = &Param_DoubleValue{DoubleValue: v}
+		case "bool_value":
+			var v bool
+			v, err = dec.DecodeBool()
+			p.Value = &Param_BoolValue{BoolValue: v}
+		case "time_value":
+			v := &timestamppb.Timestamp{}
+			err = dec.Decode(v)
+			p.Value = &Param_TimeValue{TimeValue: v}
+		case "string_list":
+			v := &StringList{}
+			err = dec.Decode(v)
+			p.Value = &Param_StringList{StringList: v}
+		default:
+			err = dec.Skip()
+		}
+		if err != nil {
+			return fmt.Errorf("unable to decode %q param field: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// oneof returns the field name and the value of the populated oneof field.
+func (p *Param) oneof() (string, any) {
+	switch v := p.Value.(type) {
+	case *Param_StringValue:
+		return "string_value", v.StringValue
+	case *Param_IntValue:
+		return "int_value", v.IntValue
+	case *Param_UintValue:
+		return "uint_value", v.UintValue
+	case *Param_DoubleValue:
+		return "double_value", v.DoubleValue
+	case *Param_BoolValue:
+		return "bool_value", v.BoolValue
+	case *Param_TimeValue:
+		return "time_value", v.TimeValue
+	case *Param_StringList:
+		return "string_list", v.StringList
+	}
+	return "", nil
+}
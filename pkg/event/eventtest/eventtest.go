@@ -0,0 +1,265 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package eventtest provides event fixtures and helpers
+// for testing event serializers.
+package eventtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	"github.com/rabbitstack/fibratus/pkg/fs"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Fixture is the event along with the name of the golden file.
+type Fixture struct {
+	Name  string
+	Event *event.Event
+}
+
+// Timestamp is the time at which fixture events occurred.
+var Timestamp = time.Date(2024, 5, 14, 10, 22, 41, 125000000, time.UTC)
+
+// Fixtures returns events of the main event categories.
+// All events, except the memory event, are generated by
+// the same cmd.exe process.
+func Fixtures() []Fixture {
+	ps := &pstypes.PS{
+		PID:       2484,
+		Ppid:      768,
+		Name:      "cmd.exe",
+		Exe:       "C:\\Windows\\System32\\cmd.exe",
+		Cmdline:   "C:\\Windows\\System32\\cmd.exe /c whoami",
+		Cwd:       "C:\\Users\\admin",
+		Args:      []string{"/c", "whoami"},
+		SID:       "S-1-5-21-2271034452-3606720046-2658486931-1001",
+		Username:  "admin",
+		Domain:    "ARCHRABBIT",
+		StartTime: time.Date(2024, 5, 14, 10, 20, 0, 0, time.UTC),
+		Parent: &pstypes.PS{
+			PID:     768,
+			Name:    "explorer.exe",
+			Exe:     "C:\\Windows\\explorer.exe",
+			Cmdline: "C:\\Windows\\explorer.exe",
+		},
+	}
+
+	return []Fixture{
+		{
+			"create-process",
+			&event.Event{
+				Type:        event.CreateProcess,
+				Seq:         10,
+				PID:         2484,
+				Tid:         3920,
+				CPU:         2,
+				Name:        "CreateProcess",
+				Category:    event.Process,
+				Timestamp:   Timestamp,
+				Host:        "archrabbit",
+				Description: "Creates a new process and its primary thread",
+				Params: event.Params{
+					params.ProcessID:       {Name: params.ProcessID, Type: params.PID, Value: uint32(5120)},
+					params.ProcessParentID: {Name: params.ProcessParentID, Type: params.PID, Value: uint32(2484)},
+					params.ProcessName:     {Name: params.ProcessName, Type: params.UnicodeString, Value: "whoami.exe"},
+					params.Exe:             {Name: params.Exe, Type: params.UnicodeString, Value: "C:\\Windows\\System32\\whoami.exe"},
+					params.Cmdline:         {Name: params.Cmdline, Type: params.UnicodeString, Value: "whoami"},
+					params.Username:        {Name: params.Username, Type: params.UnicodeString, Value: "admin"},
+					params.Domain:          {Name: params.Domain, Type: params.UnicodeString, Value: "ARCHRABBIT"},
+				},
+				Metadata: event.Metadata{
+					event.RuleNameKey:     "System discovery via whoami",
+					event.RuleSeverityKey: "high",
+				},
+				PS: ps,
+			},
+		},
+		{
+			"create-file",
+			&event.Event{
+				Type:      event.CreateFile,
+				Seq:       11,
+				PID:       2484,
+				Tid:       3920,
+				Name:      "CreateFile",
+				Category:  event.File,
+				Timestamp: Timestamp,
+				Host:      "archrabbit",
+				Params: event.Params{
+					params.FilePath:      {Name: params.FilePath, Type: params.UnicodeString, Value: "C:\\Users\\admin\\Desktop\\Report.DOCX"},
+					params.FileOperation: {Name: params.FileOperation, Type: params.Enum, Value: uint32(2), Enum: fs.FileCreateDispositions},
+				},
+				Metadata: event.Metadata{"foo": "bar"},
+				PS:       ps,
+			},
+		},
+		{
+			"set-value",
+			&event.Event{
+				Type:      event.RegSetValue,
+				Seq:       12,
+				PID:       2484,
+				Tid:       3920,
+				Name:      "RegSetValue",
+				Category:  event.Registry,
+				Timestamp: Timestamp,
+				Host:      "archrabbit",
+				Params: event.Params{
+					params.RegPath:      {Name: params.RegPath, Type: params.UnicodeString, Value: "HKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Run\\Updater"},
+					params.RegValueType: {Name: params.RegValueType, Type: params.AnsiString, Value: "REG_SZ"},
+					params.RegData:      {Name: params.RegData, Type: params.UnicodeString, Value: "C:\\Temp\\updater.exe"},
+				},
+				PS: ps,
+			},
+		},
+		{
+			"connect",
+			&event.Event{
+				Type:      event.ConnectTCPv4,
+				Seq:       13,
+				PID:       2484,
+				Tid:       3920,
+				Name:      "Connect",
+				Category:  event.Net,
+				Timestamp: Timestamp,
+				Host:      "archrabbit",
+				Params: event.Params{
+					params.NetSIP:     {Name: params.NetSIP, Type: params.IPv4, Value: net.ParseIP("192.168.1.10")},
+					params.NetSport:   {Name: params.NetSport, Type: params.Port, Value: uint16(49712)},
+					params.NetDIP:     {Name: params.NetDIP, Type: params.IPv4, Value: net.ParseIP("140.82.121.4")},
+					params.NetDport:   {Name: params.NetDport, Type: params.Port, Value: uint16(443)},
+					params.NetSize:    {Name: params.NetSize, Type: params.Uint32, Value: uint32(0)},
+					params.NetL4Proto: {Name: params.NetL4Proto, Type: params.AnsiString, Value: "TCP"},
+				},
+				PS: ps,
+			},
+		},
+		{
+			"reply-dns",
+			&event.Event{
+				Type:      event.ReplyDNS,
+				Seq:       14,
+				PID:       2484,
+				Tid:       3920,
+				Name:      "ReplyDns",
+				Category:  event.Net,
+				Timestamp: Timestamp,
+				Host:      "archrabbit",
+				Params: event.Params{
+					params.DNSName:    {Name: params.DNSName, Type: params.UnicodeString, Value: "github.com"},
+					params.DNSRR:      {Name: params.DNSRR, Type: params.AnsiString, Value: "A"},
+					params.DNSRcode:   {Name: params.DNSRcode, Type: params.AnsiString, Value: "NOERROR"},
+					params.DNSAnswers: {Name: params.DNSAnswers, Type: params.Slice, Value: []string{"140.82.121.4", "github.com."}},
+				},
+				PS: ps,
+			},
+		},
+		{
+			"load-module",
+			&event.Event{
+				Type:      event.LoadModule,
+				Seq:       15,
+				PID:       2484,
+				Tid:       3920,
+				Name:      "LoadModule",
+				Category:  event.Module,
+				Timestamp: Timestamp,
+				Host:      "archrabbit",
+				Params: event.Params{
+					params.ModulePath: {Name: params.ModulePath, Type: params.UnicodeString, Value: "C:\\Windows\\System32\\user32.dll"},
+				},
+				PS: ps,
+			},
+		},
+		{
+			"virtual-alloc",
+			&event.Event{
+				Type:      event.VirtualAlloc,
+				Seq:       16,
+				PID:       2484,
+				Tid:       3920,
+				Name:      "VirtualAlloc",
+				Category:  event.Mem,
+				Timestamp: Timestamp,
+				Host:      "archrabbit",
+				Params: event.Params{
+					params.MemRegionSize: {Name: params.MemRegionSize, Type: params.Uint64, Value: uint64(4096)},
+				},
+			},
+		},
+	}
+}
+
+// Batch returns the batch of fixture events with the given names.
+// Events are laid out in the order in which names are specified.
+func Batch(names ...string) *event.Batch {
+	fixtures := Fixtures()
+	evts := make([]*event.Event, 0, len(names))
+	for _, name := range names {
+		for _, f := range fixtures {
+			if f.Name == name {
+				evts = append(evts, f.Event)
+			}
+		}
+	}
+	return event.NewBatch(evts...)
+}
+
+// Golden compares the serialized event with the golden file in
+// the _fixtures directory. If update is true, the golden file is
+// rewritten with the serialized event first.
+func Golden(t *testing.T, name string, b []byte, update bool) {
+	t.Helper()
+	path := filepath.Join("_fixtures", name+".json")
+	if update {
+		var buf bytes.Buffer
+		require.NoError(t, json.Indent(&buf, b, "", "  "))
+		require.NoError(t, os.WriteFile(path, append(buf.Bytes(), '\n'), 0644))
+	}
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(golden), string(b))
+}
+
+// Field returns the value of the dotted path in the serialized
+// event. Numbers are decoded as float64.
+func Field(t *testing.T, b []byte, path string) any {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal(b, &v))
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
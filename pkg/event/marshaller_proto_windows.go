@@ -0,0 +1,233 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event/eventpb"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	ptypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Proto converts the event to its Protobuf representation. Process state
+// resources such as threads, modules, or handles are included according
+// to the same serialization flags that govern the JSON payload.
+func (e *Event) Proto() *eventpb.Event {
+	if e == nil {
+		return nil
+	}
+	evt := &eventpb.Event{
+		Seq:         e.Seq,
+		Pid:         e.PID,
+		Tid:         e.Tid,
+		Cpu:         uint32(e.CPU),
+		Name:        e.Name,
+		Category:    string(e.Category),
+		Description: e.Description,
+		Host:        e.Host,
+		Timestamp:   timestamppb.New(e.Timestamp),
+		Params:      make([]*eventpb.Param, 0, len(e.Params)),
+		Metadata:    make(map[string]string, len(e.Metadata)),
+		Ps:          psProto(e.PS),
+	}
+
+	for _, par := range e.Params {
+		evt.Params = append(evt.Params, e.paramProto(par))
+	}
+	sort.Slice(evt.Params, func(i, j int) bool { return evt.Params[i].Name < evt.Params[j].Name })
+
+	for k, v := range e.Metadata {
+		evt.Metadata[k.String()] = fmt.Sprintf("%s", v)
+	}
+
+	for _, frame := range e.Callstack {
+		evt.Callstack = append(evt.Callstack, &eventpb.Frame{
+			Pid:           frame.PID,
+			Addr:          frame.Addr.Uint64(),
+			Offset:        frame.Offset,
+			Symbol:        frame.Symbol,
+			Module:        frame.Module,
+			ModuleAddress: frame.ModuleAddress.Uint64(),
+		})
+	}
+
+	return evt
+}
+
+// paramProto converts the parameter to the typed Protobuf parameter. Parameters
+// without a native Protobuf counterpart are rendered as strings.
+func (e *Event) paramProto(par *Param) *eventpb.Param {
+	p := &eventpb.Param{Name: par.Name, Type: par.Type.String()}
+	switch par.Type {
+	case params.Int64:
+		p.Value = &eventpb.Param_IntValue{IntValue: par.Value.(int64)}
+	case params.Int32:
+		p.Value = &eventpb.Param_IntValue{IntValue: int64(par.Value.(int32))}
+	case params.Int16:
+		p.Value = &eventpb.Param_IntValue{IntValue: int64(par.Value.(int16))}
+	case params.Int8:
+		p.Value = &eventpb.Param_IntValue{IntValue: int64(par.Value.(int8))}
+	case params.Uint64:
+		p.Value = &eventpb.Param_UintValue{UintValue: par.Value.(uint64)}
+	case params.Uint32, params.PID, params.TID:
+		p.Value = &eventpb.Param_UintValue{UintValue: uint64(par.Value.(uint32))}
+	case params.Uint16, params.Port:
+		p.Value = &eventpb.Param_UintValue{UintValue: uint64(par.Value.(uint16))}
+	case params.Uint8:
+		p.Value = &eventpb.Param_UintValue{UintValue: uint64(par.Value.(uint8))}
+	case params.Float:
+		p.Value = &eventpb.Param_DoubleValue{DoubleValue: float64(par.Value.(float32))}
+	case params.Double:
+		p.Value = &eventpb.Param_DoubleValue{DoubleValue: par.Value.(float64)}
+	case params.IPv4, params.IPv6:
+		p.Value = &eventpb.Param_StringValue{StringValue: par.Value.(net.IP).String()}
+	case params.Bool:
+		p.Value = &eventpb.Param_BoolValue{BoolValue: par.Value.(bool)}
+	case params.Time:
+		p.Value = &eventpb.Param_TimeValue{TimeValue: timestamppb.New(par.Value.(time.Time))}
+	case params.Slice:
+		if slice, ok := par.Value.([]string); ok {
+			p.Value = &eventpb.Param_StringList{StringList: &eventpb.StringList{Values: slice}}
+			break
+		}
+		p.Value = &eventpb.Param_StringValue{StringValue: e.GetParamAsString(par.Name)}
+	default:
+		p.Value = &eventpb.Param_StringValue{StringValue: e.GetParamAsString(par.Name)}
+	}
+	return p
+}
+
+// psProto converts the process state to its Protobuf representation.
+func psProto(ps *ptypes.PS) *eventpb.Process {
+	if ps == nil {
+		return nil
+	}
+	proc := &eventpb.Process{
+		Pid:                 ps.PID,
+		Ppid:                ps.Ppid,
+		Name:                ps.Name,
+		Cmdline:             ps.Cmdline,
+		Exe:                 ps.Exe,
+		Cwd:                 ps.Cwd,
+		Sid:                 ps.SID,
+		Args:                ps.Args,
+		SessionId:           ps.SessionID,
+		Username:            ps.Username,
+		Domain:              ps.Domain,
+		IsWow64:             ps.IsWOW64,
+		IsPackaged:          ps.IsPackaged,
+		IsProtected:         ps.IsProtected,
+		TokenIntegrityLevel: ps.TokenIntegrityLevel,
+		TokenElevationType:  ps.TokenElevationType,
+		IsTokenElevated:     ps.IsTokenElevated,
+	}
+	if !ps.StartTime.IsZero() {
+		proc.StartTime = timestamppb.New(ps.StartTime)
+	}
+
+	if parent := ps.Parent; parent != nil {
+		proc.Parent = &eventpb.Process{
+			Pid:     parent.PID,
+			Name:    parent.Name,
+			Cmdline: parent.Cmdline,
+			Exe:     parent.Exe,
+			Cwd:     parent.Cwd,
+			Sid:     parent.SID,
+		}
+	}
+
+	if SerializeEnvs {
+		proc.Envs = ps.Envs
+	}
+
+	if SerializeThreads {
+		ps.RLock()
+		for _, thread := range ps.Threads {
+			proc.Threads = append(proc.Threads, &eventpb.Thread{
+				Tid:          thread.Tid,
+				IoPrio:       uint32(thread.IOPrio),
+				BasePrio:     uint32(thread.BasePrio),
+				PagePrio:     uint32(thread.PagePrio),
+				StartAddress: thread.StartAddress.Uint64(),
+				UstackBase:   thread.UstackBase.Uint64(),
+				UstackLimit:  thread.UstackLimit.Uint64(),
+				KstackBase:   thread.KstackBase.Uint64(),
+				KstackLimit:  thread.KstackLimit.Uint64(),
+			})
+		}
+		ps.RUnlock()
+		sort.Slice(proc.Threads, func(i, j int) bool { return proc.Threads[i].Tid < proc.Threads[j].Tid })
+	}
+
+	if SerializeModules {
+		for _, mod := range ps.Modules {
+			proc.Modules = append(proc.Modules, &eventpb.Module{
+				Name:        mod.Name,
+				Size:        mod.Size,
+				BaseAddress: mod.BaseAddress.Uint64(),
+			})
+		}
+	}
+
+	if SerializeHandles {
+		for _, h := range ps.Handles {
+			proc.Handles = append(proc.Handles, &eventpb.Handle{
+				Id:     uint64(h.Num),
+				Type:   h.Type,
+				Name:   h.Name,
+				Object: h.Object,
+			})
+		}
+	}
+
+	if pe := ps.PE; SerializePE && pe != nil {
+		proc.Pe = &eventpb.PE{
+			Nsections:    uint32(pe.NumberOfSections),
+			Nsymbols:     pe.NumberOfSymbols,
+			ImageBase:    pe.ImageBase,
+			Entrypoint:   pe.EntryPoint,
+			Symbols:      pe.Symbols,
+			Imports:      pe.Imports,
+			Resources:    pe.VersionResources,
+			IsDll:        pe.IsDLL,
+			IsDriver:     pe.IsDriver,
+			IsExecutable: pe.IsExecutable,
+			IsDotnet:     pe.IsDotnet,
+			Imphash:      pe.Imphash,
+		}
+		if !pe.LinkTime.IsZero() {
+			proc.Pe.LinkTime = timestamppb.New(pe.LinkTime)
+		}
+		for _, sec := range pe.Sections {
+			proc.Pe.Sections = append(proc.Pe.Sections, &eventpb.Section{
+				Name:    sec.Name,
+				Size:    sec.Size,
+				Entropy: sec.Entropy,
+				Md5:     sec.Md5,
+			})
+		}
+	}
+
+	return proc
+}
@@ -20,6 +20,7 @@ package event
 
 import (
 	"encoding/json"
+	"net"
 	"os"
 	"testing"
 	"time"
@@ -28,12 +29,15 @@ import (
 	"github.com/rabbitstack/fibratus/pkg/util/va"
 	"golang.org/x/sys/windows"
 
+	"github.com/rabbitstack/fibratus/pkg/callstack"
+	"github.com/rabbitstack/fibratus/pkg/event/eventpb"
 	"github.com/rabbitstack/fibratus/pkg/event/params"
 	htypes "github.com/rabbitstack/fibratus/pkg/handle/types"
 	pex "github.com/rabbitstack/fibratus/pkg/pe"
 	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func init() {
@@ -217,6 +221,76 @@ func TestEventMarshalJSON(t *testing.T) {
 	assert.Len(t, newEvt.PS.PE.VersionResources, 3)
 }
 
+func TestEventProto(t *testing.T) {
+	evt := &Event{
+		Type:        ConnectTCPv4,
+		Tid:         2484,
+		PID:         859,
+		CPU:         1,
+		Seq:         2,
+		Name:        "Connect",
+		Timestamp:   time.Date(2024, 3, 1, 10, 21, 5, 0, time.UTC),
+		Category:    Net,
+		Host:        "archrabbit",
+		Description: "Connects establishes a connection to the socket",
+		Params: Params{
+			params.NetDIP:      {Name: params.NetDIP, Type: params.IPv4, Value: net.ParseIP("216.58.201.174")},
+			params.NetDport:    {Name: params.NetDport, Type: params.Port, Value: uint16(443)},
+			params.NetSize:     {Name: params.NetSize, Type: params.Int32, Value: int32(-1)},
+			params.NetDIPNames: {Name: params.NetDIPNames, Type: params.Slice, Value: []string{"dns.google.", "github.com."}},
+		},
+		Metadata: map[MetadataKey]any{"foo": "bar"},
+		PS: &pstypes.PS{
+			PID:     2436,
+			Ppid:    6304,
+			Name:    "firefox.exe",
+			Exe:     `C:\Program Files\Mozilla Firefox\firefox.exe`,
+			Parent:  &pstypes.PS{PID: 6304, Name: "explorer.exe"},
+			Envs:    map[string]string{"ProgramData": "C:\\ProgramData"},
+			Modules: []pstypes.Module{{Name: `C:\Windows\System32\kernel32.dll`, Size: 12354, BaseAddress: va.Address(0x7ffb5c1d0000)}},
+			Threads: map[uint32]pstypes.Thread{
+				3455: {Tid: 3455, StartAddress: va.Address(140729524944768)},
+				3453: {Tid: 3453, StartAddress: va.Address(140729524944768)},
+			},
+		},
+		Callstack: callstack.Callstack{
+			{Addr: va.Address(0x7ffb5c1d0396), Offset: 0x61, Symbol: "CreateFileW", Module: `C:\Windows\System32\kernelbase.dll`},
+		},
+	}
+
+	b, err := proto.Marshal(evt.Proto())
+	require.NoError(t, err)
+
+	var pb eventpb.Event
+	require.NoError(t, proto.Unmarshal(b, &pb))
+
+	assert.Equal(t, uint64(2), pb.Seq)
+	assert.Equal(t, "net", pb.Category)
+	assert.Equal(t, evt.Timestamp, pb.Timestamp.AsTime())
+	assert.Equal(t, map[string]string{"foo": "bar"}, pb.Metadata)
+
+	require.Len(t, pb.Params, 4)
+	assert.Equal(t, params.NetDIP, pb.Params[0].Name)
+	assert.Equal(t, "216.58.201.174", pb.Params[0].GetStringValue())
+	assert.Equal(t, params.NetDIPNames, pb.Params[1].Name)
+	assert.Equal(t, []string{"dns.google.", "github.com."}, pb.Params[1].GetStringList().Values)
+	assert.Equal(t, params.NetDport, pb.Params[2].Name)
+	assert.Equal(t, uint64(443), pb.Params[2].GetUintValue())
+	assert.Equal(t, "port", pb.Params[2].Type)
+	assert.Equal(t, int64(-1), pb.Params[3].GetIntValue())
+
+	assert.Equal(t, "explorer.exe", pb.Ps.Parent.Name)
+	assert.Equal(t, "C:\\ProgramData", pb.Ps.Envs["ProgramData"])
+	require.Len(t, pb.Ps.Threads, 2)
+	assert.Equal(t, uint32(3453), pb.Ps.Threads[0].Tid)
+	require.Len(t, pb.Ps.Modules, 1)
+	assert.Equal(t, uint64(0x7ffb5c1d0000), pb.Ps.Modules[0].BaseAddress)
+	assert.Nil(t, pb.Ps.Pe)
+
+	require.Len(t, pb.Callstack, 1)
+	assert.Equal(t, "CreateFileW", pb.Callstack[0].Symbol)
+}
+
 func TestUnmarshalHugeHandles(t *testing.T) {
 	b, err := os.ReadFile("_fixtures\\handles.json")
 	require.NoError(t, err)
@@ -0,0 +1,590 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ocsf maps events to Open Cybersecurity Schema Framework classes.
+package ocsf
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/rabbitstack/fibratus/pkg/util/version"
+)
+
+// Version is the OCSF schema version the events conform to.
+const Version = "1.1.0"
+
+// Category identifiers.
+const (
+	CategoryUncategorized = 0
+	CategorySystem        = 1
+	CategoryNetwork       = 4
+)
+
+// Class identifiers.
+const (
+	ClassBase          = 0
+	ClassFileSystem    = 1001
+	ClassModule        = 1005
+	ClassProcess       = 1007
+	ClassNetwork       = 4001
+	ClassDNS           = 4003
+	ClassRegistryKey   = 201001
+	ClassRegistryValue = 201002
+)
+
+// ActivityOther designates the activity without the dedicated identifier.
+const ActivityOther = 99
+
+var categoryNames = map[int]string{
+	CategoryUncategorized: "Uncategorized",
+	CategorySystem:        "System Activity",
+	CategoryNetwork:       "Network Activity",
+}
+
+var classNames = map[int]string{
+	ClassBase:          "Base Event",
+	ClassFileSystem:    "File System Activity",
+	ClassModule:        "Module Activity",
+	ClassProcess:       "Process Activity",
+	ClassNetwork:       "Network Activity",
+	ClassDNS:           "DNS Activity",
+	ClassRegistryKey:   "Registry Key Activity",
+	ClassRegistryValue: "Registry Value Activity",
+}
+
+// activityNames contains activity names for each class.
+var activityNames = map[int]map[int]string{
+	ClassFileSystem: {
+		1: "Create", 2: "Read", 3: "Update", 4: "Delete", 5: "Rename", 6: "Set Attributes", 14: "Open",
+	},
+	ClassModule:        {1: "Load", 2: "Unload"},
+	ClassProcess:       {1: "Launch", 2: "Terminate", 3: "Open"},
+	ClassNetwork:       {1: "Open", 2: "Close", 6: "Traffic"},
+	ClassDNS:           {1: "Query", 2: "Response"},
+	ClassRegistryKey:   {1: "Create", 2: "Read", 4: "Delete"},
+	ClassRegistryValue: {1: "Get", 2: "Set", 4: "Delete"},
+}
+
+// severities maps rule severities to OCSF severity identifiers.
+var severities = map[string]int{
+	"low":      2,
+	"medium":   3,
+	"high":     4,
+	"critical": 5,
+}
+
+var severityNames = map[int]string{
+	1: "Informational",
+	2: "Low",
+	3: "Medium",
+	4: "High",
+	5: "Critical",
+}
+
+// Event is the OCSF event. Class-specific attributes are
+// omitted when they don't apply to the event class.
+type Event struct {
+	CategoryUID    int             `json:"category_uid"`
+	CategoryName   string          `json:"category_name"`
+	ClassUID       int             `json:"class_uid"`
+	ClassName      string          `json:"class_name"`
+	ActivityID     int             `json:"activity_id"`
+	ActivityName   string          `json:"activity_name"`
+	TypeUID        int             `json:"type_uid"`
+	TypeName       string          `json:"type_name"`
+	SeverityID     int             `json:"severity_id"`
+	Severity       string          `json:"severity"`
+	Time           int64           `json:"time"`
+	Message        string          `json:"message,omitempty"`
+	Metadata       Metadata        `json:"metadata"`
+	Device         Device          `json:"device"`
+	Actor          *Actor          `json:"actor,omitempty"`
+	Process        *Process        `json:"process,omitempty"`
+	File           *File           `json:"file,omitempty"`
+	Module         *Module         `json:"module,omitempty"`
+	SrcEndpoint    *Endpoint       `json:"src_endpoint,omitempty"`
+	DstEndpoint    *Endpoint       `json:"dst_endpoint,omitempty"`
+	ConnectionInfo *ConnectionInfo `json:"connection_info,omitempty"`
+	Traffic        *Traffic        `json:"traffic,omitempty"`
+	Query          *DNSQuery       `json:"query,omitempty"`
+	Answers        []DNSAnswer     `json:"answers,omitempty"`
+	Rcode          string          `json:"rcode,omitempty"`
+	RegKey         *RegKey         `json:"reg_key,omitempty"`
+	RegValue       *RegValue       `json:"reg_value,omitempty"`
+	Unmapped       Unmapped        `json:"unmapped"`
+}
+
+// Metadata describes the event origin.
+type Metadata struct {
+	Version string  `json:"version"`
+	Product Product `json:"product"`
+	UID     string  `json:"uid"`
+}
+
+// Product describes the product that generated the event.
+type Product struct {
+	Name       string `json:"name"`
+	VendorName string `json:"vendor_name"`
+	Version    string `json:"version"`
+}
+
+// Device describes the host where the event originated.
+type Device struct {
+	Hostname string `json:"hostname"`
+	OS       OS     `json:"os"`
+	TypeID   int    `json:"type_id"`
+}
+
+// OS describes the operating system.
+type OS struct {
+	Name   string `json:"name"`
+	TypeID int    `json:"type_id"`
+}
+
+// Actor describes the process that performed the activity.
+type Actor struct {
+	Process *Process `json:"process,omitempty"`
+	User    *User    `json:"user,omitempty"`
+}
+
+// Process is the OCSF process object.
+type Process struct {
+	PID           uint32   `json:"pid"`
+	Name          string   `json:"name,omitempty"`
+	CmdLine       string   `json:"cmd_line,omitempty"`
+	File          *File    `json:"file,omitempty"`
+	CreatedTime   int64    `json:"created_time,omitempty"`
+	TID           uint32   `json:"tid,omitempty"`
+	User          *User    `json:"user,omitempty"`
+	ParentProcess *Process `json:"parent_process,omitempty"`
+}
+
+// User is the OCSF user object.
+type User struct {
+	UID    string `json:"uid,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Domain string `json:"domain,omitempty"`
+}
+
+// File is the OCSF file object.
+type File struct {
+	Path         string `json:"path"`
+	Name         string `json:"name,omitempty"`
+	ParentFolder string `json:"parent_folder,omitempty"`
+	TypeID       int    `json:"type_id"`
+}
+
+// Module is the OCSF module object.
+type Module struct {
+	File        *File  `json:"file,omitempty"`
+	BaseAddress string `json:"base_address,omitempty"`
+}
+
+// Endpoint is the network endpoint.
+type Endpoint struct {
+	IP   string `json:"ip,omitempty"`
+	Port uint16 `json:"port,omitempty"`
+}
+
+// ConnectionInfo describes the network connection.
+type ConnectionInfo struct {
+	ProtocolName  string `json:"protocol_name,omitempty"`
+	ProtocolVerID int    `json:"protocol_ver_id,omitempty"`
+	DirectionID   int    `json:"direction_id"`
+	Direction     string `json:"direction,omitempty"`
+}
+
+// Traffic contains network traffic counters.
+type Traffic struct {
+	Bytes    uint32 `json:"bytes"`
+	BytesIn  uint32 `json:"bytes_in,omitempty"`
+	BytesOut uint32 `json:"bytes_out,omitempty"`
+}
+
+// DNSQuery is the DNS query object.
+type DNSQuery struct {
+	Hostname string `json:"hostname"`
+	Type     string `json:"type,omitempty"`
+}
+
+// DNSAnswer is the DNS answer object.
+type DNSAnswer struct {
+	Rdata string `json:"rdata"`
+}
+
+// RegKey is the Windows registry key object.
+type RegKey struct {
+	Path string `json:"path"`
+}
+
+// RegValue is the Windows registry value object.
+type RegValue struct {
+	Path string `json:"path"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+	Data string `json:"data,omitempty"`
+}
+
+// Unmapped contains event attributes without the OCSF counterpart.
+type Unmapped struct {
+	Name     string            `json:"name"`
+	Category string            `json:"category"`
+	CPU      uint8             `json:"cpu"`
+	Params   map[string]string `json:"params,omitempty"`
+	Meta     map[string]string `json:"metadata,omitempty"`
+	Rule     *Rule             `json:"rule,omitempty"`
+}
+
+// Rule describes the rule that matched the event.
+type Rule struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// Marshal serializes the event to the OCSF JSON event.
+func Marshal(evt *event.Event) ([]byte, error) {
+	return json.Marshal(New(evt))
+}
+
+// New maps the event to the OCSF event of the matching class.
+// Events that don't belong to any of the supported classes
+// are mapped to the base event class.
+func New(evt *event.Event) *Event {
+	class, activity := classify(evt)
+	category := CategoryUncategorized
+	switch class {
+	case ClassFileSystem, ClassModule, ClassProcess, ClassRegistryKey, ClassRegistryValue:
+		category = CategorySystem
+	case ClassNetwork, ClassDNS:
+		category = CategoryNetwork
+	}
+
+	activityName, ok := activityNames[class][activity]
+	if !ok {
+		activityName = "Other"
+		if class == ClassBase {
+			activityName = evt.Name
+		}
+	}
+
+	e := &Event{
+		CategoryUID:  category,
+		CategoryName: categoryNames[category],
+		ClassUID:     class,
+		ClassName:    classNames[class],
+		ActivityID:   activity,
+		ActivityName: activityName,
+		TypeUID:      class*100 + activity,
+		TypeName:     classNames[class] + ": " + activityName,
+		SeverityID:   1,
+		Time:         evt.Timestamp.UnixMilli(),
+		Message:      evt.Description,
+		Metadata: Metadata{
+			Version: Version,
+			Product: Product{Name: "Fibratus", VendorName: "Fibratus", Version: version.Get()},
+			UID:     strconv.FormatUint(evt.Seq, 10),
+		},
+		Device: Device{
+			Hostname: evt.Host,
+			OS:       OS{Name: "Windows", TypeID: 100},
+		},
+		Unmapped: Unmapped{
+			Name:     evt.Name,
+			Category: string(evt.Category),
+			CPU:      evt.CPU,
+		},
+	}
+
+	if rule := evt.GetMetaAsString(event.RuleNameKey); rule != "" {
+		sev := evt.GetMetaAsString(event.RuleSeverityKey)
+		e.Unmapped.Rule = &Rule{Name: rule, Severity: sev}
+		e.SeverityID = severities[strings.ToLower(sev)]
+		if e.SeverityID == 0 {
+			e.SeverityID = severities["low"]
+		}
+	}
+	e.Severity = severityNames[e.SeverityID]
+
+	if len(evt.Params) > 0 {
+		e.Unmapped.Params = make(map[string]string, len(evt.Params))
+		for name, par := range evt.Params {
+			e.Unmapped.Params[name] = par.String()
+		}
+	}
+	for k, v := range evt.Metadata {
+		if strings.HasPrefix(k.String(), "rule.") {
+			continue
+		}
+		if e.Unmapped.Meta == nil {
+			e.Unmapped.Meta = make(map[string]string)
+		}
+		e.Unmapped.Meta[k.String()] = fmt.Sprintf("%v", v)
+	}
+
+	e.Actor = actor(evt)
+
+	switch class {
+	case ClassProcess:
+		e.Process = process(evt)
+	case ClassFileSystem:
+		e.File = file(evt.GetParamAsString(params.FilePath))
+	case ClassModule:
+		e.Module = &Module{
+			File:        file(evt.GetParamAsString(params.ModulePath)),
+			BaseAddress: evt.GetParamAsString(params.ModuleBase),
+		}
+	case ClassNetwork:
+		network(evt, e)
+	case ClassDNS:
+		e.Query = &DNSQuery{
+			Hostname: evt.GetParamAsString(params.DNSName),
+			Type:     evt.GetParamAsString(params.DNSRR),
+		}
+		if evt.Type == event.ReplyDNS {
+			e.Rcode = evt.GetParamAsString(params.DNSRcode)
+			answers, _ := evt.Params.GetStringSlice(params.DNSAnswers)
+			for _, answer := range answers {
+				e.Answers = append(e.Answers, DNSAnswer{Rdata: answer})
+			}
+		}
+	case ClassRegistryKey:
+		e.RegKey = &RegKey{Path: evt.GetParamAsString(params.RegPath)}
+	case ClassRegistryValue:
+		path := evt.GetParamAsString(params.RegPath)
+		key, name := split(path)
+		e.RegValue = &RegValue{Path: key, Name: name}
+		if evt.Type == event.RegSetValue {
+			e.RegValue.Type = evt.GetParamAsString(params.RegValueType)
+			e.RegValue.Data = evt.GetParamAsString(params.RegData)
+		}
+	}
+
+	return e
+}
+
+// classify resolves the class and activity identifiers for the event.
+func classify(evt *event.Event) (int, int) {
+	switch evt.Type {
+	case event.CreateProcess:
+		return ClassProcess, 1
+	case event.TerminateProcess:
+		return ClassProcess, 2
+	case event.OpenProcess:
+		return ClassProcess, 3
+	case event.ProcessRundown:
+		return ClassProcess, ActivityOther
+
+	case event.CreateFile:
+		if _, err := evt.Params.GetUint32(params.FileOperation); err == nil && evt.IsCreateDisposition() {
+			return ClassFileSystem, 1
+		}
+		return ClassFileSystem, 14
+	case event.ReadFile:
+		return ClassFileSystem, 2
+	case event.WriteFile:
+		return ClassFileSystem, 3
+	case event.DeleteFile:
+		return ClassFileSystem, 4
+	case event.RenameFile:
+		return ClassFileSystem, 5
+	case event.SetFileInformation:
+		return ClassFileSystem, 6
+	case event.EnumDirectory, event.CloseFile, event.MapViewFile, event.UnmapViewFile:
+		return ClassFileSystem, ActivityOther
+
+	case event.LoadModule:
+		return ClassModule, 1
+	case event.UnloadModule:
+		return ClassModule, 2
+
+	case event.ConnectTCPv4, event.ConnectTCPv6, event.AcceptTCPv4, event.AcceptTCPv6,
+		event.ReconnectTCPv4, event.ReconnectTCPv6:
+		return ClassNetwork, 1
+	case event.DisconnectTCPv4, event.DisconnectTCPv6:
+		return ClassNetwork, 2
+	case event.SendTCPv4, event.SendTCPv6, event.SendUDPv4, event.SendUDPv6,
+		event.RecvTCPv4, event.RecvTCPv6, event.RecvUDPv4, event.RecvUDPv6,
+		event.RetransmitTCPv4, event.RetransmitTCPv6:
+		return ClassNetwork, 6
+
+	case event.QueryDNS:
+		return ClassDNS, 1
+	case event.ReplyDNS:
+		return ClassDNS, 2
+
+	case event.RegCreateKey:
+		return ClassRegistryKey, 1
+	case event.RegOpenKey, event.RegQueryKey:
+		return ClassRegistryKey, 2
+	case event.RegDeleteKey:
+		return ClassRegistryKey, 4
+	case event.RegQueryValue:
+		return ClassRegistryValue, 1
+	case event.RegSetValue:
+		return ClassRegistryValue, 2
+	case event.RegDeleteValue:
+		return ClassRegistryValue, 4
+	}
+	return ClassBase, ActivityOther
+}
+
+// actor maps the process that generated the event. For process
+// creation events, this is the parent of the spawned process.
+func actor(evt *event.Event) *Actor {
+	proc := fromPS(evt.PS)
+	if proc == nil {
+		proc = &Process{PID: evt.PID}
+	}
+	proc.TID = evt.Tid
+	a := &Actor{Process: proc}
+	if proc.User != nil {
+		a.User, proc.User = proc.User, nil
+	}
+	return a
+}
+
+// process maps the target process of the process activity.
+func process(evt *event.Event) *Process {
+	switch evt.Type {
+	case event.CreateProcess, event.OpenProcess:
+		proc := &Process{
+			PID:     evt.Params.TryGetUint32(params.ProcessID),
+			Name:    evt.GetParamAsString(params.ProcessName),
+			CmdLine: evt.GetParamAsString(params.Cmdline),
+			File:    file(evt.GetParamAsString(params.Exe)),
+			User: user(
+				evt.GetParamAsString(params.UserSID),
+				evt.GetParamAsString(params.Username),
+				evt.GetParamAsString(params.Domain),
+			),
+		}
+		if start, err := evt.Params.GetTime(params.StartTime); err == nil && !start.IsZero() {
+			proc.CreatedTime = start.UnixMilli()
+		}
+		if evt.Type == event.CreateProcess {
+			if ppid := evt.Params.TryGetUint32(params.ProcessParentID); ppid != 0 {
+				proc.ParentProcess = &Process{PID: ppid}
+			}
+		}
+		return proc
+	}
+	return fromPS(evt.PS)
+}
+
+// fromPS maps the process state to the OCSF process object.
+func fromPS(ps *pstypes.PS) *Process {
+	if ps == nil {
+		return nil
+	}
+	proc := &Process{
+		PID:     ps.PID,
+		Name:    ps.Name,
+		CmdLine: ps.Cmdline,
+		File:    file(ps.Exe),
+		User:    user(ps.SID, ps.Username, ps.Domain),
+	}
+	if !ps.StartTime.IsZero() {
+		proc.CreatedTime = ps.StartTime.UnixMilli()
+	}
+	if ps.Parent != nil {
+		proc.ParentProcess = &Process{
+			PID:     ps.Parent.PID,
+			Name:    ps.Parent.Name,
+			CmdLine: ps.Parent.Cmdline,
+			File:    file(ps.Parent.Exe),
+		}
+	} else if ps.Ppid != 0 {
+		proc.ParentProcess = &Process{PID: ps.Ppid}
+	}
+	return proc
+}
+
+func user(sid, name, domain string) *User {
+	if sid == "" && name == "" {
+		return nil
+	}
+	return &User{UID: sid, Name: name, Domain: domain}
+}
+
+// file maps the path to the OCSF file object of the regular file type.
+func file(path string) *File {
+	if path == "" {
+		return nil
+	}
+	dir, name := split(path)
+	return &File{Path: path, Name: name, ParentFolder: dir, TypeID: 1}
+}
+
+// network populates endpoints, connection info and traffic attributes.
+func network(evt *event.Event, e *Event) {
+	e.SrcEndpoint = &Endpoint{Port: evt.Params.TryGetUint16(params.NetSport)}
+	e.DstEndpoint = &Endpoint{Port: evt.Params.TryGetUint16(params.NetDport)}
+	e.ConnectionInfo = &ConnectionInfo{
+		ProtocolName: strings.ToLower(evt.GetParamAsString(params.NetL4Proto)),
+	}
+
+	var ip net.IP
+	if sip, err := evt.Params.GetIP(params.NetSIP); err == nil {
+		e.SrcEndpoint.IP = sip.String()
+		ip = sip
+	}
+	if dip, err := evt.Params.GetIP(params.NetDIP); err == nil {
+		e.DstEndpoint.IP = dip.String()
+		ip = dip
+	}
+	if ip != nil {
+		e.ConnectionInfo.ProtocolVerID = 6
+		if ip.To4() != nil {
+			e.ConnectionInfo.ProtocolVerID = 4
+		}
+	}
+
+	size := evt.Params.TryGetUint32(params.NetSize)
+	switch evt.Type {
+	case event.AcceptTCPv4, event.AcceptTCPv6, event.RecvTCPv4, event.RecvTCPv6, event.RecvUDPv4, event.RecvUDPv6:
+		e.ConnectionInfo.DirectionID, e.ConnectionInfo.Direction = 1, "Inbound"
+		if size > 0 {
+			e.Traffic = &Traffic{Bytes: size, BytesIn: size}
+		}
+	case event.ConnectTCPv4, event.ConnectTCPv6, event.SendTCPv4, event.SendTCPv6, event.SendUDPv4, event.SendUDPv6:
+		e.ConnectionInfo.DirectionID, e.ConnectionInfo.Direction = 2, "Outbound"
+		if size > 0 {
+			e.Traffic = &Traffic{Bytes: size, BytesOut: size}
+		}
+	default:
+		if size > 0 {
+			e.Traffic = &Traffic{Bytes: size}
+		}
+	}
+}
+
+// split splits the Windows path into the parent and the last component.
+func split(path string) (string, string) {
+	n := strings.LastIndexByte(path, '\\')
+	if n < 0 {
+		return "", path
+	}
+	return path[:n], path[n+1:]
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ocsf
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/rabbitstack/fibratus/pkg/event/eventtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestMarshal(t *testing.T) {
+	for _, f := range eventtest.Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			b, err := Marshal(f.Event)
+			require.NoError(t, err)
+			eventtest.Golden(t, f.Name, b, *update)
+		})
+	}
+}
+
+func TestMarshalFields(t *testing.T) {
+	// class and type identifiers as defined by the OCSF schema.
+	// The type identifier is class_uid * 100 + activity_id
+	var tests = map[string]map[string]any{
+		"create-process": {
+			"class_uid":         float64(1007),
+			"category_uid":      float64(1),
+			"activity_id":       float64(1),
+			"type_uid":          float64(100701),
+			"severity_id":       float64(4),
+			"process.pid":       float64(5120),
+			"actor.process.pid": float64(2484),
+		},
+		"create-file": {
+			"class_uid":         float64(1001),
+			"category_uid":      float64(1),
+			"activity_id":       float64(1),
+			"type_uid":          float64(100101),
+			"file.path":         "C:\\Users\\admin\\Desktop\\Report.DOCX",
+			"actor.process.pid": float64(2484),
+		},
+		"set-value": {
+			"class_uid":    float64(201002),
+			"category_uid": float64(1),
+			"activity_id":  float64(2),
+			"type_uid":     float64(20100202),
+		},
+		"connect": {
+			"class_uid":         float64(4001),
+			"category_uid":      float64(4),
+			"activity_id":       float64(1),
+			"type_uid":          float64(400101),
+			"dst_endpoint.ip":   "140.82.121.4",
+			"dst_endpoint.port": float64(443),
+		},
+		"reply-dns": {
+			"class_uid":      float64(4003),
+			"category_uid":   float64(4),
+			"activity_id":    float64(2),
+			"type_uid":       float64(400302),
+			"query.hostname": "github.com",
+		},
+		"load-module": {
+			"class_uid":        float64(1005),
+			"category_uid":     float64(1),
+			"activity_id":      float64(1),
+			"type_uid":         float64(100501),
+			"module.file.path": "C:\\Windows\\System32\\user32.dll",
+		},
+		"virtual-alloc": {
+			"class_uid":   float64(0),
+			"activity_id": float64(99),
+			"type_uid":    float64(99),
+			"severity_id": float64(1),
+		},
+	}
+
+	for _, f := range eventtest.Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			require.Contains(t, tests, f.Name)
+			b, err := Marshal(f.Event)
+			require.NoError(t, err)
+			for path, want := range tests[f.Name] {
+				assert.Equal(t, want, eventtest.Field(t, b, path), path)
+			}
+		})
+	}
+}
@@ -136,6 +136,32 @@ func (t Type) String() string {
 		return "ipv6"
 	case IPv4:
 		return "ipv4"
+	case Float:
+		return "float"
+	case Double:
+		return "double"
+	case Bool:
+		return "bool"
+	case Binary:
+		return "binary"
+	case Time:
+		return "time"
+	case Slice:
+		return "slice"
+	case Enum:
+		return "enum"
+	case DOSPath, Path:
+		return "path"
+	case Status:
+		return "status"
+	case Key:
+		return "key"
+	case Flags, Flags64:
+		return "flags"
+	case Address:
+		return "address"
+	case HandleType:
+		return "handle_type"
 	default:
 		return "unknown"
 	}
@@ -507,23 +507,25 @@ var keywords = []string{"", "", "severity", "tags"}
 func (f *filament) emitAlertFn(_, args cpython.PyArgs, kwargs cpython.PyKwargs) cpython.PyRawObject {
 	f.gil.Lock()
 	defer f.gil.Unlock()
-	senders := alertsender.FindAll()
+	title, text, sever, tags := cpython.PyArgsParseKeywords(args, kwargs, keywords)
+
+	alert := alertsender.NewAlert(
+		title,
+		text,
+		tags,
+		alertsender.ParseSeverityFromString(sever),
+	)
+	alertsender.Record(alert)
+
+	senders := alert.Route()
 	if len(senders) == 0 {
-		log.Warn("no alertsenders registered. Alert won't be sent")
+		log.Warn("no alertsenders registered or routed. Alert won't be sent")
 		return cpython.NewPyNone()
 	}
 
-	title, text, sever, tags := cpython.PyArgsParseKeywords(args, kwargs, keywords)
-
 	for _, s := range senders {
-		alert := alertsender.NewAlert(
-			title,
-			text,
-			tags,
-			alertsender.ParseSeverityFromString(sever),
-		)
 		if err := s.Send(alert); err != nil {
-			log.Warnf("unable to emit alert from filament: %v", err)
+			log.Warnf("unable to emit alert from filament via [%s] sender: %v", s.Name, err)
 		}
 	}
 
@@ -446,17 +446,6 @@ func InterpolateFields(s string, evts []*event.Event) string {
 		return s
 	}
 
-	split := func(s string) (string, string) {
-		n, m := strings.Index(s, "["), strings.Index(s, "]")
-		if n < 0 || m < 0 {
-			return s, ""
-		}
-		if n > m {
-			return s, ""
-		}
-		return s[0:n], s[n+1 : m]
-	}
-
 	for _, m := range matches {
 		switch {
 		case len(m) == 3:
@@ -473,21 +462,8 @@ func InterpolateFields(s string, evts []*event.Event) string {
 			if i-1 > len(evts)-1 {
 				continue
 			}
-			evt := evts[i-1]
 			// extract field value from the event and replace in string
-			var val any
-			for _, accessor := range GetAccessors() {
-				name, arg := split(m[2])
-				f := Field{Value: m[2], Name: fields.Field(name), Arg: arg}
-				var err error
-				val, err = accessor.Get(f, evt)
-				if err != nil {
-					continue
-				}
-				if val != nil {
-					break
-				}
-			}
+			val := GetFieldValue(m[2], evts[i-1])
 			if val != nil {
 				r = strings.ReplaceAll(r, m[0], fmt.Sprintf("%v", val))
 			} else {
@@ -500,6 +476,29 @@ func InterpolateFields(s string, evts []*event.Event) string {
 	return r
 }
 
+// GetFieldValue extracts the value of the field from the event. The
+// field name may contain the argument enclosed in square brackets,
+// e.g. ps.envs[ALLUSERSPROFILE]. If the field can't be resolved by
+// any of the accessors, nil is returned.
+func GetFieldValue(name string, evt *event.Event) any {
+	n, arg := name, ""
+	i, j := strings.Index(name, "["), strings.Index(name, "]")
+	if i >= 0 && j >= 0 && i < j {
+		n, arg = name[0:i], name[i+1:j]
+	}
+	f := Field{Value: name, Name: fields.Field(n), Arg: arg}
+	for _, accessor := range GetAccessors() {
+		val, err := accessor.Get(f, evt)
+		if err != nil {
+			continue
+		}
+		if val != nil {
+			return val
+		}
+	}
+	return nil
+}
+
 // mapValuer for each field present in the AST, we run the
 // accessors and extract the field values that are supplied
 // to the valuer. The valuer feeds the expression with correct
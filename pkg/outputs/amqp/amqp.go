@@ -33,7 +33,8 @@ var (
 )
 
 type rabbitmq struct {
-	client *client
+	client     *client
+	serializer outputs.Serializer
 }
 
 func init() {
@@ -46,7 +47,11 @@ func initAMQP(config outputs.Config) (outputs.OutputGroup, error) {
 		return outputs.Fail(outputs.ErrInvalidConfig(outputs.AMQP, config.Output))
 	}
 
-	q := &rabbitmq{client: newClient(cfg)}
+	if err := cfg.Serializer.Validate(outputs.AMQP); err != nil {
+		return outputs.Fail(err)
+	}
+
+	q := &rabbitmq{client: newClient(cfg), serializer: cfg.Serializer}
 
 	return outputs.Success(q), nil
 }
@@ -67,9 +72,13 @@ func (q *rabbitmq) Close() error {
 }
 
 func (q *rabbitmq) Publish(batch *event.Batch) error {
-	body := batch.MarshalJSON()
+	body, err := q.serializer.MarshalBatch(batch)
+	if err != nil {
+		amqpErrors.Add(1)
+		return err
+	}
 
-	err := q.client.publish(body)
+	err = q.client.publish(body)
 	if err != nil {
 		amqpErrors.Add(1)
 		return err
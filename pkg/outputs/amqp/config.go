@@ -38,6 +38,7 @@ const (
 	amqpDeliveryMode = "output.amqp.delivery-mode"
 	amqpUsername     = "output.amqp.username"
 	amqpPassword     = "output.amqp.password"
+	amqpSerializer   = "output.amqp.serializer"
 )
 
 // Config contains the tweaks that influence the behaviour of the AMQP output.
@@ -69,6 +70,8 @@ type Config struct {
 	Vhost string `mapstructure:"vhost"`
 	// Headers contains a list of headers that are added to AMQP message
 	Headers map[string]string `mapstructure:"headers"`
+	// Serializer indicates the serializer for the message body.
+	Serializer outputs.Serializer `mapstructure:"serializer"`
 }
 
 // AddFlags registers persistent flags.
@@ -85,6 +88,7 @@ func AddFlags(flags *pflag.FlagSet) {
 	flags.String(amqpDeliveryMode, "transient", "Determines if a published message is persistent or transient")
 	flags.String(amqpUsername, "", "The username for the plain authentication method")
 	flags.String(amqpPassword, "", "The password for the plain authentication method")
+	flags.String(amqpSerializer, string(outputs.JSON), "Indicates the event serializer type")
 	outputs.AddTLSFlags(flags, outputs.AMQP)
 }
 
@@ -107,6 +111,13 @@ func (c Config) deliveryMode() uint8 {
 	}
 }
 
+func (c Config) contentType() string {
+	if c.Serializer.IsBinary() {
+		return c.Serializer.ContentType()
+	}
+	return "text/json"
+}
+
 func (c Config) auth() []amqp.Authentication {
 	if c.Username == "" && c.Password == "" {
 		return nil
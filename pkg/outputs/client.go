@@ -19,9 +19,48 @@
 package outputs
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/rabbitstack/fibratus/pkg/event"
 )
 
+// ErrConnectionLost signals the client lost the connection to the remote endpoint.
+// Clients wrap publish errors with this error to instruct the aggregator worker to
+// reconnect the client and publish the batch again.
+var ErrConnectionLost = errors.New("connection lost")
+
+// PermanentError signals the batch was rejected by the output endpoint,
+// for example, due to a malformed or oversized payload. Publishing
+// the same batch again can't succeed.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent wraps the error to signal the batch shouldn't be published again.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent determines if the publish error is non-retryable.
+func IsPermanent(err error) bool {
+	var e *PermanentError
+	return errors.As(err, &e)
+}
+
+// IsPermanentStatus determines if the HTTP status code returned by
+// the output endpoint designates the rejected batch. Client errors
+// are permanent except timeouts and rate limiting.
+func IsPermanentStatus(code int) bool {
+	return code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
+
 // Client represents the minimal interface all output implementors have to satisfy.
 type Client interface {
 	Close() error
@@ -27,6 +27,13 @@ import (
 type Config struct {
 	Type   Type
 	Output interface{}
+	// Filter is the filter expression that selects the
+	// events forwarded to the output. If empty, all events
+	// are forwarded to the output.
+	Filter string
+	// Sampling is the policy that samples and rate
+	// limits the events forwarded to the output.
+	Sampling Sampling
 }
 
 // TLSConfig stores the client TLS parameters.
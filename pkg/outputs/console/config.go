@@ -18,7 +18,10 @@
 
 package console
 
-import "github.com/spf13/pflag"
+import (
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/spf13/pflag"
+)
 
 const (
 	frmt             = "output.console.format"
@@ -26,15 +29,17 @@ const (
 	paramKVDelimiter = "output.console.kv-delimiter"
 	enabled          = "output.console.enabled"
 	colorize         = "output.console.colorize"
+	serializer       = "output.console.serializer"
 )
 
 // Config contains the tweaks that influence the behaviour of the console output.
 type Config struct {
-	Format           string `mapstructure:"format"`
-	Template         string `mapstructure:"template"`
-	ParamKVDelimiter string `mapstructure:"kv-delimiter"`
-	Enabled          bool   `mapstructure:"enabled"`
-	Colorize         bool   `mapstructure:"colorize"`
+	Format           string             `mapstructure:"format"`
+	Template         string             `mapstructure:"template"`
+	ParamKVDelimiter string             `mapstructure:"kv-delimiter"`
+	Enabled          bool               `mapstructure:"enabled"`
+	Colorize         bool               `mapstructure:"colorize"`
+	Serializer       outputs.Serializer `mapstructure:"serializer"`
 }
 
 // AddFlags registers persistent flags.
@@ -44,4 +49,5 @@ func AddFlags(flags *pflag.FlagSet) {
 	flags.String(tmpl, "", "Event formatting template")
 	flags.Bool(enabled, true, "Indicates if the console output is enabled")
 	flags.Bool(colorize, true, "Indicates if the console output is colorized")
+	flags.String(serializer, string(outputs.JSON), "Indicates the event serializer type in json format")
 }
@@ -45,6 +45,7 @@ type console struct {
 	formatter      *event.Formatter
 	colorFormatter *event.ColorFormatter
 	format         format
+	serializer     outputs.Serializer
 }
 
 func init() {
@@ -57,6 +58,9 @@ func initConsole(config outputs.Config) (outputs.OutputGroup, error) {
 	if !ok {
 		return outputs.Fail(outputs.ErrInvalidConfig(outputs.Console, config.Output))
 	}
+	if err := cfg.Serializer.Validate(outputs.Console); err != nil {
+		return outputs.Fail(err)
+	}
 	tmpl := cfg.Template
 	if tmpl == "" {
 		tmpl = template
@@ -72,9 +76,10 @@ func initConsole(config outputs.Config) (outputs.OutputGroup, error) {
 	}
 
 	c := &console{
-		writer:    bufio.NewWriterSize(stdout, 8*1024),
-		formatter: formatter,
-		format:    format(cfg.Format),
+		writer:     bufio.NewWriterSize(stdout, 8*1024),
+		formatter:  formatter,
+		format:     format(cfg.Format),
+		serializer: cfg.Serializer,
 	}
 
 	if cfg.Colorize {
@@ -91,7 +96,12 @@ func (c *console) Publish(batch *event.Batch) error {
 		var buf []byte
 		switch c.format {
 		case json:
-			buf = evt.MarshalJSON()
+			var err error
+			buf, err = c.serializer.Marshal(evt)
+			if err != nil {
+				consoleErrors.Add(1)
+				continue
+			}
 		case pretty:
 			if c.colorFormatter != nil {
 				buf = c.colorFormatter.Format(evt)
@@ -19,8 +19,11 @@
 package elasticsearch
 
 import (
+	"fmt"
 	"github.com/rabbitstack/fibratus/pkg/outputs"
 	"github.com/spf13/pflag"
+	"path"
+	"strings"
 	"time"
 )
 
@@ -41,6 +44,12 @@ const (
 	esTemplateName        = "output.elasticsearch.template-name"
 	esTemplateConfig      = "output.elasticsearch.template-config"
 	esGzipCompression     = "output.elasticsearch.gzip-compression"
+	esSerializer          = "output.elasticsearch.serializer"
+	esDataStream          = "output.elasticsearch.data-stream"
+	esDataStreamNamespace = "output.elasticsearch.data-stream-namespace"
+	esILMPolicy           = "output.elasticsearch.ilm-policy"
+	esILMPolicyConfig     = "output.elasticsearch.ilm-policy-config"
+	esDeadLetterIndex     = "output.elasticsearch.dead-letter-index"
 )
 
 // Config contains the options for tweaking the output behaviour.
@@ -78,6 +87,20 @@ type Config struct {
 	TemplateConfig string `mapstructure:"template-config"`
 	// GzipCompression specifies if gzip compression is enabled.
 	GzipCompression bool `mapstructure:"gzip-compression"`
+	// Serializer indicates the serializer for the indexed documents.
+	Serializer outputs.Serializer `mapstructure:"serializer"`
+	// DataStream determines if events are indexed into data streams named after the logs-fibratus.<category>-<namespace> scheme.
+	DataStream bool `mapstructure:"data-stream"`
+	// DataStreamNamespace specifies the namespace segment of the data stream name.
+	DataStreamNamespace string `mapstructure:"data-stream-namespace"`
+	// Routing maps event categories to data stream datasets. Categories without the route are indexed into the fibratus.<category> dataset.
+	Routing map[string]string `mapstructure:"routing"`
+	// ILMPolicy specifies the name of the index lifecycle management policy attached to data streams.
+	ILMPolicy string `mapstructure:"ilm-policy"`
+	// ILMPolicyConfig contains the full JSON body of the index lifecycle management policy.
+	ILMPolicyConfig string `mapstructure:"ilm-policy-config"`
+	// DeadLetterIndex specifies the index that receives documents rejected by Elasticsearch.
+	DeadLetterIndex string `mapstructure:"dead-letter-index"`
 }
 
 // AddFlags registers persistent flags.
@@ -98,4 +121,49 @@ func AddFlags(flags *pflag.FlagSet) {
 	flags.String(esIndexName, "fibratus", "Represents the target index for kernel events. It allows time specifiers to create indices per time frame")
 	flags.String(esTemplateConfig, "", "Contains the full JSON body of the index template")
 	flags.Bool(esGzipCompression, false, "Specifies if gzip compression is enabled")
+	flags.String(esSerializer, string(outputs.JSON), "Indicates the event serializer type")
+	flags.Bool(esDataStream, false, "Determines if events are indexed into data streams named after the logs-fibratus.<category>-<namespace> scheme")
+	flags.String(esDataStreamNamespace, "default", "Specifies the namespace segment of the data stream name")
+	flags.String(esILMPolicy, "fibratus", "Specifies the name of the index lifecycle management policy attached to data streams")
+	flags.String(esILMPolicyConfig, "", "Contains the full JSON body of the index lifecycle management policy")
+	flags.String(esDeadLetterIndex, "fibratus-dead-letter", "Specifies the index that receives documents rejected by Elasticsearch")
+}
+
+// builtinDataStreamPatterns are the index patterns of the built-in Elasticsearch
+// templates that create data streams. Data streams only accept create operations.
+var builtinDataStreamPatterns = []string{"logs-*-*", "metrics-*-*", "synthetics-*-*", "traces-*-*"}
+
+// validate ensures the data stream name segments are valid, and the
+// dead-letter index doesn't resolve to the data stream.
+func (c Config) validate() error {
+	if !c.DataStream {
+		return nil
+	}
+	if err := validateDataStreamSegment("namespace", c.DataStreamNamespace); err != nil {
+		return err
+	}
+	for category, dataset := range c.Routing {
+		if err := validateDataStreamSegment(fmt.Sprintf("%s category dataset", category), dataset); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range builtinDataStreamPatterns {
+		if ok, _ := path.Match(pattern, c.DeadLetterIndex); ok {
+			return fmt.Errorf("dead-letter index %q matches the %s data stream pattern. Choose the regular index name", c.DeadLetterIndex, pattern)
+		}
+	}
+	return nil
+}
+
+// validateDataStreamSegment checks the dataset or namespace conforms to data
+// stream naming restrictions. Segments can't be empty, must be lowercase, and
+// can't contain hyphens since they delimit the type, dataset, and namespace.
+func validateDataStreamSegment(name, s string) error {
+	if s == "" {
+		return fmt.Errorf("data stream %s can't be empty", name)
+	}
+	if strings.ToLower(s) != s || strings.ContainsAny(s, "-\\/*?\"<>| ,#:") {
+		return fmt.Errorf("invalid data stream %s %q: only lowercase characters without hyphens are allowed", name, s)
+	}
+	return nil
 }
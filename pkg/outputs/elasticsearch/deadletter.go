@@ -0,0 +1,145 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	log "github.com/sirupsen/logrus"
+)
+
+// deadLetterTimeout bounds the dead-letter bulk request when the output timeout is not set
+const deadLetterTimeout = time.Second * 5
+
+// deadLetter is the document indexed into the dead-letter index
+// when Elasticsearch rejects the event document.
+type deadLetter struct {
+	Timestamp time.Time `json:"@timestamp"`
+	// Index is the index or data stream the document was destined to.
+	Index string `json:"index"`
+	// Status is the HTTP status code of the failed bulk item.
+	Status int `json:"status"`
+	// Error contains the failure type and reason.
+	Error deadLetterError `json:"error"`
+	// Document is the original document. It is stored as a string
+	// to prevent the mapping conflicts that caused the rejection.
+	Document string `json:"document"`
+}
+
+type deadLetterError struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// afterBulk inspects the bulk response for per-item failures. Rejected
+// documents are routed to the dead-letter index if it is configured.
+func (e *elasticsearch) afterBulk(executionID int64, requests []elastic.BulkableRequest, response *elastic.BulkResponse, err error) {
+	if err != nil {
+		failedDocs.Add(int64(len(requests)))
+		log.Errorf("failed to execute bulk: %s", err)
+		return
+	}
+	if !response.Errors {
+		committedDocs.Add(int64(len(requests)))
+		return
+	}
+
+	letters := make([]elastic.BulkableRequest, 0)
+	// bulk response items are in the same
+	// order as the requests in the bulk
+	for i, item := range response.Items {
+		if i >= len(requests) {
+			break
+		}
+		for _, res := range item {
+			if res.Error == nil && res.Status < 300 {
+				committedDocs.Add(1)
+				continue
+			}
+			failedDocs.Add(1)
+			var reason deadLetterError
+			if res.Error != nil {
+				reason = deadLetterError{Type: res.Error.Type, Reason: res.Error.Reason}
+			}
+			log.Errorf("failed to insert document into %s index: %s: %s", res.Index, reason.Type, reason.Reason)
+			if req := e.newDeadLetterRequest(requests[i], res, reason); req != nil {
+				letters = append(letters, req)
+			}
+		}
+	}
+
+	if len(letters) == 0 {
+		return
+	}
+	// the dead-letter bulk runs in the bulk worker, so it must not
+	// hold the worker indefinitely while the cluster is unresponsive
+	timeout := e.config.Timeout
+	if timeout <= 0 {
+		timeout = deadLetterTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	resp, err := e.client.Bulk().Add(letters...).Do(ctx)
+	if err != nil {
+		log.Errorf("failed to index %d documents into dead-letter index: %v", len(letters), err)
+		return
+	}
+	deadLetterDocs.Add(int64(len(letters) - len(resp.Failed())))
+	if resp.Errors {
+		log.Errorf("failed to index %d documents into dead-letter index", len(resp.Failed()))
+	}
+}
+
+// newDeadLetterRequest creates the index request for the rejected document.
+// It returns nil if the dead-letter index is not configured or the rejected
+// document is already the dead letter.
+func (e *elasticsearch) newDeadLetterRequest(req elastic.BulkableRequest, res *elastic.BulkResponseItem, reason deadLetterError) elastic.BulkableRequest {
+	if e.config.DeadLetterIndex == "" || e.client == nil {
+		return nil
+	}
+	source, err := req.Source()
+	if err != nil || len(source) < 2 {
+		return nil
+	}
+	index := res.Index
+	var action map[string]struct {
+		Index string `json:"_index"`
+	}
+	if err := json.Unmarshal([]byte(source[0]), &action); err == nil {
+		for _, meta := range action {
+			if meta.Index != "" {
+				index = meta.Index
+			}
+		}
+	}
+	if index == e.config.DeadLetterIndex {
+		return nil
+	}
+	doc := deadLetter{
+		Timestamp: time.Now().UTC(),
+		Index:     index,
+		Status:    res.Status,
+		Error:     reason,
+		Document:  source[1],
+	}
+	return elastic.NewBulkIndexRequest().Index(e.config.DeadLetterIndex).Doc(doc)
+}
@@ -19,6 +19,7 @@
 package elasticsearch
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"expvar"
@@ -30,11 +31,15 @@ import (
 	"github.com/rabbitstack/fibratus/pkg/util/tls"
 	log "github.com/sirupsen/logrus"
 	"net/http"
+	"time"
 )
 
 // minElasticVersion is the minimal supported Elasticsearch version
 var minElasticVersion, _ = version.NewVersion("5.5")
 
+// minDataStreamVersion is the minimal Elasticsearch version that supports data streams
+var minDataStreamVersion, _ = version.NewVersion("7.9")
+
 var (
 	// totalBulkedDocs contains the number of total bulked docs
 	totalBulkedDocs = expvar.NewInt("elasticsearch.total.bulked.docs")
@@ -42,6 +47,8 @@ var (
 	committedDocs = expvar.NewInt("elasticsearch.committed.docs")
 	// failedDocs counts the number of docs that failed to commit to Elasticsearch
 	failedDocs = expvar.NewInt("elasticsearch.failed.docs")
+	// deadLetterDocs counts the number of rejected docs indexed into the dead-letter index
+	deadLetterDocs = expvar.NewInt("elasticsearch.dead.letter.docs")
 )
 
 type elasticsearch struct {
@@ -67,6 +74,13 @@ func initElastic(config outputs.Config) (outputs.OutputGroup, error) {
 		return outputs.Fail(outputs.ErrInvalidConfig(outputs.Elasticsearch, config.Output))
 	}
 
+	if err := cfg.Serializer.Validate(outputs.Elasticsearch); err != nil {
+		return outputs.Fail(err)
+	}
+	if err := cfg.validate(); err != nil {
+		return outputs.Fail(err)
+	}
+
 	es := &elasticsearch{config: cfg, index: index{config: cfg}}
 
 	return outputs.Success(es), nil
@@ -125,27 +139,15 @@ func (e *elasticsearch) Connect() error {
 	if v.LessThan(minElasticVersion) {
 		return fmt.Errorf("required at least Elasticsearch %s but found version %s", minElasticVersion.String(), ver)
 	}
+	if e.config.DataStream && v.LessThan(minDataStreamVersion) {
+		return fmt.Errorf("data streams require at least Elasticsearch %s but found version %s", minDataStreamVersion.String(), ver)
+	}
 
 	e.client = client
 	e.index.client = client
 
 	bulkProcessor, err := client.BulkProcessor().
-		After(func(executionId int64, requests []elastic.BulkableRequest, response *elastic.BulkResponse, err error) {
-			if err != nil {
-				log.Errorf("failed to execute bulk: %s", err)
-				return
-			}
-
-			if response.Errors {
-				log.Errorf("failed to insert %d documents", len(response.Failed()))
-				for i, fail := range response.Failed() {
-					failedDocs.Add(1)
-					log.Errorf("failed to insert document %d: %v", i, fail.Error)
-				}
-				return
-			}
-			committedDocs.Add(int64(len(requests)))
-		}).
+		After(e.afterBulk).
 		FlushInterval(e.config.FlushPeriod).
 		Workers(e.config.BulkWorkers).
 		Do(context.Background())
@@ -176,15 +178,47 @@ func (e *elasticsearch) Publish(batch *event.Batch) error {
 		// create the bulk index request for each event in the batch.
 		// We already have a valid JSON body, so just pass the raw
 		// JSON message as request document
-		e.bulkProcessor.Add(newBulkIndexRequest(indexName, evt))
+		req, err := newBulkIndexRequest(indexName, evt, e.config.Serializer, e.config.DataStream)
+		if err != nil {
+			return err
+		}
+		e.bulkProcessor.Add(req)
 		totalBulkedDocs.Add(1)
 	}
 	return nil
 }
 
-func newBulkIndexRequest(indexName string, evt *event.Event) *elastic.BulkIndexRequest {
-	kjson := evt.MarshalJSON()
-	return elastic.NewBulkIndexRequest().Index(indexName).Doc(json.RawMessage(kjson))
+// newBulkIndexRequest creates the bulk request for the event. Data streams
+// only accept the create operation and require the @timestamp field, which
+// is injected into documents produced by serializers that lack it.
+func newBulkIndexRequest(indexName string, evt *event.Event, serializer outputs.Serializer, dataStream bool) (*elastic.BulkIndexRequest, error) {
+	kjson, err := serializer.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+	req := elastic.NewBulkIndexRequest().Index(indexName)
+	if dataStream {
+		if serializer != outputs.ECS {
+			kjson = withTimestamp(kjson, evt.Timestamp)
+		}
+		req.OpType("create")
+	}
+	return req.Doc(json.RawMessage(kjson)), nil
+}
+
+// withTimestamp prepends the @timestamp field to the JSON document.
+func withTimestamp(doc []byte, ts time.Time) []byte {
+	if len(doc) < 2 || doc[0] != '{' {
+		return doc
+	}
+	b := make([]byte, 0, len(doc)+48)
+	b = append(b, `{"@timestamp":"`...)
+	b = ts.UTC().AppendFormat(b, time.RFC3339Nano)
+	b = append(b, '"')
+	if rest := bytes.TrimSpace(doc[1:]); len(rest) > 0 && rest[0] != '}' {
+		b = append(b, ',')
+	}
+	return append(b, doc[1:]...)
 }
 
 func (e *elasticsearch) Close() error {
@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -157,6 +158,167 @@ func TestElasticsearchPublish(t *testing.T) {
 	assert.Equal(t, int64(0), failedDocs.Value())
 }
 
+func TestElasticsearchDataStreams(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+		policy   []byte
+		tmpl     []byte
+		bulk     []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.URL.Path == "/_ilm/policy/fibratus" && r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"type":"resource_not_found_exception","reason":"Lifecycle policy not found: fibratus"},"status":404}`))
+		case r.URL.Path == "/_ilm/policy/fibratus" && r.Method == http.MethodPut:
+			policy = body
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		case r.URL.Path == "/_index_template/fibratus" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/_index_template/fibratus" && r.Method == http.MethodPut:
+			tmpl = body
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		case strings.Contains(r.URL.Path, "_bulk"):
+			bulk = body
+			resp, _ := json.Marshal(&elastic.BulkResponse{Took: 1, Items: []map[string]*elastic.BulkResponseItem{}})
+			_, _ = w.Write(resp)
+		default:
+			ping := elastic.PingResult{Name: "es"}
+			ping.Version.Number = "8.11.0"
+			resp, _ := json.Marshal(&ping)
+			_, _ = w.Write(resp)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := Config{
+		Servers:             []string{srv.URL},
+		FlushPeriod:         time.Millisecond * 250,
+		TemplateName:        "fibratus",
+		DataStream:          true,
+		DataStreamNamespace: "prod",
+		Routing:             map[string]string{"file": "windows.file"},
+		ILMPolicy:           "fibratus",
+	}
+
+	es := &elasticsearch{
+		config: cfg,
+		index:  index{config: cfg},
+	}
+
+	require.NoError(t, es.Connect())
+	require.NoError(t, es.Publish(getBatch()))
+	require.NoError(t, es.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Contains(t, requests, "PUT /_ilm/policy/fibratus")
+	assert.Contains(t, requests, "PUT /_index_template/fibratus")
+	assert.True(t, bytes.Contains(policy, []byte("rollover")))
+
+	var template struct {
+		IndexPatterns []string       `json:"index_patterns"`
+		DataStream    map[string]any `json:"data_stream"`
+		Template      struct {
+			Settings struct {
+				Index struct {
+					Lifecycle struct {
+						Name string `json:"name"`
+					} `json:"lifecycle"`
+				} `json:"index"`
+			} `json:"settings"`
+		} `json:"template"`
+	}
+	require.NoError(t, json.Unmarshal(tmpl, &template))
+	assert.Equal(t, []string{"logs-fibratus.*-*", "logs-windows.file-*"}, template.IndexPatterns)
+	assert.NotNil(t, template.DataStream)
+	assert.Equal(t, "fibratus", template.Template.Settings.Index.Lifecycle.Name)
+
+	assert.True(t, bytes.Contains(bulk, []byte(`{"create":{"_index":"logs-windows.file-prod"}}`)))
+	assert.True(t, bytes.Contains(bulk, []byte(`{"@timestamp":"2018-05-03T15:04:05.323Z",`)))
+}
+
+func TestElasticsearchDeadLetter(t *testing.T) {
+	letters := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "_bulk") {
+			ping := elastic.PingResult{Name: "es"}
+			ping.Version.Number = "7.10.2"
+			resp, _ := json.Marshal(&ping)
+			_, _ = w.Write(resp)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response := elastic.BulkResponse{Took: 1}
+		if bytes.Contains(body, []byte("fibratus-dead-letter")) {
+			letters <- body
+			response.Items = []map[string]*elastic.BulkResponseItem{{"index": {Index: "fibratus-dead-letter", Status: 201}}}
+		} else {
+			response.Errors = true
+			response.Items = []map[string]*elastic.BulkResponseItem{
+				{"index": {Index: "fibratus", Status: 400, Error: &elastic.ErrorDetails{Type: "mapper_parsing_exception", Reason: "failed to parse field [params.file_object]"}}},
+				{"index": {Index: "fibratus", Status: 201}},
+				{"index": {Index: "fibratus", Status: 201}},
+			}
+		}
+		resp, _ := json.Marshal(&response)
+		_, _ = w.Write(resp)
+	}))
+	defer srv.Close()
+
+	cfg := Config{
+		Servers:         []string{srv.URL},
+		FlushPeriod:     time.Millisecond * 250,
+		IndexName:       "fibratus",
+		DeadLetterIndex: "fibratus-dead-letter",
+	}
+
+	es := &elasticsearch{
+		config: cfg,
+		index:  index{config: cfg},
+	}
+
+	failed, dead := failedDocs.Value(), deadLetterDocs.Value()
+
+	require.NoError(t, es.Connect())
+	require.NoError(t, es.Publish(getBatch()))
+
+	select {
+	case body := <-letters:
+		lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+		require.Len(t, lines, 2)
+		assert.Equal(t, `{"index":{"_index":"fibratus-dead-letter"}}`, string(lines[0]))
+		var letter deadLetter
+		require.NoError(t, json.Unmarshal(lines[1], &letter))
+		assert.Equal(t, "fibratus", letter.Index)
+		assert.Equal(t, 400, letter.Status)
+		assert.Equal(t, "mapper_parsing_exception", letter.Error.Type)
+		assert.Contains(t, letter.Document, `"seq":2`)
+	case <-time.After(time.Second * 5):
+		t.Fatal("dead letter not indexed")
+	}
+
+	require.NoError(t, es.Close())
+
+	assert.Equal(t, failed+1, failedDocs.Value())
+	assert.Equal(t, dead+1, deadLetterDocs.Value())
+}
+
 func getBatch() *event.Batch {
 	ts, _ := time.Parse(time.RFC3339, "2018-05-03T15:04:05.323Z")
 
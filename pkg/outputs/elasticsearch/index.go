@@ -25,17 +25,32 @@ import (
 	"github.com/olivere/elastic/v7"
 	"github.com/rabbitstack/fibratus/pkg/event"
 	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
 
+// dataStreamType is the type segment of data stream names
+const dataStreamType = "logs"
+
 type index struct {
 	config Config
 	client *elastic.Client
 }
 
-// putTemplate creates the index template.
+// putTemplate creates the index template. If data streams are
+// enabled, the lifecycle policy and the composable index template
+// are created instead of the legacy index template.
 func (i index) putTemplate() error {
+	if i.config.DataStream {
+		if err := i.putLifecyclePolicy(); err != nil {
+			return err
+		}
+		return i.putIndexTemplate()
+	}
 	if i.config.TemplateName == "" {
 		return nil
 	}
@@ -75,9 +90,113 @@ func (i index) putTemplate() error {
 	return nil
 }
 
+// putLifecyclePolicy creates the index lifecycle management policy
+// attached to the data streams backing indices unless it already exists.
+func (i index) putLifecyclePolicy() error {
+	if i.config.ILMPolicy == "" {
+		return nil
+	}
+	ctx := context.Background()
+
+	_, err := i.client.XPackIlmGetLifecycle().Policy(i.config.ILMPolicy).Do(ctx)
+	if err == nil {
+		return nil
+	}
+	if !elastic.IsNotFound(err) {
+		return fmt.Errorf("unable to check the existence of the %q lifecycle policy: %v", i.config.ILMPolicy, err)
+	}
+
+	policy := lifecyclePolicy
+	if i.config.ILMPolicyConfig != "" {
+		policy = i.config.ILMPolicyConfig
+	}
+	_, err = i.client.XPackIlmPutLifecycle().Policy(i.config.ILMPolicy).BodyString(policy).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to create the %q lifecycle policy: %v", i.config.ILMPolicy, err)
+	}
+
+	return nil
+}
+
+// putIndexTemplate creates the composable index template that matches
+// data streams unless it already exists. The client doesn't provide the
+// composable index template API, so requests are performed directly.
+func (i index) putIndexTemplate() error {
+	if i.config.TemplateName == "" {
+		return nil
+	}
+
+	var b bytes.Buffer
+	if i.config.TemplateConfig != "" {
+		b.WriteString(i.config.TemplateConfig)
+	} else {
+		tmpl := template.Must(template.New("template").Parse(dataStreamTemplate))
+		err := tmpl.Execute(&b, dataStreamTemplateInfo{IndexPatterns: i.dataStreamPatterns(), ILMPolicy: i.config.ILMPolicy})
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	path := "/_index_template/" + url.PathEscape(i.config.TemplateName)
+
+	resp, err := i.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:       http.MethodHead,
+		Path:         path,
+		IgnoreErrors: []int{http.StatusNotFound},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to check the existence of the %q index template: %v", i.config.TemplateName, err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	_, err = i.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodPut,
+		Path:   path,
+		Body:   b.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create the %q index template: %v", i.config.TemplateName, err)
+	}
+
+	return nil
+}
+
+// dataStreamPatterns returns index patterns matching all data streams
+// the events can be routed to.
+func (i index) dataStreamPatterns() []string {
+	patterns := []string{fmt.Sprintf("%s-fibratus.*-*", dataStreamType)}
+	for _, dataset := range i.config.Routing {
+		if strings.HasPrefix(dataset, "fibratus.") {
+			continue
+		}
+		pattern := fmt.Sprintf("%s-%s-*", dataStreamType, dataset)
+		if !slices.Contains(patterns, pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns[1:])
+	return patterns
+}
+
+// dataStreamName returns the data stream name for the event category
+// following the logs-<dataset>-<namespace> naming scheme.
+func (i index) dataStreamName(category event.Category) string {
+	dataset, ok := i.config.Routing[string(category)]
+	if !ok {
+		dataset = "fibratus." + string(category)
+	}
+	return fmt.Sprintf("%s-%s-%s", dataStreamType, dataset, i.config.DataStreamNamespace)
+}
+
 // getName creates an index name by replacing specifiers to create time frame indices. If no time specifiers are
-// used this method returns a fixed index name.
+// used this method returns a fixed index name. If data streams are enabled, the data stream name is returned.
 func (i index) getName(evt *event.Event) string {
+	if i.config.DataStream {
+		return i.dataStreamName(evt.Category)
+	}
 	indexName := i.config.IndexName
 	if !strings.Contains(indexName, "%") {
 		return indexName
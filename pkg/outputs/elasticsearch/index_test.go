@@ -48,3 +48,46 @@ func TestProduceIndexName(t *testing.T) {
 	indexName = i.getName(&event.Event{Timestamp: ts})
 	assert.Equal(t, "fibratus-events", indexName)
 }
+
+func TestDataStreamName(t *testing.T) {
+	i := index{config: Config{DataStream: true, DataStreamNamespace: "prod", IndexName: "fibratus-%Y-%m"}}
+
+	assert.Equal(t, "logs-fibratus.process-prod", i.getName(&event.Event{Category: event.Process}))
+	assert.Equal(t, "logs-fibratus.registry-prod", i.getName(&event.Event{Category: event.Registry}))
+	assert.Equal(t, []string{"logs-fibratus.*-*"}, i.dataStreamPatterns())
+
+	i.config.Routing = map[string]string{"registry": "windows.registry", "file": "fibratus.io", "net": "windows.net"}
+
+	assert.Equal(t, "logs-windows.registry-prod", i.getName(&event.Event{Category: event.Registry}))
+	assert.Equal(t, "logs-fibratus.io-prod", i.getName(&event.Event{Category: event.File}))
+	assert.Equal(t, "logs-fibratus.process-prod", i.getName(&event.Event{Category: event.Process}))
+	assert.Equal(t, []string{"logs-fibratus.*-*", "logs-windows.net-*", "logs-windows.registry-*"}, i.dataStreamPatterns())
+}
+
+func TestDataStreamConfigValidate(t *testing.T) {
+	var tests = []struct {
+		config Config
+		valid  bool
+	}{
+		{Config{DataStreamNamespace: "Prod-1"}, true},
+		{Config{DataStream: true, DataStreamNamespace: "prod"}, true},
+		{Config{DataStream: true, DataStreamNamespace: ""}, false},
+		{Config{DataStream: true, DataStreamNamespace: "Prod"}, false},
+		{Config{DataStream: true, DataStreamNamespace: "prod-eu"}, false},
+		{Config{DataStream: true, DataStreamNamespace: "prod", Routing: map[string]string{"file": "windows.file"}}, true},
+		{Config{DataStream: true, DataStreamNamespace: "prod", Routing: map[string]string{"file": "windows-file"}}, false},
+		{Config{DataStream: true, DataStreamNamespace: "prod", DeadLetterIndex: "fibratus-dead-letter"}, true},
+		{Config{DataStream: true, DataStreamNamespace: "prod", DeadLetterIndex: "logs-fibratus.dead-letter-default"}, false},
+		{Config{DataStream: true, DataStreamNamespace: "prod", DeadLetterIndex: "metrics-dead-letter"}, false},
+		{Config{DeadLetterIndex: "logs-fibratus.dead-letter-default"}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.config.validate()
+		if tt.valid {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+}
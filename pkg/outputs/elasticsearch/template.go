@@ -81,3 +81,84 @@ const indexTemplate = `
 	}
 }
 `
+
+type dataStreamTemplateInfo struct {
+	IndexPatterns []string
+	ILMPolicy     string
+}
+
+// dataStreamTemplate is the composable index template that enables data streams
+// for indices matching the pattern. The priority takes precedence over the
+// built-in logs-*-* template.
+const dataStreamTemplate = `
+{
+	"index_patterns": [ {{ range $i, $p := .IndexPatterns }}{{ if $i }}, {{ end }}"{{ $p }}"{{ end }} ],
+	"data_stream": {},
+	"priority": 200,
+	"template": {
+		"settings": {
+			"index": {
+				{{ if .ILMPolicy }}"lifecycle": { "name": "{{ .ILMPolicy }}" },{{ end }}
+				"refresh_interval": "5s"
+			}
+		},
+		"mappings": {
+			"dynamic_templates": [
+				{
+					"strings_as_keyword": {
+						"match_mapping_type": "string",
+						"mapping": { "type": "keyword", "ignore_above": 1024 }
+					}
+				}
+			],
+			"properties": {
+				"@timestamp": { "type": "date" },
+
+				"seq": { "type": "long" },
+				"pid": { "type": "long" },
+				"tid": { "type": "long" },
+				"cpu": { "type": "short" },
+				"name": { "type": "keyword" },
+				"category": { "type": "keyword" },
+				"description": { "type": "text" },
+				"host": { "type": "keyword" },
+				"timestamp": { "type": "date" },
+				"params": {
+					"properties": {
+						"dip": { "type": "ip" },
+						"sip": { "type": "ip" }
+					}
+				},
+
+				"message": { "type": "text" },
+				"source": { "properties": { "ip": { "type": "ip" } } },
+				"destination": { "properties": { "ip": { "type": "ip" } } }
+			}
+		}
+	},
+	"_meta": {
+		"description": "Fibratus data streams template"
+	}
+}
+`
+
+// lifecyclePolicy is the default index lifecycle management policy. Backing
+// indices are rolled over when they reach 50GB or 30 days and deleted 90 days
+// after the rollover.
+const lifecyclePolicy = `
+{
+	"policy": {
+		"phases": {
+			"hot": {
+				"actions": {
+					"rollover": { "max_size": "50gb", "max_age": "30d" }
+				}
+			},
+			"delete": {
+				"min_age": "90d",
+				"actions": { "delete": {} }
+			}
+		}
+	}
+}
+`
@@ -20,6 +20,7 @@ package eventlog
 
 import (
 	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
 	"text/template"
 
 	"github.com/spf13/pflag"
@@ -30,6 +31,7 @@ const (
 	level      = "output.eventlog.level"
 	remoteHost = "output.eventlog.remote-host"
 	tmpl       = "output.eventlog.template"
+	serializer = "output.eventlog.serializer"
 )
 
 // Config contains configuration properties for fine-tuning the eventlog output.
@@ -42,6 +44,9 @@ type Config struct {
 	RemoteHost string `mapstructure:"remote-host"`
 	// Template specifies the Go template for rendering the eventlog message.
 	Template string `mapstructure:"template"`
+	// Serializer specifies the serializer for the eventlog message. If
+	// empty, the eventlog message is rendered from the template.
+	Serializer outputs.Serializer `mapstructure:"serializer"`
 }
 
 func (c Config) parseTemplate() (*template.Template, error) {
@@ -58,4 +63,5 @@ func AddFlags(flags *pflag.FlagSet) {
 	flags.String(level, "info", "Specifies the eventlog level. Deprecated")
 	flags.String(remoteHost, "", "Address of the remote eventlog intake")
 	flags.Bool(enabled, false, "Indicates if the eventlog output is enabled")
+	flags.String(serializer, "", "Indicates the event serializer type. If empty, the message is rendered from the template")
 }
@@ -56,6 +56,9 @@ func initEventlog(config outputs.Config) (outputs.OutputGroup, error) {
 	if !ok {
 		return outputs.Fail(outputs.ErrInvalidConfig(outputs.Eventlog, config.Output))
 	}
+	if err := cfg.Serializer.Validate(outputs.Eventlog); err != nil {
+		return outputs.Fail(err)
+	}
 	err := eventlog.Install(eventlog.Levels)
 	if err != nil {
 		// ignore error if the key already exists
@@ -109,7 +112,7 @@ func (e *evtlog) Publish(batch *event.Batch) error {
 }
 
 func (e *evtlog) publish(evt *event.Event) error {
-	buf, err := evt.RenderCustomTemplate(e.tmpl)
+	buf, err := e.render(evt)
 	if err != nil {
 		return err
 	}
@@ -125,6 +128,16 @@ func (e *evtlog) publish(evt *event.Event) error {
 	return nil
 }
 
+// render produces the eventlog message either from
+// the serializer or the template if the serializer
+// is not specified.
+func (e *evtlog) render(evt *event.Event) ([]byte, error) {
+	if e.config.Serializer != "" {
+		return e.config.Serializer.Marshal(evt)
+	}
+	return evt.RenderCustomTemplate(e.tmpl)
+}
+
 // categoryID maps category name to eventlog identifier.
 func (e *evtlog) categoryID(evt *event.Event) uint16 {
 	for i, cat := range e.cats {
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/spf13/pflag"
+)
+
+// Compression is the compression algorithm applied to rotated files.
+type Compression string
+
+const (
+	// None leaves rotated files uncompressed.
+	None Compression = "none"
+	// Gzip compresses rotated files with gzip.
+	Gzip Compression = "gzip"
+	// Zstd compresses rotated files with Zstandard.
+	Zstd Compression = "zstd"
+)
+
+const (
+	fileEnabled          = "output.file.enabled"
+	filePath             = "output.file.path"
+	fileName             = "output.file.name"
+	fileSerializer       = "output.file.serializer"
+	fileMaxSize          = "output.file.max-size"
+	fileRotationInterval = "output.file.rotation-interval"
+	fileMaxBackups       = "output.file.max-backups"
+	fileCompression      = "output.file.compression"
+	fileFsyncInterval    = "output.file.fsync-interval"
+)
+
+// Config contains the options for tweaking the file output behaviour.
+type Config struct {
+	// Enabled determines whether file output is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Path is the directory where event files are written. If empty,
+	// the Events directory in the installation directory is used.
+	Path string `mapstructure:"path"`
+	// Name is the template of the file name. The template can reference
+	// the {{ .Host }} and {{ .Date }} fields. The date field renders the
+	// date at which the file is created in the YYYY-MM-DD format.
+	Name string `mapstructure:"name"`
+	// Serializer indicates the serializer for the events written to the file.
+	Serializer outputs.Serializer `mapstructure:"serializer"`
+	// MaxSize is the maximum size in megabytes of the file before it is rotated.
+	MaxSize int `mapstructure:"max-size"`
+	// RotationInterval is the interval at which the file is rotated
+	// regardless of its size. Rotations are aligned to interval
+	// boundaries in UTC.
+	RotationInterval time.Duration `mapstructure:"rotation-interval"`
+	// MaxBackups is the maximum number of rotated files to retain.
+	MaxBackups int `mapstructure:"max-backups"`
+	// Compression is the compression algorithm applied to rotated files.
+	Compression Compression `mapstructure:"compression"`
+	// FsyncInterval is the interval at which written events are flushed
+	// to stable storage. If zero, the file is synced after every batch.
+	FsyncInterval time.Duration `mapstructure:"fsync-interval"`
+}
+
+// AddFlags registers persistent flags for the file output.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(fileEnabled, false, "Determines whether the file output is enabled")
+	flags.String(filePath, "", "Specifies the directory where event files are written. Defaults to the Events directory in the installation directory")
+	flags.String(fileName, "fibratus-{{ .Host }}-{{ .Date }}.jsonl", "Specifies the template of the file name. The template can reference {{ .Host }} and {{ .Date }} fields")
+	flags.String(fileSerializer, string(outputs.JSON), "Indicates the event serializer type")
+	flags.Int(fileMaxSize, 100, "Specifies the maximum size in megabytes of the file before it is rotated")
+	flags.Duration(fileRotationInterval, time.Hour*24, "Specifies the interval at which the file is rotated regardless of its size")
+	flags.Int(fileMaxBackups, 10, "Specifies the maximum number of rotated files to retain")
+	flags.String(fileCompression, string(None), "Specifies the compression algorithm applied to rotated files. Possible values are none, gzip, and zstd")
+	flags.Duration(fileFsyncInterval, time.Second, "Specifies the interval at which written events are flushed to stable storage")
+}
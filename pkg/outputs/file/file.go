@@ -0,0 +1,162 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"expvar"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/rabbitstack/fibratus/pkg/util/hostname"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// fileEvents counts the total number of events written to files
+	fileEvents = expvar.NewInt("output.file.written.events")
+	// fileErrors counts file write errors
+	fileErrors = expvar.NewInt("output.file.write.errors")
+	// fileRotations counts file rotations
+	fileRotations = expvar.NewInt("output.file.rotations")
+	// fileCompressionErrors counts errors produced while compressing rotated files
+	fileCompressionErrors = expvar.NewInt("output.file.compression.errors")
+	// fileRemovedBackups counts rotated files removed due to exceeding the maximum number of backups
+	fileRemovedBackups = expvar.NewInt("output.file.removed.backups")
+)
+
+type file struct {
+	config Config
+	r      *rotator
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func init() {
+	outputs.Register(outputs.File, initFile)
+}
+
+func initFile(config outputs.Config) (outputs.OutputGroup, error) {
+	cfg, ok := config.Output.(Config)
+	if !ok {
+		return outputs.Fail(outputs.ErrInvalidConfig(outputs.File, config.Output))
+	}
+	f, err := newFile(cfg, hostname.Get())
+	if err != nil {
+		return outputs.Fail(err)
+	}
+	return outputs.Success(f), nil
+}
+
+func newFile(cfg Config, host string) (*file, error) {
+	if err := cfg.Serializer.Validate(outputs.File); err != nil {
+		return nil, err
+	}
+	switch cfg.Compression {
+	case None, Gzip, Zstd:
+	case "":
+		cfg.Compression = None
+	default:
+		return nil, fmt.Errorf("unsupported file output compression %q. Use one of none, gzip, or zstd", cfg.Compression)
+	}
+
+	r, err := newRotator(cfg, fileDir(cfg.Path), host)
+	if err != nil {
+		return nil, err
+	}
+	f := &file{
+		config: cfg,
+		r:      r,
+		stop:   make(chan struct{}),
+	}
+	if cfg.FsyncInterval > 0 {
+		f.wg.Add(1)
+		go f.fsync()
+	}
+	return f, nil
+}
+
+func (f *file) Connect() error { return nil }
+
+func (f *file) Close() error {
+	close(f.stop)
+	f.wg.Wait()
+	return f.r.close()
+}
+
+// Publish writes serialized events to the active file. Text
+// serializers write one event per line, while binary serializers
+// write length-delimited or self-delimiting records. If the fsync
+// interval is not specified, the file is synced after the batch
+// is written.
+func (f *file) Publish(batch *event.Batch) error {
+	for _, evt := range batch.Events {
+		buf, err := f.config.Serializer.MarshalDelimited(evt)
+		if err != nil {
+			fileErrors.Add(1)
+			return err
+		}
+		if err := f.r.write(buf); err != nil {
+			fileErrors.Add(1)
+			return err
+		}
+		fileEvents.Add(1)
+	}
+	if f.config.FsyncInterval == 0 {
+		if err := f.r.sync(); err != nil {
+			fileErrors.Add(1)
+			return err
+		}
+	}
+	return nil
+}
+
+// fsync periodically flushes written events to stable storage.
+func (f *file) fsync() {
+	defer f.wg.Done()
+	tick := time.NewTicker(f.config.FsyncInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			if err := f.r.sync(); err != nil {
+				fileErrors.Add(1)
+				log.Warnf("unable to sync events file: %v", err)
+			}
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// fileDir returns the directory where event files are written.
+func fileDir(path string) string {
+	if path != "" {
+		return path
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return filepath.Join(os.Getenv("PROGRAMFILES"), "Fibratus", "Events")
+	}
+	return filepath.Join(filepath.Dir(exe), "..", "Events")
+}
@@ -0,0 +1,418 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/eventpb"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatch(seq uint64) *event.Batch {
+	return event.NewBatch(
+		&event.Event{
+			Seq:       seq,
+			Type:      event.CreateProcess,
+			Timestamp: time.Date(2025, 3, 14, 10, 11, 12, 0, time.UTC),
+			Name:      "CreateProcess",
+			Category:  event.Process,
+			PID:       4024,
+			Host:      "archrabbit",
+			PS:        &pstypes.PS{PID: 4024, Name: "cmd.exe", Exe: `C:\Windows\System32\cmd.exe`},
+		},
+		&event.Event{
+			Seq:       seq + 1,
+			Type:      event.CreateFile,
+			Timestamp: time.Date(2025, 3, 14, 10, 11, 13, 0, time.UTC),
+			Name:      "CreateFile",
+			Category:  event.File,
+			PID:       4024,
+			Host:      "archrabbit",
+		},
+	)
+}
+
+func newConfig(dir string) Config {
+	return Config{
+		Enabled:          true,
+		Path:             dir,
+		Name:             "fibratus-{{ .Host }}-{{ .Date }}.jsonl",
+		Serializer:       outputs.JSON,
+		MaxSize:          100,
+		RotationInterval: time.Hour * 24,
+		MaxBackups:       10,
+		Compression:      None,
+	}
+}
+
+// clock is the controllable time source.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newFileWithClock(t *testing.T, cfg Config) (*file, *clock) {
+	f, err := newFile(cfg, "archrabbit")
+	require.NoError(t, err)
+	c := &clock{now: time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)}
+	f.r.now = c.Now
+	return f, c
+}
+
+func readLines(t *testing.T, r io.Reader) []string {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func listFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPublish(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newConfig(dir)
+	f, _ := newFileWithClock(t, cfg)
+
+	require.NoError(t, f.Publish(newBatch(1)))
+	require.NoError(t, f.Publish(newBatch(3)))
+
+	// the file is synced after every batch
+	// if the fsync interval is not specified
+	b, err := os.Open(filepath.Join(dir, "fibratus-archrabbit-2025-03-14.jsonl"))
+	require.NoError(t, err)
+	defer b.Close()
+	lines := readLines(t, b)
+	require.Len(t, lines, 4)
+	for i, line := range lines {
+		var evt struct {
+			Seq uint64 `json:"seq"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &evt))
+		assert.Equal(t, uint64(i+1), evt.Seq)
+	}
+
+	require.NoError(t, f.Close())
+	require.Error(t, f.Publish(newBatch(5)))
+}
+
+func TestPublishAppendsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newConfig(dir)
+
+	f, _ := newFileWithClock(t, cfg)
+	require.NoError(t, f.Publish(newBatch(1)))
+	require.NoError(t, f.Close())
+
+	f, _ = newFileWithClock(t, cfg)
+	require.NoError(t, f.Publish(newBatch(3)))
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, []string{"fibratus-archrabbit-2025-03-14.jsonl"}, listFiles(t, dir))
+	b, err := os.ReadFile(filepath.Join(dir, "fibratus-archrabbit-2025-03-14.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, readLines(t, bytes.NewReader(b)), 4)
+}
+
+func TestPublishSerializers(t *testing.T) {
+	var tests = []struct {
+		serializer outputs.Serializer
+		seq        func(map[string]any) any
+		expected   any
+	}{
+		{outputs.ECS, func(m map[string]any) any { return m["event"].(map[string]any)["sequence"] }, float64(1)},
+		{outputs.OCSF, func(m map[string]any) any { return m["metadata"].(map[string]any)["uid"] }, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.serializer), func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := newConfig(dir)
+			cfg.Serializer = tt.serializer
+			f, _ := newFileWithClock(t, cfg)
+
+			require.NoError(t, f.Publish(newBatch(1)))
+			require.NoError(t, f.Close())
+
+			b, err := os.ReadFile(filepath.Join(dir, "fibratus-archrabbit-2025-03-14.jsonl"))
+			require.NoError(t, err)
+			lines := readLines(t, bytes.NewReader(b))
+			require.Len(t, lines, 2)
+			var doc map[string]any
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &doc))
+			assert.Equal(t, tt.expected, tt.seq(doc))
+		})
+	}
+}
+
+func TestPublishBinarySerializers(t *testing.T) {
+	for _, serializer := range []outputs.Serializer{outputs.Protobuf, outputs.Msgpack} {
+		t.Run(string(serializer), func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := newConfig(dir)
+			cfg.Serializer = serializer
+			f, _ := newFileWithClock(t, cfg)
+
+			require.NoError(t, f.Publish(newBatch(1)))
+			require.NoError(t, f.Publish(newBatch(3)))
+			require.NoError(t, f.Close())
+
+			r, err := os.Open(filepath.Join(dir, "fibratus-archrabbit-2025-03-14.jsonl"))
+			require.NoError(t, err)
+			defer r.Close()
+
+			dec := eventpb.NewDecoder(r, eventpb.Format(serializer))
+			var seqs []uint64
+			for {
+				evt, err := dec.Decode()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				seqs = append(seqs, evt.Seq)
+			}
+			assert.Equal(t, []uint64{1, 2, 3, 4}, seqs)
+		})
+	}
+}
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	f, c := newFileWithClock(t, newConfig(dir))
+	f.r.maxSize = 1
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, f.Publish(newBatch(uint64(i*2+1))))
+		c.Advance(time.Millisecond)
+	}
+	require.NoError(t, f.Close())
+
+	files := listFiles(t, dir)
+	// every event exceeds the maximum size, so each
+	// event ends up in its own file. Rotations within
+	// the same millisecond get distinct backup names
+	require.Len(t, files, 6)
+	assert.Contains(t, files, "fibratus-archrabbit-2025-03-14.jsonl")
+	for _, name := range files {
+		if name == "fibratus-archrabbit-2025-03-14.jsonl" {
+			continue
+		}
+		assert.Regexp(t, `^fibratus-archrabbit-2025-03-14-20250314T100000\.00\d\.jsonl$`, name)
+		assert.True(t, f.r.backups.MatchString(name))
+	}
+}
+
+func TestRotateByInterval(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newConfig(dir)
+	cfg.RotationInterval = time.Hour * 24
+	f, c := newFileWithClock(t, cfg)
+
+	require.NoError(t, f.Publish(newBatch(1)))
+	c.Advance(time.Hour * 13)
+	require.NoError(t, f.Publish(newBatch(3)))
+	c.Advance(time.Hour)
+	require.NoError(t, f.Publish(newBatch(5)))
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, []string{
+		"fibratus-archrabbit-2025-03-14-20250315T000000.000.jsonl",
+		"fibratus-archrabbit-2025-03-15.jsonl",
+	}, listFiles(t, dir))
+}
+
+func TestMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newConfig(dir)
+	cfg.MaxBackups = 2
+	f, c := newFileWithClock(t, cfg)
+	f.r.maxSize = 1
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, f.Publish(newBatch(uint64(i*2+1))))
+		c.Advance(time.Second)
+	}
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, []string{
+		"fibratus-archrabbit-2025-03-14-20250314T100004.000.jsonl",
+		"fibratus-archrabbit-2025-03-14-20250314T100004.001.jsonl",
+		"fibratus-archrabbit-2025-03-14.jsonl",
+	}, listFiles(t, dir))
+}
+
+func TestMaxBackupsOnStartup(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"fibratus-archrabbit-2025-03-12-20250313T000000.000.jsonl",
+		"fibratus-archrabbit-2025-03-13-20250314T000000.000.jsonl.gz",
+		"fibratus-archrabbit-2025-03-14-20250314T080000.000.jsonl",
+		"fibratus-archrabbit-2025-03-14.jsonl",
+		"notes.txt",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}\n"), 0644))
+	}
+
+	cfg := newConfig(dir)
+	cfg.MaxBackups = 1
+	cfg.Compression = Gzip
+	f, _ := newFileWithClock(t, cfg)
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, []string{
+		"fibratus-archrabbit-2025-03-14-20250314T080000.000.jsonl.gz",
+		"fibratus-archrabbit-2025-03-14.jsonl",
+		"notes.txt",
+	}, listFiles(t, dir))
+}
+
+func TestCompression(t *testing.T) {
+	var tests = []struct {
+		compression Compression
+		ext         string
+		reader      func(io.Reader) (io.Reader, error)
+	}{
+		{Gzip, ".gz", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{Zstd, ".zst", func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.compression), func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := newConfig(dir)
+			cfg.Compression = tt.compression
+			f, c := newFileWithClock(t, cfg)
+
+			require.NoError(t, f.Publish(newBatch(1)))
+			c.Advance(time.Hour * 24)
+			require.NoError(t, f.Publish(newBatch(3)))
+			require.NoError(t, f.Close())
+
+			backup := "fibratus-archrabbit-2025-03-14-20250315T100000.000.jsonl" + tt.ext
+			assert.Equal(t, []string{backup, "fibratus-archrabbit-2025-03-15.jsonl"}, listFiles(t, dir))
+
+			b, err := os.Open(filepath.Join(dir, backup))
+			require.NoError(t, err)
+			defer b.Close()
+			r, err := tt.reader(b)
+			require.NoError(t, err)
+			assert.Len(t, readLines(t, r), 2)
+		})
+	}
+}
+
+func TestNameTemplate(t *testing.T) {
+	var tests = []struct {
+		name   string
+		valid  bool
+		active string
+		backup string
+	}{
+		{"{{ .Host }}.ndjson", true, "archrabbit.ndjson", "archrabbit-20250314T100000.000.ndjson"},
+		{"events-{{ .Date }}", true, "events-2025-03-14", "events-2025-03-14-20250314T100000.000"},
+		{"logs/{{ .Host }}.jsonl", false, "", ""},
+		{"{{ .Hostname }}.jsonl", false, "", ""},
+		{"{{ .Host", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig(t.TempDir())
+			cfg.Name = tt.name
+			r, err := newRotator(cfg, cfg.Path, "archrabbit")
+			if !tt.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer r.close()
+			name, err := r.render("2025-03-14")
+			require.NoError(t, err)
+			assert.Equal(t, tt.active, name)
+			assert.False(t, r.backups.MatchString(tt.active))
+			assert.True(t, r.backups.MatchString(tt.backup))
+		})
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	cfg := newConfig(t.TempDir())
+	cfg.Compression = "lz4"
+	_, err := newFile(cfg, "archrabbit")
+	require.Error(t, err)
+
+	cfg = newConfig(t.TempDir())
+	cfg.Serializer = "xml"
+	_, err = newFile(cfg, "archrabbit")
+	require.Error(t, err)
+}
+
+func TestFsyncInterval(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newConfig(dir)
+	cfg.FsyncInterval = time.Millisecond * 10
+	f, _ := newFileWithClock(t, cfg)
+	defer f.Close()
+
+	require.NoError(t, f.Publish(newBatch(1)))
+
+	require.Eventually(t, func() bool {
+		b, err := os.ReadFile(filepath.Join(dir, "fibratus-archrabbit-2025-03-14.jsonl"))
+		return err == nil && len(readLines(t, bytes.NewReader(b))) == 2
+	}, time.Second*5, time.Millisecond*10)
+}
@@ -0,0 +1,396 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// dateLayout is the layout of the date field in the file name template
+	dateLayout = "2006-01-02"
+	// backupLayout is the layout of the timestamp appended to rotated files
+	backupLayout = "20060102T150405.000"
+	// dateSentinel substitutes the date field when the template is
+	// rendered to derive the pattern that matches rotated files
+	dateSentinel = "\x00"
+)
+
+// nameData is the data that is available to the file name template.
+type nameData struct {
+	// Host is the name of the host where the file is written.
+	Host string
+	// Date is the date at which the file is created.
+	Date string
+}
+
+// rotator writes to the active file and rotates it when the file
+// exceeds the maximum size or the rotation interval elapses. The
+// rotated file is renamed by appending the rotation timestamp to
+// the file stem. Rotated files are compressed and pruned in the
+// background.
+type rotator struct {
+	mu sync.Mutex
+
+	dir         string
+	host        string
+	name        *template.Template
+	ext         string
+	maxSize     int64
+	interval    time.Duration
+	maxBackups  int
+	compression Compression
+
+	f        *os.File
+	w        *bufio.Writer
+	path     string
+	size     int64
+	rotateAt time.Time
+	dirty    bool
+	closed   bool
+
+	// backups matches rotated file names and captures the rotation timestamp
+	backups *regexp.Regexp
+	mill    chan struct{}
+	wg      sync.WaitGroup
+
+	now func() time.Time
+}
+
+func newRotator(cfg Config, dir, host string) (*rotator, error) {
+	name, err := template.New("name").Option("missingkey=error").Parse(cfg.Name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file name template: %v", err)
+	}
+	r := &rotator{
+		dir:         dir,
+		host:        host,
+		name:        name,
+		maxSize:     int64(cfg.MaxSize) * 1024 * 1024,
+		interval:    cfg.RotationInterval,
+		maxBackups:  cfg.MaxBackups,
+		compression: cfg.Compression,
+		mill:        make(chan struct{}, 1),
+		now:         time.Now,
+	}
+
+	// derive the extension and the pattern of rotated
+	// files from the name rendered with the date sentinel
+	pattern, err := r.render(dateSentinel)
+	if err != nil {
+		return nil, err
+	}
+	if pattern == "" || filepath.Base(pattern) != pattern {
+		return nil, fmt.Errorf("file name template must render to a file name without directories: %q", pattern)
+	}
+	r.ext = filepath.Ext(pattern)
+	if strings.Contains(r.ext, dateSentinel) {
+		r.ext = ""
+	}
+	stem := strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSuffix(pattern, r.ext)), dateSentinel, `\d{4}-\d{2}-\d{2}`)
+	r.backups, err = regexp.Compile("^" + stem + `-(\d{8}T\d{6}\.\d{3})` + regexp.QuoteMeta(r.ext) + `(\.gz|\.zst)?$`)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	r.wg.Add(1)
+	go r.millRun()
+	// compress and prune backups left
+	// over from previous executions
+	r.mill <- struct{}{}
+
+	return r, nil
+}
+
+// render renders the file name template for the given date.
+func (r *rotator) render(date string) (string, error) {
+	var b bytes.Buffer
+	if err := r.name.Execute(&b, nameData{Host: r.host, Date: date}); err != nil {
+		return "", fmt.Errorf("unable to render file name template: %v", err)
+	}
+	return b.String(), nil
+}
+
+// write writes the buffer to the active file. The file is
+// opened on the first write, and rotated before the write
+// if the buffer would exceed the maximum file size or the
+// rotation interval elapsed.
+func (r *rotator) write(b []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errors.New("file output is closed")
+	}
+	now := r.now()
+	switch {
+	case r.f == nil:
+		if err := r.open(now); err != nil {
+			return err
+		}
+	case r.maxSize > 0 && r.size > 0 && r.size+int64(len(b)) > r.maxSize,
+		!r.rotateAt.IsZero() && !now.Before(r.rotateAt):
+		if err := r.rotate(now); err != nil {
+			return err
+		}
+	}
+	n, err := r.w.Write(b)
+	r.size += int64(n)
+	r.dirty = true
+	return err
+}
+
+// sync flushes buffered data and commits the
+// contents of the active file to stable storage.
+func (r *rotator) sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil || !r.dirty {
+		return nil
+	}
+	if err := r.w.Flush(); err != nil {
+		return err
+	}
+	r.dirty = false
+	return r.f.Sync()
+}
+
+// close closes the active file and waits
+// for pending backups to be compressed.
+func (r *rotator) close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	err := r.closeFile()
+	r.mu.Unlock()
+	close(r.mill)
+	r.wg.Wait()
+	return err
+}
+
+func (r *rotator) open(now time.Time) error {
+	name, err := r.render(now.UTC().Format(dateLayout))
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(r.dir, name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.f = f
+	r.w = bufio.NewWriterSize(f, 64*1024)
+	r.path = path
+	r.size = fi.Size()
+	if r.interval > 0 {
+		r.rotateAt = now.UTC().Truncate(r.interval).Add(r.interval)
+	}
+	return nil
+}
+
+func (r *rotator) closeFile() error {
+	if r.f == nil {
+		return nil
+	}
+	err := r.w.Flush()
+	if serr := r.f.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	r.f, r.w = nil, nil
+	r.dirty = false
+	return err
+}
+
+// rotate closes the active file, renames it to the backup
+// name, and opens a new active file. The mill goroutine is
+// signaled to compress the backup and prune old backups.
+func (r *rotator) rotate(now time.Time) error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+	// bump the timestamp if the backup
+	// with the same name already exists
+	var backup string
+	for ts := now.UTC(); ; ts = ts.Add(time.Millisecond) {
+		backup = strings.TrimSuffix(r.path, r.ext) + "-" + ts.Format(backupLayout) + r.ext
+		if _, err := os.Stat(backup); err != nil {
+			break
+		}
+	}
+	if err := os.Rename(r.path, backup); err != nil {
+		return fmt.Errorf("unable to rotate %s: %v", r.path, err)
+	}
+	fileRotations.Add(1)
+	select {
+	case r.mill <- struct{}{}:
+	default:
+	}
+	return r.open(now)
+}
+
+// millRun compresses uncompressed backups and
+// removes backups exceeding the maximum count.
+func (r *rotator) millRun() {
+	defer r.wg.Done()
+	for range r.mill {
+		backups, err := r.listBackups()
+		if err != nil {
+			log.Warnf("unable to list rotated files in %s: %v", r.dir, err)
+			continue
+		}
+		if r.compression == Gzip || r.compression == Zstd {
+			for i, backup := range backups {
+				if filepath.Ext(backup.name) == ".gz" || filepath.Ext(backup.name) == ".zst" {
+					continue
+				}
+				name, err := r.compress(backup.name)
+				if err != nil {
+					fileCompressionErrors.Add(1)
+					log.Warnf("unable to compress %s: %v", backup.name, err)
+					continue
+				}
+				backups[i].name = name
+			}
+		}
+		if r.maxBackups <= 0 || len(backups) <= r.maxBackups {
+			continue
+		}
+		for _, backup := range backups[r.maxBackups:] {
+			if err := os.Remove(filepath.Join(r.dir, backup.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Warnf("unable to remove rotated file %s: %v", backup.name, err)
+				continue
+			}
+			fileRemovedBackups.Add(1)
+		}
+	}
+}
+
+// backup describes the rotated file.
+type backup struct {
+	name string
+	ts   string
+}
+
+// listBackups returns rotated files sorted from newest to oldest.
+func (r *rotator) listBackups() ([]backup, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]backup, 0)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := r.backups.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		backups = append(backups, backup{name: e.Name(), ts: m[1]})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].ts == backups[j].ts {
+			return backups[i].name > backups[j].name
+		}
+		return backups[i].ts > backups[j].ts
+	})
+	return backups, nil
+}
+
+// compress compresses the rotated file and removes
+// the original. It returns the compressed file name.
+func (r *rotator) compress(name string) (string, error) {
+	src := filepath.Join(r.dir, name)
+	ext := ".gz"
+	if r.compression == Zstd {
+		ext = ".zst"
+	}
+	dst := src + ext
+
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	var w io.WriteCloser
+	if r.compression == Zstd {
+		w, err = zstd.NewWriter(out)
+		if err != nil {
+			_ = out.Close()
+			_ = os.Remove(dst)
+			return "", err
+		}
+	} else {
+		w = gzip.NewWriter(out)
+	}
+	_, err = io.Copy(w, in)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if serr := out.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return "", err
+	}
+	_ = in.Close()
+	if err := os.Remove(src); err != nil {
+		return "", err
+	}
+	return name + ext, nil
+}
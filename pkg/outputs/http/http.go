@@ -36,9 +36,6 @@ import (
 // userAgentHeader represents the value of the User-Agent header
 var userAgentHeader = version.ProductToken()
 
-// defaultContentType represents the default content type for HTTP requests
-const defaultContentType = "application/json"
-
 type _http struct {
 	client *http.Client
 	config Config
@@ -54,6 +51,9 @@ func initHTTP(config outputs.Config) (outputs.OutputGroup, error) {
 	if !ok {
 		return outputs.Fail(outputs.ErrInvalidConfig(outputs.HTTP, config.Output))
 	}
+	if err := cfg.Serializer.Validate(outputs.HTTP); err != nil {
+		return outputs.Fail(err)
+	}
 
 	clients := make([]outputs.Client, len(cfg.Endpoints))
 	for i, endpoint := range cfg.Endpoints {
@@ -80,10 +80,9 @@ func (h *_http) Connect() error { return nil }
 func (h *_http) Close() error   { return nil }
 
 func (h *_http) Publish(batch *event.Batch) error {
-	var buf []byte
-	switch h.config.Serializer {
-	case outputs.JSON:
-		buf = batch.MarshalJSON()
+	buf, err := h.config.Serializer.MarshalBatch(batch)
+	if err != nil {
+		return outputs.Permanent(err)
 	}
 
 	if h.config.EnableGzip {
@@ -119,7 +118,11 @@ func (h *_http) Publish(batch *event.Batch) error {
 		if err != nil {
 			return err
 		}
-		return fmt.Errorf("http request failed with %d status code: %v", resp.StatusCode, string(body))
+		err = fmt.Errorf("http request failed with %d status code: %v", resp.StatusCode, string(body))
+		if outputs.IsPermanentStatus(resp.StatusCode) {
+			return outputs.Permanent(err)
+		}
+		return err
 	}
 
 	return nil
@@ -136,7 +139,7 @@ func (h *_http) setBasicAuth(req *http.Request) {
 // setHeaders populates required and optional request headers.
 func (h *_http) setHeaders(req *http.Request) {
 	req.Header.Set("User-Agent", userAgentHeader)
-	req.Header.Set("Content-Type", defaultContentType)
+	req.Header.Set("Content-Type", h.config.Serializer.ContentType())
 	if h.config.EnableGzip {
 		req.Header.Set("Content-Encoding", "gzip")
 	}
@@ -35,6 +35,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/eventpb"
 	"github.com/rabbitstack/fibratus/pkg/event/params"
 	htypes "github.com/rabbitstack/fibratus/pkg/handle/types"
 	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
@@ -146,6 +147,51 @@ func TestHttpGzipPublish(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestHttpBinaryPublish(t *testing.T) {
+	var tests = []struct {
+		serializer  outputs.Serializer
+		contentType string
+	}{
+		{outputs.Protobuf, "application/x-protobuf"},
+		{outputs.Msgpack, "application/msgpack"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.serializer), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				events, err := eventpb.UnmarshalBatch(body, eventpb.Format(tt.serializer))
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				assert.Equal(t, tt.contentType, r.Header.Get("Content-Type"))
+				require.Len(t, events, 3)
+				assert.Equal(t, "CreateFile", events[0].Name)
+				assert.Equal(t, "firefox.exe", events[0].Ps.Name)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			c := Config{
+				Timeout:    time.Second * 3,
+				Serializer: tt.serializer,
+			}
+
+			httpClient, err := newHTTPClient(c)
+			require.NoError(t, err)
+
+			h := _http{config: c, client: httpClient, url: srv.URL}
+
+			require.NoError(t, h.Publish(getBatch()))
+		})
+	}
+}
+
 func getBatch() *event.Batch {
 	evt := &event.Event{
 		Type:        event.CreateFile,
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kafka
+
+import (
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/spf13/pflag"
+)
+
+const (
+	kafkaEnabled         = "output.kafka.enabled"
+	kafkaBrokers         = "output.kafka.brokers"
+	kafkaTopic           = "output.kafka.topic"
+	kafkaPartitionKey    = "output.kafka.partition-key"
+	kafkaClientID        = "output.kafka.client-id"
+	kafkaVersion         = "output.kafka.version"
+	kafkaCompression     = "output.kafka.compression"
+	kafkaRequiredAcks    = "output.kafka.required-acks"
+	kafkaIdempotent      = "output.kafka.idempotent"
+	kafkaMaxMessageBytes = "output.kafka.max-message-bytes"
+	kafkaFlushMessages   = "output.kafka.flush-messages"
+	kafkaFlushBytes      = "output.kafka.flush-bytes"
+	kafkaFlushFrequency  = "output.kafka.flush-frequency"
+	kafkaMaxRetries      = "output.kafka.max-retries"
+	kafkaTimeout         = "output.kafka.timeout"
+	kafkaSASLMechanism   = "output.kafka.sasl-mechanism"
+	kafkaUsername        = "output.kafka.username"
+	kafkaPassword        = "output.kafka.password"
+	kafkaTLSEnabled      = "output.kafka.tls-enabled"
+	kafkaSerializer      = "output.kafka.serializer"
+)
+
+// PartitionKey determines how events are distributed across topic partitions.
+type PartitionKey string
+
+const (
+	// Host routes events originated on the same host to the same partition.
+	Host PartitionKey = "host"
+	// PsUUID routes events generated by the same process to the same partition.
+	PsUUID PartitionKey = "ps.uuid"
+	// RoundRobin distributes events evenly across partitions.
+	RoundRobin PartitionKey = "round-robin"
+)
+
+// Config contains the options for tweaking the Kafka output behaviour.
+type Config struct {
+	outputs.TLSConfig
+	// Enabled determines whether Kafka output is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Brokers contains the list of Kafka bootstrap brokers in the host:port format.
+	Brokers []string `mapstructure:"brokers"`
+	// Topic is the template that resolves the destination topic for each event.
+	Topic string `mapstructure:"topic"`
+	// PartitionKey determines how events are distributed across topic partitions.
+	// Can be host, ps.uuid, or round-robin.
+	PartitionKey PartitionKey `mapstructure:"partition-key"`
+	// ClientID is the identifier of the producer sent to brokers.
+	ClientID string `mapstructure:"client-id"`
+	// Version is the Kafka protocol version assumed by the producer.
+	Version string `mapstructure:"version"`
+	// Compression is the message compression codec. Can be none, gzip, snappy, lz4, or zstd.
+	Compression string `mapstructure:"compression"`
+	// RequiredAcks is the level of acknowledgement reliability. Can be none, leader, or all.
+	RequiredAcks string `mapstructure:"required-acks"`
+	// Idempotent enables the idempotent producer that guarantees exactly one copy of each message is written.
+	Idempotent bool `mapstructure:"idempotent"`
+	// MaxMessageBytes is the maximum permitted size of the message.
+	MaxMessageBytes int `mapstructure:"max-message-bytes"`
+	// FlushMessages is the number of messages that triggers the flush of the producer batch.
+	FlushMessages int `mapstructure:"flush-messages"`
+	// FlushBytes is the size of the producer batch in bytes that triggers the flush.
+	FlushBytes int `mapstructure:"flush-bytes"`
+	// FlushFrequency is the interval at which producer batches are flushed.
+	FlushFrequency time.Duration `mapstructure:"flush-frequency"`
+	// MaxRetries is the number of times the producer retries sending the message.
+	MaxRetries int `mapstructure:"max-retries"`
+	// Timeout is the network dial, read, and write timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// SASLMechanism is the SASL authentication mechanism. Can be plain, scram-sha-256, or scram-sha-512.
+	SASLMechanism string `mapstructure:"sasl-mechanism"`
+	// Username is the SASL authentication username.
+	Username string `mapstructure:"username"`
+	// Password is the SASL authentication password.
+	Password string `mapstructure:"password"`
+	// TLSEnabled indicates if the connection to brokers is established over TLS. TLS
+	// is implicitly enabled when any of the certificate files is specified.
+	TLSEnabled bool `mapstructure:"tls-enabled"`
+	// Serializer indicates the serializer for the message value.
+	Serializer outputs.Serializer `mapstructure:"serializer"`
+}
+
+// AddFlags registers persistent flags for the Kafka output.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(kafkaEnabled, false, "Determines whether the Kafka output is enabled")
+	flags.StringSlice(kafkaBrokers, []string{"localhost:9092"}, "Contains the list of Kafka bootstrap brokers in the host:port format")
+	flags.String(kafkaTopic, "fibratus", "Specifies the template that resolves the destination topic for each event, e.g. fibratus-{{ .Category }}")
+	flags.String(kafkaPartitionKey, string(RoundRobin), "Determines how events are distributed across topic partitions. Possible values are host, ps.uuid, and round-robin")
+	flags.String(kafkaClientID, "fibratus", "Specifies the identifier of the producer sent to brokers")
+	flags.String(kafkaVersion, "2.1.0", "Specifies the Kafka protocol version assumed by the producer")
+	flags.String(kafkaCompression, "snappy", "Specifies the message compression codec. Possible values are none, gzip, snappy, lz4, and zstd")
+	flags.String(kafkaRequiredAcks, "all", "Specifies the level of acknowledgement reliability. Possible values are none, leader, and all")
+	flags.Bool(kafkaIdempotent, false, "Enables the idempotent producer that guarantees exactly one copy of each message is written")
+	flags.Int(kafkaMaxMessageBytes, 1000000, "Specifies the maximum permitted size of the message")
+	flags.Int(kafkaFlushMessages, 500, "Specifies the number of messages that triggers the flush of the producer batch")
+	flags.Int(kafkaFlushBytes, 1048576, "Specifies the size of the producer batch in bytes that triggers the flush")
+	flags.Duration(kafkaFlushFrequency, time.Millisecond*500, "Specifies the interval at which producer batches are flushed")
+	flags.Int(kafkaMaxRetries, 3, "Specifies the number of times the producer retries sending the message")
+	flags.Duration(kafkaTimeout, time.Second*10, "Specifies the network dial, read, and write timeout")
+	flags.String(kafkaSASLMechanism, "", "Specifies the SASL authentication mechanism. Possible values are plain, scram-sha-256, and scram-sha-512")
+	flags.String(kafkaUsername, "", "Specifies the SASL authentication username")
+	flags.String(kafkaPassword, "", "Specifies the SASL authentication password")
+	flags.Bool(kafkaTLSEnabled, false, "Indicates if the connection to brokers is established over TLS")
+	flags.String(kafkaSerializer, string(outputs.JSON), "Indicates the event serializer type")
+	outputs.AddTLSFlags(flags, outputs.Kafka)
+}
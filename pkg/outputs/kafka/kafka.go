@@ -0,0 +1,319 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kafka
+
+import (
+	"bytes"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/IBM/sarama"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/rabbitstack/fibratus/pkg/util/tls"
+)
+
+var (
+	// kafkaErrors counts Kafka delivery errors
+	kafkaErrors = expvar.NewInt("output.kafka.publish.errors")
+	// kafkaMessages counts the total number of published messages
+	kafkaMessages = expvar.NewInt("output.kafka.publish.messages")
+)
+
+// topicData is the data that is available to the topic template.
+type topicData struct {
+	// Category is the event category, e.g. process or file.
+	Category string
+	// Name is the event name, e.g. CreateProcess.
+	Name string
+	// Host is the name of the host where the event originated.
+	Host string
+}
+
+type kafka struct {
+	config   Config
+	sconfig  *sarama.Config
+	topic    *template.Template
+	topics   map[topicData]string
+	producer sarama.SyncProducer
+}
+
+func init() {
+	outputs.Register(outputs.Kafka, initKafka)
+}
+
+func initKafka(config outputs.Config) (outputs.OutputGroup, error) {
+	cfg, ok := config.Output.(Config)
+	if !ok {
+		return outputs.Fail(outputs.ErrInvalidConfig(outputs.Kafka, config.Output))
+	}
+	k, err := newKafka(cfg)
+	if err != nil {
+		return outputs.Fail(err)
+	}
+	return outputs.Success(k), nil
+}
+
+func newKafka(cfg Config) (*kafka, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("at least one Kafka broker is required")
+	}
+	if err := cfg.Serializer.Validate(outputs.Kafka); err != nil {
+		return nil, err
+	}
+	topic, err := template.New("topic").Option("missingkey=error").Parse(cfg.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kafka topic template: %v", err)
+	}
+	sconfig, err := newSaramaConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &kafka{
+		config:  cfg,
+		sconfig: sconfig,
+		topic:   topic,
+		topics:  make(map[topicData]string),
+	}, nil
+}
+
+// newSaramaConfig translates the output config to the producer config.
+func newSaramaConfig(cfg Config) (*sarama.Config, error) {
+	c := sarama.NewConfig()
+	if cfg.ClientID != "" {
+		c.ClientID = cfg.ClientID
+	}
+	if cfg.Version != "" {
+		version, err := sarama.ParseKafkaVersion(cfg.Version)
+		if err != nil {
+			return nil, err
+		}
+		c.Version = version
+	}
+	if cfg.Timeout > 0 {
+		c.Net.DialTimeout = cfg.Timeout
+		c.Net.ReadTimeout = cfg.Timeout
+		c.Net.WriteTimeout = cfg.Timeout
+	}
+
+	// the sync producer requires successes to be returned
+	c.Producer.Return.Successes = true
+	c.Producer.Return.Errors = true
+	c.Producer.Retry.Max = cfg.MaxRetries
+	if cfg.MaxMessageBytes > 0 {
+		c.Producer.MaxMessageBytes = cfg.MaxMessageBytes
+	}
+	c.Producer.Flush.Messages = cfg.FlushMessages
+	c.Producer.Flush.Bytes = cfg.FlushBytes
+	c.Producer.Flush.Frequency = cfg.FlushFrequency
+
+	switch cfg.PartitionKey {
+	case RoundRobin, "":
+		c.Producer.Partitioner = sarama.NewRoundRobinPartitioner
+	case Host, PsUUID:
+		c.Producer.Partitioner = sarama.NewHashPartitioner
+	default:
+		return nil, fmt.Errorf("unknown Kafka partition key %q", cfg.PartitionKey)
+	}
+
+	switch cfg.Compression {
+	case "none", "":
+		c.Producer.Compression = sarama.CompressionNone
+	case "gzip":
+		c.Producer.Compression = sarama.CompressionGZIP
+	case "snappy":
+		c.Producer.Compression = sarama.CompressionSnappy
+	case "lz4":
+		c.Producer.Compression = sarama.CompressionLZ4
+	case "zstd":
+		c.Producer.Compression = sarama.CompressionZSTD
+	default:
+		return nil, fmt.Errorf("unknown Kafka compression codec %q", cfg.Compression)
+	}
+
+	switch cfg.RequiredAcks {
+	case "none":
+		c.Producer.RequiredAcks = sarama.NoResponse
+	case "leader":
+		c.Producer.RequiredAcks = sarama.WaitForLocal
+	case "all", "":
+		c.Producer.RequiredAcks = sarama.WaitForAll
+	default:
+		return nil, fmt.Errorf("unknown Kafka required acks %q", cfg.RequiredAcks)
+	}
+
+	// the idempotent producer requires acknowledgements
+	// from all in-sync replicas and at most one in-flight
+	// request per broker connection to preserve ordering
+	if cfg.Idempotent {
+		c.Producer.Idempotent = true
+		c.Producer.RequiredAcks = sarama.WaitForAll
+		c.Net.MaxOpenRequests = 1
+		if c.Producer.Retry.Max < 1 {
+			c.Producer.Retry.Max = 1
+		}
+	}
+
+	switch strings.ToLower(cfg.SASLMechanism) {
+	case "":
+	case "plain":
+		c.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case "scram-sha-256":
+		c.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		c.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha256Generator} }
+	case "scram-sha-512":
+		c.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		c.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha512Generator} }
+	default:
+		return nil, fmt.Errorf("unknown Kafka SASL mechanism %q", cfg.SASLMechanism)
+	}
+	if c.Net.SASL.Mechanism != "" {
+		c.Net.SASL.Enable = true
+		c.Net.SASL.User = cfg.Username
+		c.Net.SASL.Password = cfg.Password
+	}
+
+	tlsConfig, err := tls.MakeConfig(cfg.TLSCert, cfg.TLSKey, cfg.TLSCA, cfg.TLSInsecureSkipVerify)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS config: %v", err)
+	}
+	if tlsConfig != nil || cfg.TLSEnabled {
+		c.Net.TLS.Enable = true
+		c.Net.TLS.Config = tlsConfig
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Kafka producer config: %v", err)
+	}
+
+	return c, nil
+}
+
+func (k *kafka) Connect() error {
+	if k.producer != nil {
+		_ = k.producer.Close()
+		k.producer = nil
+	}
+	producer, err := sarama.NewSyncProducer(k.config.Brokers, k.sconfig)
+	if err != nil {
+		return err
+	}
+	k.producer = producer
+	return nil
+}
+
+func (k *kafka) Close() error {
+	if k.producer == nil {
+		return nil
+	}
+	return k.producer.Close()
+}
+
+// Publish produces a message for each event in the batch. Messages are
+// accumulated in producer batches according to the flush settings and
+// compressed with the configured codec. If the producer is unable to
+// reach the brokers, the error is wrapped with outputs.ErrConnectionLost
+// to signal the aggregator worker to reconnect.
+func (k *kafka) Publish(batch *event.Batch) error {
+	if k.producer == nil {
+		return fmt.Errorf("%w: producer is not connected", outputs.ErrConnectionLost)
+	}
+	msgs := make([]*sarama.ProducerMessage, 0, len(batch.Events))
+	for _, evt := range batch.Events {
+		topic, err := k.resolveTopic(evt)
+		if err != nil {
+			return err
+		}
+		value, err := k.config.Serializer.Marshal(evt)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic:     topic,
+			Key:       k.partitionKey(evt),
+			Value:     sarama.ByteEncoder(value),
+			Timestamp: evt.Timestamp,
+		})
+	}
+
+	err := k.producer.SendMessages(msgs)
+	if err == nil {
+		kafkaMessages.Add(int64(len(msgs)))
+		return nil
+	}
+
+	var errs sarama.ProducerErrors
+	if !errors.As(err, &errs) || len(errs) == 0 {
+		kafkaErrors.Add(int64(len(msgs)))
+		return err
+	}
+	kafkaErrors.Add(int64(len(errs)))
+	kafkaMessages.Add(int64(len(msgs) - len(errs)))
+	err = fmt.Errorf("%d of %d messages failed: %v", len(errs), len(msgs), errs[0].Err)
+	if isConnectionLost(errs[0].Err) {
+		return fmt.Errorf("%w: %v", outputs.ErrConnectionLost, err)
+	}
+	return err
+}
+
+// resolveTopic renders the topic template for the event.
+// Rendered topics are cached, as the number of distinct
+// template inputs is bounded by categories and event names.
+func (k *kafka) resolveTopic(evt *event.Event) (string, error) {
+	data := topicData{Category: string(evt.Category), Name: evt.Name, Host: evt.Host}
+	if topic, ok := k.topics[data]; ok {
+		return topic, nil
+	}
+	var b bytes.Buffer
+	if err := k.topic.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("unable to resolve Kafka topic: %v", err)
+	}
+	topic := b.String()
+	k.topics[data] = topic
+	return topic, nil
+}
+
+// partitionKey returns the message key that determines the
+// destination partition. Messages without the key are evenly
+// distributed across partitions.
+func (k *kafka) partitionKey(evt *event.Event) sarama.Encoder {
+	switch k.config.PartitionKey {
+	case Host:
+		return sarama.StringEncoder(evt.Host)
+	case PsUUID:
+		if evt.PS != nil {
+			return sarama.StringEncoder(strconv.FormatUint(evt.PS.UUID(), 10))
+		}
+	}
+	return nil
+}
+
+// isConnectionLost determines if the producer error is caused by unreachable brokers.
+func isConnectionLost(err error) bool {
+	var nerr net.Error
+	return errors.Is(err, sarama.ErrOutOfBrokers) ||
+		errors.Is(err, sarama.ErrNotConnected) ||
+		errors.Is(err, sarama.ErrClosedClient) ||
+		errors.As(err, &nerr)
+}
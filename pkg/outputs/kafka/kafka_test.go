@@ -0,0 +1,214 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kafka
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rabbitstack/fibratus/pkg/event/eventtest"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func config(brokers ...string) Config {
+	return Config{
+		Enabled:        true,
+		Brokers:        brokers,
+		Topic:          "fibratus-{{ .Category }}",
+		PartitionKey:   RoundRobin,
+		Version:        "2.1.0",
+		Compression:    "zstd",
+		RequiredAcks:   "all",
+		FlushMessages:  10,
+		FlushFrequency: time.Millisecond * 10,
+		Timeout:        time.Second * 5,
+	}
+}
+
+func newMockBroker(t *testing.T, produce *sarama.MockProduceResponse) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("fibratus-process", 0, broker.BrokerID()).
+			SetLeader("fibratus-net", 0, broker.BrokerID()),
+		"ProduceRequest": produce,
+	})
+	return broker
+}
+
+func TestPublish(t *testing.T) {
+	broker := newMockBroker(t, sarama.NewMockProduceResponse(t))
+	defer broker.Close()
+
+	k, err := newKafka(config(broker.Addr()))
+	require.NoError(t, err)
+	require.NoError(t, k.Connect())
+	defer k.Close()
+
+	messages := kafkaMessages.Value()
+	require.NoError(t, k.Publish(eventtest.Batch("create-process", "connect")))
+	assert.Equal(t, int64(2), kafkaMessages.Value()-messages)
+
+	var produces int
+	for _, rr := range broker.History() {
+		if _, ok := rr.Request.(*sarama.ProduceRequest); ok {
+			produces++
+		}
+	}
+	assert.True(t, produces > 0)
+}
+
+func TestPublishError(t *testing.T) {
+	produce := sarama.NewMockProduceResponse(t).
+		SetError("fibratus-process", 0, sarama.ErrMessageSizeTooLarge)
+	broker := newMockBroker(t, produce)
+	defer broker.Close()
+
+	cfg := config(broker.Addr())
+	cfg.MaxRetries = 0
+	k, err := newKafka(cfg)
+	require.NoError(t, err)
+	require.NoError(t, k.Connect())
+	defer k.Close()
+
+	errs := kafkaErrors.Value()
+	err = k.Publish(eventtest.Batch("create-process", "connect"))
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, outputs.ErrConnectionLost))
+	assert.Equal(t, int64(1), kafkaErrors.Value()-errs)
+}
+
+func TestPublishNotConnected(t *testing.T) {
+	k, err := newKafka(config("127.0.0.1:9092"))
+	require.NoError(t, err)
+	assert.True(t, errors.Is(k.Publish(eventtest.Batch("create-process", "connect")), outputs.ErrConnectionLost))
+}
+
+func TestResolveTopic(t *testing.T) {
+	var tests = []struct {
+		topic    string
+		expected []string
+	}{
+		{"fibratus", []string{"fibratus", "fibratus"}},
+		{"fibratus-{{ .Category }}", []string{"fibratus-process", "fibratus-net"}},
+		{"{{ .Host }}.{{ .Category }}.{{ .Name | printf \"%s\" }}", []string{"archrabbit.process.CreateProcess", "archrabbit.net.Connect"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			cfg := config("127.0.0.1:9092")
+			cfg.Topic = tt.topic
+			k, err := newKafka(cfg)
+			require.NoError(t, err)
+			for i, evt := range eventtest.Batch("create-process", "connect").Events {
+				topic, err := k.resolveTopic(evt)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected[i], topic)
+			}
+		})
+	}
+
+	cfg := config("127.0.0.1:9092")
+	cfg.Topic = "fibratus-{{ .Category"
+	_, err := newKafka(cfg)
+	require.Error(t, err)
+}
+
+func TestPartitionKey(t *testing.T) {
+	evts := eventtest.Batch("create-process", "connect").Events
+
+	var tests = []struct {
+		key      PartitionKey
+		expected []sarama.Encoder
+	}{
+		{RoundRobin, []sarama.Encoder{nil, nil}},
+		{Host, []sarama.Encoder{sarama.StringEncoder("archrabbit"), sarama.StringEncoder("archrabbit")}},
+		{PsUUID, []sarama.Encoder{sarama.StringEncoder(strconv.FormatUint(evts[0].PS.UUID(), 10)), sarama.StringEncoder(strconv.FormatUint(evts[1].PS.UUID(), 10))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.key), func(t *testing.T) {
+			cfg := config("127.0.0.1:9092")
+			cfg.PartitionKey = tt.key
+			k, err := newKafka(cfg)
+			require.NoError(t, err)
+			for i, evt := range evts {
+				assert.Equal(t, tt.expected[i], k.partitionKey(evt))
+			}
+		})
+	}
+}
+
+func TestProducerConfig(t *testing.T) {
+	cfg := config("127.0.0.1:9092")
+	cfg.Idempotent = true
+	cfg.RequiredAcks = "leader"
+	cfg.Compression = "lz4"
+	cfg.SASLMechanism = "SCRAM-SHA-512"
+	cfg.Username = "fibratus"
+	cfg.Password = "secret"
+	cfg.TLSEnabled = true
+
+	c, err := newSaramaConfig(cfg)
+	require.NoError(t, err)
+	assert.True(t, c.Producer.Idempotent)
+	assert.Equal(t, sarama.WaitForAll, c.Producer.RequiredAcks)
+	assert.Equal(t, 1, c.Net.MaxOpenRequests)
+	assert.Equal(t, sarama.CompressionLZ4, c.Producer.Compression)
+	assert.True(t, c.Net.SASL.Enable)
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512), c.Net.SASL.Mechanism)
+	assert.NotNil(t, c.Net.SASL.SCRAMClientGeneratorFunc)
+	assert.True(t, c.Net.TLS.Enable)
+
+	var tests = []struct {
+		name string
+		cfg  func(*Config)
+	}{
+		{"compression", func(c *Config) { c.Compression = "brotli" }},
+		{"required acks", func(c *Config) { c.RequiredAcks = "some" }},
+		{"partition key", func(c *Config) { c.PartitionKey = "ps.pid" }},
+		{"sasl mechanism", func(c *Config) { c.SASLMechanism = "gssapi" }},
+		{"version", func(c *Config) { c.Version = "x.y" }},
+		{"idempotent version", func(c *Config) { c.Version = "0.10.2.0"; c.Idempotent = true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config("127.0.0.1:9092")
+			tt.cfg(&cfg)
+			_, err := newSaramaConfig(cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSCRAMClient(t *testing.T) {
+	c := &scramClient{hashGenerator: sha256Generator}
+	require.NoError(t, c.Begin("fibratus", "secret", ""))
+	msg, err := c.Step("")
+	require.NoError(t, err)
+	assert.Contains(t, msg, "n=fibratus")
+	assert.False(t, c.Done())
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otlp
+
+import (
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/spf13/pflag"
+)
+
+// Protocol is the OTLP transport protocol.
+type Protocol string
+
+const (
+	// GRPC exports log records via OTLP/gRPC.
+	GRPC Protocol = "grpc"
+	// HTTPProtobuf exports log records via OTLP/HTTP in binary protobuf encoding.
+	HTTPProtobuf Protocol = "http/protobuf"
+	// HTTPJSON exports log records via OTLP/HTTP in JSON protobuf encoding.
+	HTTPJSON Protocol = "http/json"
+)
+
+const (
+	otlpEnabled            = "output.otlp.enabled"
+	otlpEndpoint           = "output.otlp.endpoint"
+	otlpProtocol           = "output.otlp.protocol"
+	otlpInsecure           = "output.otlp.insecure"
+	otlpServiceName        = "output.otlp.service-name"
+	otlpResourceAttributes = "output.otlp.resource-attributes"
+	otlpMaxBatchSize       = "output.otlp.max-batch-size"
+	otlpEnableGzip         = "output.otlp.enable-gzip"
+	otlpTimeout            = "output.otlp.timeout"
+	otlpMaxRetries         = "output.otlp.max-retries"
+	otlpRetryInterval      = "output.otlp.retry-interval"
+	otlpMaxRetryInterval   = "output.otlp.max-retry-interval"
+	otlpSerializer         = "output.otlp.serializer"
+)
+
+const (
+	// defaultGRPCEndpoint is the default OTLP/gRPC collector address
+	defaultGRPCEndpoint = "localhost:4317"
+	// defaultHTTPEndpoint is the default OTLP/HTTP collector URL
+	defaultHTTPEndpoint = "http://localhost:4318"
+)
+
+// Config contains the options for tweaking the OTLP output behaviour.
+type Config struct {
+	outputs.TLSConfig
+	// Enabled determines whether OTLP output is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the address of the OpenTelemetry Collector. For the gRPC
+	// protocol, the endpoint is given in the host:port form. For the HTTP
+	// protocols, the endpoint is the base URL to which the /v1/logs path
+	// is appended unless the URL already contains the path.
+	Endpoint string `mapstructure:"endpoint"`
+	// Protocol is the transport protocol. Can be grpc, http/protobuf, or http/json.
+	Protocol Protocol `mapstructure:"protocol"`
+	// Insecure indicates if the gRPC connection is established without transport security.
+	Insecure bool `mapstructure:"insecure"`
+	// Headers represents additional HTTP headers or gRPC metadata sent with each export request.
+	Headers map[string]string `mapstructure:"headers"`
+	// ServiceName is the value of the service.name resource attribute.
+	ServiceName string `mapstructure:"service-name"`
+	// ResourceAttributes contains additional resource attributes in the key=value form.
+	ResourceAttributes []string `mapstructure:"resource-attributes"`
+	// MaxBatchSize is the maximum number of log records in a single export request.
+	MaxBatchSize int `mapstructure:"max-batch-size"`
+	// EnableGzip specifies whether the gzip compression is enabled.
+	EnableGzip bool `mapstructure:"enable-gzip"`
+	// Timeout represents the timeout for the export requests.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxRetries is the number of times the export request is retried on transient errors.
+	MaxRetries int `mapstructure:"max-retries"`
+	// RetryInterval is the initial wait time before retrying the export request.
+	RetryInterval time.Duration `mapstructure:"retry-interval"`
+	// MaxRetryInterval is the upper bound of the wait time between retries.
+	MaxRetryInterval time.Duration `mapstructure:"max-retry-interval"`
+	// Serializer indicates the serializer for the log record body. If
+	// empty, the body contains the event description.
+	Serializer outputs.Serializer `mapstructure:"serializer"`
+}
+
+// endpoint returns the configured endpoint or the default
+// endpoint address for the transport protocol.
+func (c Config) endpoint() string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	if c.Protocol == GRPC {
+		return defaultGRPCEndpoint
+	}
+	return defaultHTTPEndpoint
+}
+
+// AddFlags registers persistent flags for the OTLP output.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(otlpEnabled, false, "Determines whether the OTLP output is enabled")
+	flags.String(otlpEndpoint, "", "Specifies the address of the OpenTelemetry Collector. Defaults to localhost:4317 for gRPC and http://localhost:4318 for HTTP protocols")
+	flags.String(otlpProtocol, string(GRPC), "Specifies the transport protocol. Possible values are grpc, http/protobuf, and http/json")
+	flags.Bool(otlpInsecure, false, "Indicates if the gRPC connection is established without transport security")
+	flags.String(otlpServiceName, "fibratus", "Specifies the value of the service.name resource attribute")
+	flags.StringSlice(otlpResourceAttributes, []string{}, "A comma-separated list of additional resource attributes in the key=value form")
+	flags.Int(otlpMaxBatchSize, 512, "Specifies the maximum number of log records in a single export request")
+	flags.Bool(otlpEnableGzip, false, "Indicates whether the gzip compression is enabled")
+	flags.Duration(otlpTimeout, time.Second*10, "Represents the timeout for the export requests")
+	flags.Int(otlpMaxRetries, 5, "Specifies the number of times the export request is retried on transient errors")
+	flags.Duration(otlpRetryInterval, time.Second, "Specifies the initial wait time before retrying the export request")
+	flags.Duration(otlpMaxRetryInterval, time.Second*30, "Specifies the upper bound of the wait time between retries")
+	flags.String(otlpSerializer, "", "Indicates the event serializer type for the log record body. If empty, the body contains the event description")
+	outputs.AddTLSFlags(flags, outputs.OTLP)
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otlp
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"fmt"
+
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/rabbitstack/fibratus/pkg/util/tls"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcExporter exports log records via OTLP/gRPC.
+type grpcExporter struct {
+	conn   *grpc.ClientConn
+	client collogspb.LogsServiceClient
+	md     metadata.MD
+}
+
+func newGRPCExporter(cfg Config) (*grpcExporter, error) {
+	creds := insecure.NewCredentials()
+	if !cfg.Insecure {
+		tlsConfig, err := tls.MakeConfig(cfg.TLSCert, cfg.TLSKey, cfg.TLSCA, cfg.TLSInsecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS config: %v", err)
+		}
+		if tlsConfig == nil {
+			tlsConfig = &gotls.Config{InsecureSkipVerify: cfg.TLSInsecureSkipVerify}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if cfg.EnableGzip {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
+	}
+	conn, err := grpc.NewClient(cfg.endpoint(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpcExporter{
+		conn:   conn,
+		client: collogspb.NewLogsServiceClient(conn),
+		md:     metadata.New(cfg.Headers),
+	}, nil
+}
+
+func (e *grpcExporter) export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	if len(e.md) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, e.md)
+	}
+	resp, err := e.client.Export(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return resp, nil
+}
+
+func (e *grpcExporter) close() error { return e.conn.Close() }
+
+// grpcError determines whether the gRPC status is retryable
+// according to the OTLP specification. ResourceExhausted is
+// only retried if the server signals the recovery is possible
+// by providing the retry information. InvalidArgument signals
+// the collector rejected the malformed request.
+func grpcError(err error) error {
+	st := status.Convert(err)
+	var retryInfo *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			retryInfo = ri
+		}
+	}
+	switch st.Code() {
+	case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+	case codes.ResourceExhausted:
+		if retryInfo == nil {
+			return err
+		}
+	case codes.InvalidArgument:
+		return outputs.Permanent(err)
+	default:
+		return err
+	}
+	rerr := &retryableError{err: err}
+	if retryInfo != nil {
+		rerr.throttle = retryInfo.GetRetryDelay().AsDuration()
+	}
+	return rerr
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/rabbitstack/fibratus/pkg/util/tls"
+	"github.com/rabbitstack/fibratus/pkg/util/version"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// logsPath is the default OTLP/HTTP logs path
+const logsPath = "/v1/logs"
+
+// userAgentHeader represents the value of the User-Agent header
+var userAgentHeader = version.ProductToken()
+
+// httpExporter exports log records via OTLP/HTTP.
+type httpExporter struct {
+	client  *http.Client
+	url     string
+	json    bool
+	gzip    bool
+	headers map[string]string
+}
+
+func newHTTPExporter(cfg Config) (*httpExporter, error) {
+	u, err := url.Parse(cfg.endpoint())
+	if err != nil {
+		return nil, err
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = logsPath
+	}
+
+	tlsConfig, err := tls.MakeConfig(cfg.TLSCert, cfg.TLSKey, cfg.TLSCA, cfg.TLSInsecureSkipVerify)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS config: %v", err)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+			Proxy:           http.ProxyFromEnvironment,
+		},
+	}
+
+	return &httpExporter{
+		client:  client,
+		url:     u.String(),
+		json:    cfg.Protocol == HTTPJSON,
+		gzip:    cfg.EnableGzip,
+		headers: cfg.Headers,
+	}, nil
+}
+
+func (e *httpExporter) export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	body, err := e.marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range e.headers {
+		r.Header.Set(k, v)
+	}
+	r.Header.Set("User-Agent", userAgentHeader)
+	if e.json {
+		r.Header.Set("Content-Type", "application/json")
+	} else {
+		r.Header.Set("Content-Type", "application/x-protobuf")
+	}
+	if e.gzip {
+		r.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := e.client.Do(r)
+	if err != nil {
+		return nil, &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &retryableError{err: err}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var res collogspb.ExportLogsServiceResponse
+		if len(b) > 0 {
+			if err := e.unmarshal(b, &res); err != nil {
+				return nil, fmt.Errorf("invalid OTLP export response: %v", err)
+			}
+		}
+		return &res, nil
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		rerr := &retryableError{err: fmt.Errorf("OTLP export request failed with %d status code", resp.StatusCode)}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			rerr.throttle = time.Duration(secs) * time.Second
+		}
+		return nil, rerr
+	default:
+		err := fmt.Errorf("OTLP export request failed with %d status code: %s", resp.StatusCode, string(b))
+		if outputs.IsPermanentStatus(resp.StatusCode) {
+			return nil, outputs.Permanent(err)
+		}
+		return nil, err
+	}
+}
+
+func (e *httpExporter) close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// marshal encodes the export request in binary or JSON protobuf
+// encoding and optionally compresses the payload. OTLP/JSON
+// requires enumerations to be encoded as integers.
+func (e *httpExporter) marshal(req *collogspb.ExportLogsServiceRequest) ([]byte, error) {
+	var (
+		body []byte
+		err  error
+	)
+	if e.json {
+		body, err = protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(req)
+	} else {
+		body, err = proto.Marshal(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !e.gzip {
+		return body, nil
+	}
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	if _, err := gz.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (e *httpExporter) unmarshal(b []byte, res *collogspb.ExportLogsServiceResponse) error {
+	if e.json {
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(b, res)
+	}
+	return proto.Unmarshal(b, res)
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otlp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	"github.com/rabbitstack/fibratus/pkg/util/version"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// scopeName is the instrumentation scope name of the emitted log records
+const scopeName = "github.com/rabbitstack/fibratus"
+
+// paramAttributes maps event parameters to semantic convention attributes.
+// Parameters without the mapping are emitted under the fibratus.param
+// namespace.
+var paramAttributes = map[string]string{
+	"file_path": "file.path",
+	"sip":       "source.address",
+	"sport":     "source.port",
+	"dip":       "destination.address",
+	"dport":     "destination.port",
+	"l4_proto":  "network.transport",
+}
+
+// severity resolves the severity of the log record. Events
+// tagged by rules get the severity derived from the rule
+// severity, while the rest of events are informational.
+func severity(evt *event.Event) (logspb.SeverityNumber, string) {
+	if !evt.ContainsMeta(event.RuleNameKey) {
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO, "INFO"
+	}
+	switch strings.ToLower(evt.GetMetaAsString(event.RuleSeverityKey)) {
+	case "medium":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, "ERROR"
+	case "high":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR3, "ERROR3"
+	case "critical":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL, "FATAL"
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN, "WARN"
+	}
+}
+
+// logRecord converts the event to the OTLP log record. Process
+// state and event parameters are mapped to attributes following
+// OpenTelemetry semantic conventions where applicable.
+func logRecord(evt *event.Event, observed time.Time) *logspb.LogRecord {
+	sevnum, sevtext := severity(evt)
+	body := evt.Description
+	if body == "" {
+		body = evt.Name
+	}
+
+	attrs := []*commonpb.KeyValue{
+		str("event.name", evt.Name),
+		str("fibratus.event.category", string(evt.Category)),
+		integer("fibratus.event.seq", int64(evt.Seq)),
+		integer("fibratus.event.cpu", int64(evt.CPU)),
+		integer("process.pid", int64(evt.PID)),
+		integer("thread.id", int64(evt.Tid)),
+	}
+
+	if ps := evt.PS; ps != nil {
+		attrs = append(attrs,
+			integer("process.parent_pid", int64(ps.Ppid)),
+			str("process.executable.name", ps.Name),
+			str("process.executable.path", ps.Exe),
+			str("process.command_line", ps.Cmdline),
+		)
+		if ps.Cwd != "" {
+			attrs = append(attrs, str("process.working_directory", ps.Cwd))
+		}
+		if ps.Username != "" {
+			owner := ps.Username
+			if ps.Domain != "" {
+				owner = ps.Domain + "\\" + ps.Username
+			}
+			attrs = append(attrs, str("process.owner", owner))
+		}
+		if ps.SID != "" {
+			attrs = append(attrs, str("fibratus.process.sid", ps.SID))
+		}
+		if !ps.StartTime.IsZero() {
+			attrs = append(attrs, str("process.creation.time", ps.StartTime.UTC().Format(time.RFC3339Nano)))
+		}
+	}
+
+	if rule := evt.GetMetaAsString(event.RuleNameKey); rule != "" {
+		attrs = append(attrs, str("security_rule.name", rule))
+	}
+	if rule := evt.GetMetaAsString(event.RuleSeverityKey); rule != "" {
+		attrs = append(attrs, str("fibratus.rule.severity", rule))
+	}
+
+	for _, name := range paramNames(evt) {
+		attrs = append(attrs, param(evt.Params[name]))
+	}
+
+	for _, k := range metaKeys(evt) {
+		attrs = append(attrs, str("fibratus.meta."+k, fmt.Sprintf("%v", evt.Metadata[event.MetadataKey(k)])))
+	}
+
+	return &logspb.LogRecord{
+		TimeUnixNano:         uint64(evt.Timestamp.UnixNano()),
+		ObservedTimeUnixNano: uint64(observed.UnixNano()),
+		SeverityNumber:       sevnum,
+		SeverityText:         sevtext,
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: body}},
+		Attributes:           attrs,
+	}
+}
+
+// param converts the event parameter to the attribute. Numeric
+// parameters and ports without flags or enumerations retain the
+// integer value, while the rest of parameters are rendered as strings.
+func param(par *event.Param) *commonpb.KeyValue {
+	key, ok := paramAttributes[par.Name]
+	if !ok {
+		key = "fibratus.param." + par.Name
+	}
+	if (par.IsNumber() || par.Type == params.Port) && par.Flags == nil && par.Enum == nil {
+		switch v := par.Value.(type) {
+		case uint8:
+			return integer(key, int64(v))
+		case uint16:
+			return integer(key, int64(v))
+		case uint32:
+			return integer(key, int64(v))
+		case uint64:
+			return integer(key, int64(v))
+		case int8:
+			return integer(key, int64(v))
+		case int16:
+			return integer(key, int64(v))
+		case int32:
+			return integer(key, int64(v))
+		case int64:
+			return integer(key, v)
+		}
+	}
+	if v, ok := par.Value.(bool); ok {
+		return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}}
+	}
+	return str(key, par.String())
+}
+
+// resourceLogs groups log records by the originating host. Each
+// host is represented by a distinct resource. If the serializer
+// is specified, the log record body contains the serialized event.
+func (o *otlp) resourceLogs(evts []*event.Event) ([]*logspb.ResourceLogs, error) {
+	observed := time.Now()
+	rls := make([]*logspb.ResourceLogs, 0, 1)
+	hosts := make(map[string]*logspb.ScopeLogs)
+	for _, evt := range evts {
+		sl, ok := hosts[evt.Host]
+		if !ok {
+			sl = &logspb.ScopeLogs{
+				Scope: &commonpb.InstrumentationScope{Name: scopeName, Version: version.Get()},
+			}
+			hosts[evt.Host] = sl
+			rls = append(rls, &logspb.ResourceLogs{
+				Resource:  o.resource(evt.Host),
+				ScopeLogs: []*logspb.ScopeLogs{sl},
+			})
+		}
+		rec := logRecord(evt, observed)
+		if o.config.Serializer != "" {
+			b, err := o.config.Serializer.Marshal(evt)
+			if err != nil {
+				return nil, err
+			}
+			rec.Body = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: string(b)}}
+		}
+		sl.LogRecords = append(sl.LogRecords, rec)
+	}
+	return rls, nil
+}
+
+// resource builds the resource that describes the host.
+func (o *otlp) resource(host string) *resourcepb.Resource {
+	attrs := []*commonpb.KeyValue{
+		str("service.name", o.config.ServiceName),
+		str("service.version", version.Get()),
+		str("host.name", host),
+		str("os.type", "windows"),
+	}
+	for _, attr := range o.config.ResourceAttributes {
+		k, v, ok := strings.Cut(attr, "=")
+		if !ok || k == "" {
+			continue
+		}
+		attrs = append(attrs, str(strings.TrimSpace(k), strings.TrimSpace(v)))
+	}
+	return &resourcepb.Resource{Attributes: attrs}
+}
+
+// paramNames returns sorted event parameter names.
+func paramNames(evt *event.Event) []string {
+	names := make([]string, 0, len(evt.Params))
+	for name := range evt.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// metaKeys returns sorted metadata keys except the
+// rule keys that are mapped to dedicated attributes.
+func metaKeys(evt *event.Event) []string {
+	keys := make([]string, 0, len(evt.Metadata))
+	for k := range evt.Metadata {
+		if k == event.RuleNameKey || k == event.RuleSeverityKey {
+			continue
+		}
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func str(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func integer(key string, value int64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value}}}
+}
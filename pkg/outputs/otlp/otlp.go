@@ -0,0 +1,169 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otlp
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	log "github.com/sirupsen/logrus"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+)
+
+var (
+	// otlpErrors counts export errors
+	otlpErrors = expvar.NewInt("output.otlp.publish.errors")
+	// otlpRecords counts the total number of exported log records
+	otlpRecords = expvar.NewInt("output.otlp.publish.records")
+	// otlpRetries counts export requests retried due to transient errors
+	otlpRetries = expvar.NewInt("output.otlp.retries")
+	// otlpRejectedRecords counts log records rejected by the collector
+	otlpRejectedRecords = expvar.NewInt("output.otlp.rejected.records")
+)
+
+// exporter sends log records to the collector over the specific transport.
+type exporter interface {
+	export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error)
+	close() error
+}
+
+// retryableError signals the export request failed with
+// the transient error and can be retried. If the collector
+// instructs the client to throttle, the throttle field
+// contains the wait time before the next attempt.
+type retryableError struct {
+	err      error
+	throttle time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+type otlp struct {
+	config   Config
+	exporter exporter
+}
+
+func init() {
+	outputs.Register(outputs.OTLP, initOTLP)
+}
+
+func initOTLP(config outputs.Config) (outputs.OutputGroup, error) {
+	cfg, ok := config.Output.(Config)
+	if !ok {
+		return outputs.Fail(outputs.ErrInvalidConfig(outputs.OTLP, config.Output))
+	}
+	o, err := newOTLP(cfg)
+	if err != nil {
+		return outputs.Fail(err)
+	}
+	return outputs.Success(o), nil
+}
+
+func newOTLP(cfg Config) (*otlp, error) {
+	var (
+		exp exporter
+		err error
+	)
+	if err := cfg.Serializer.Validate(outputs.OTLP); err != nil {
+		return nil, err
+	}
+	switch cfg.Protocol {
+	case GRPC:
+		exp, err = newGRPCExporter(cfg)
+	case HTTPProtobuf, HTTPJSON:
+		exp, err = newHTTPExporter(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q. Use one of grpc, http/protobuf, or http/json", cfg.Protocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &otlp{config: cfg, exporter: exp}, nil
+}
+
+func (o *otlp) Connect() error { return nil }
+func (o *otlp) Close() error   { return o.exporter.close() }
+
+// Publish converts the batch of events to log records and exports
+// them to the collector. Log records are split into multiple export
+// requests if the batch exceeds the maximum batch size.
+func (o *otlp) Publish(batch *event.Batch) error {
+	evts := batch.Events
+	size := o.config.MaxBatchSize
+	if size <= 0 {
+		size = len(evts)
+	}
+	for len(evts) > 0 {
+		n := min(size, len(evts))
+		rls, err := o.resourceLogs(evts[:n])
+		if err != nil {
+			otlpErrors.Add(1)
+			return outputs.Permanent(err)
+		}
+		req := &collogspb.ExportLogsServiceRequest{ResourceLogs: rls}
+		if err := o.export(req); err != nil {
+			otlpErrors.Add(1)
+			return err
+		}
+		otlpRecords.Add(int64(n))
+		evts = evts[n:]
+	}
+	return nil
+}
+
+// export sends the export request and retries it on transient
+// errors. The wait time between attempts doubles on every retry
+// up to the maximum retry interval, unless the collector asks
+// to throttle for the specific period of time.
+func (o *otlp) export(req *collogspb.ExportLogsServiceRequest) error {
+	backoff := o.config.RetryInterval
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), o.config.Timeout)
+		resp, err := o.exporter.export(ctx, req)
+		cancel()
+		if err == nil {
+			if ps := resp.GetPartialSuccess(); ps != nil && ps.RejectedLogRecords > 0 {
+				otlpRejectedRecords.Add(ps.RejectedLogRecords)
+				log.Warnf("OTLP collector rejected %d log record(s): %s", ps.RejectedLogRecords, ps.ErrorMessage)
+			}
+			return nil
+		}
+		var rerr *retryableError
+		if !errors.As(err, &rerr) || attempt >= o.config.MaxRetries {
+			return err
+		}
+		wait := backoff
+		if rerr.throttle > 0 {
+			wait = rerr.throttle
+		}
+		otlpRetries.Add(1)
+		log.Debugf("OTLP export failed: %v. Retrying in %v...", err, wait)
+		time.Sleep(wait)
+		backoff *= 2
+		if o.config.MaxRetryInterval > 0 && backoff > o.config.MaxRetryInterval {
+			backoff = o.config.MaxRetryInterval
+		}
+	}
+}
@@ -0,0 +1,403 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otlp
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/eventtest"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func attributes(kvs []*commonpb.KeyValue) map[string]any {
+	attrs := make(map[string]any)
+	for _, kv := range kvs {
+		switch v := kv.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			attrs[kv.Key] = v.StringValue
+		case *commonpb.AnyValue_IntValue:
+			attrs[kv.Key] = v.IntValue
+		case *commonpb.AnyValue_BoolValue:
+			attrs[kv.Key] = v.BoolValue
+		}
+	}
+	return attrs
+}
+
+func records(req *collogspb.ExportLogsServiceRequest) []*logspb.LogRecord {
+	var recs []*logspb.LogRecord
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			recs = append(recs, sl.LogRecords...)
+		}
+	}
+	return recs
+}
+
+// collector is the OTLP/HTTP receiver stub.
+type collector struct {
+	mu       sync.Mutex
+	reqs     []*collogspb.ExportLogsServiceRequest
+	headers  []http.Header
+	failures int
+	response []byte
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r.URL.Path != logsPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if c.failures > 0 {
+		c.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var req collogspb.ExportLogsServiceRequest
+	if r.Header.Get("Content-Type") == "application/json" {
+		err = protojson.Unmarshal(b, &req)
+	} else {
+		err = proto.Unmarshal(b, &req)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.reqs = append(c.reqs, &req)
+	c.headers = append(c.headers, r.Header.Clone())
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(c.response)
+}
+
+func (c *collector) requests() []*collogspb.ExportLogsServiceRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reqs
+}
+
+func newConfig(endpoint string, protocol Protocol) Config {
+	return Config{
+		Endpoint:         endpoint,
+		Protocol:         protocol,
+		ServiceName:      "fibratus",
+		Timeout:          time.Second * 5,
+		MaxBatchSize:     512,
+		MaxRetries:       3,
+		RetryInterval:    time.Millisecond * 10,
+		MaxRetryInterval: time.Millisecond * 50,
+	}
+}
+
+func TestLogRecord(t *testing.T) {
+	batch := eventtest.Batch("create-process", "create-file", "connect", "virtual-alloc")
+	observed := time.Now()
+
+	rec := logRecord(batch.Events[0], observed)
+	assert.Equal(t, uint64(eventtest.Timestamp.UnixNano()), rec.TimeUnixNano)
+	assert.Equal(t, uint64(observed.UnixNano()), rec.ObservedTimeUnixNano)
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_ERROR3, rec.SeverityNumber)
+	assert.Equal(t, "ERROR3", rec.SeverityText)
+	assert.Equal(t, "Creates a new process and its primary thread", rec.Body.GetStringValue())
+
+	attrs := attributes(rec.Attributes)
+	assert.Equal(t, "CreateProcess", attrs["event.name"])
+	assert.Equal(t, "process", attrs["fibratus.event.category"])
+	assert.Equal(t, int64(10), attrs["fibratus.event.seq"])
+	assert.Equal(t, int64(2484), attrs["process.pid"])
+	assert.Equal(t, int64(768), attrs["process.parent_pid"])
+	assert.Equal(t, int64(3920), attrs["thread.id"])
+	assert.Equal(t, "cmd.exe", attrs["process.executable.name"])
+	assert.Equal(t, `C:\Windows\System32\cmd.exe`, attrs["process.executable.path"])
+	assert.Equal(t, `C:\Windows\System32\cmd.exe /c whoami`, attrs["process.command_line"])
+	assert.Equal(t, `ARCHRABBIT\admin`, attrs["process.owner"])
+	assert.Equal(t, "System discovery via whoami", attrs["security_rule.name"])
+	assert.NotContains(t, attrs, "fibratus.meta.rule.name")
+
+	rec = logRecord(batch.Events[1], observed)
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, rec.SeverityNumber)
+	assert.Equal(t, "CreateFile", rec.Body.GetStringValue())
+	assert.Equal(t, "bar", attributes(rec.Attributes)["fibratus.meta.foo"])
+
+	rec = logRecord(batch.Events[2], observed)
+	attrs = attributes(rec.Attributes)
+	assert.Equal(t, int64(443), attrs["destination.port"])
+	assert.Equal(t, "140.82.121.4", attrs["destination.address"])
+	assert.Equal(t, int64(0), attrs["fibratus.param.size"])
+
+	rec = logRecord(batch.Events[3], observed)
+	assert.NotContains(t, attributes(rec.Attributes), "process.parent_pid")
+}
+
+func TestSeverity(t *testing.T) {
+	var tests = []struct {
+		severity string
+		num      logspb.SeverityNumber
+	}{
+		{"", logspb.SeverityNumber_SEVERITY_NUMBER_WARN},
+		{"low", logspb.SeverityNumber_SEVERITY_NUMBER_WARN},
+		{"medium", logspb.SeverityNumber_SEVERITY_NUMBER_ERROR},
+		{"high", logspb.SeverityNumber_SEVERITY_NUMBER_ERROR3},
+		{"Critical", logspb.SeverityNumber_SEVERITY_NUMBER_FATAL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.severity, func(t *testing.T) {
+			evt := &event.Event{Metadata: map[event.MetadataKey]any{event.RuleNameKey: "Rule"}}
+			if tt.severity != "" {
+				evt.Metadata[event.RuleSeverityKey] = tt.severity
+			}
+			num, _ := severity(evt)
+			assert.Equal(t, tt.num, num)
+		})
+	}
+}
+
+func TestPublishHTTPProtobuf(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	cfg := newConfig(srv.URL, HTTPProtobuf)
+	cfg.Headers = map[string]string{"X-Api-Key": "secret"}
+	cfg.ResourceAttributes = []string{"deployment.environment=production"}
+	o, err := newOTLP(cfg)
+	require.NoError(t, err)
+	defer o.Close()
+
+	require.NoError(t, o.Publish(eventtest.Batch("create-process", "connect")))
+
+	reqs := c.requests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "application/x-protobuf", c.headers[0].Get("Content-Type"))
+	assert.Equal(t, "secret", c.headers[0].Get("X-Api-Key"))
+	require.Len(t, reqs[0].ResourceLogs, 1)
+
+	res := attributes(reqs[0].ResourceLogs[0].Resource.Attributes)
+	assert.Equal(t, "fibratus", res["service.name"])
+	assert.Equal(t, "archrabbit", res["host.name"])
+	assert.Equal(t, "windows", res["os.type"])
+	assert.Equal(t, "production", res["deployment.environment"])
+	assert.Equal(t, scopeName, reqs[0].ResourceLogs[0].ScopeLogs[0].Scope.Name)
+	assert.Len(t, records(reqs[0]), 2)
+}
+
+func TestPublishHTTPJSON(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b, err := io.ReadAll(gz)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"partialSuccess":{"rejectedLogRecords":"1","errorMessage":"record too large"}}`))
+	}))
+	defer srv.Close()
+
+	cfg := newConfig(srv.URL+"/otlp/v1/logs", HTTPJSON)
+	cfg.EnableGzip = true
+	o, err := newOTLP(cfg)
+	require.NoError(t, err)
+	defer o.Close()
+
+	rejected := otlpRejectedRecords.Value()
+	require.NoError(t, o.Publish(eventtest.Batch("create-process", "connect")))
+
+	assert.Contains(t, body, `"severityNumber":9`)
+	assert.Contains(t, body, `"severityNumber":19`)
+	assert.Contains(t, body, `"key":"process.executable.path"`)
+	assert.Equal(t, rejected+1, otlpRejectedRecords.Value())
+
+	var req collogspb.ExportLogsServiceRequest
+	require.NoError(t, protojson.Unmarshal([]byte(body), &req))
+	assert.Len(t, records(&req), 2)
+}
+
+func TestPublishHTTPRetry(t *testing.T) {
+	c := &collector{failures: 2}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	o, err := newOTLP(newConfig(srv.URL, HTTPProtobuf))
+	require.NoError(t, err)
+	defer o.Close()
+
+	retries := otlpRetries.Value()
+	require.NoError(t, o.Publish(eventtest.Batch("create-process", "connect")))
+	assert.Len(t, c.requests(), 1)
+	assert.Equal(t, retries+2, otlpRetries.Value())
+
+	c.failures = 5
+	require.Error(t, o.Publish(eventtest.Batch("create-process", "connect")))
+}
+
+func TestPublishHTTPNonRetryable(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	o, err := newOTLP(newConfig(srv.URL, HTTPProtobuf))
+	require.NoError(t, err)
+	defer o.Close()
+
+	err = o.Publish(eventtest.Batch("create-process", "connect"))
+	require.Error(t, err)
+	assert.True(t, outputs.IsPermanent(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestPublishMaxBatchSize(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	cfg := newConfig(srv.URL, HTTPProtobuf)
+	cfg.MaxBatchSize = 1
+	o, err := newOTLP(cfg)
+	require.NoError(t, err)
+	defer o.Close()
+
+	require.NoError(t, o.Publish(eventtest.Batch("create-process", "connect")))
+
+	reqs := c.requests()
+	require.Len(t, reqs, 2)
+	for _, req := range reqs {
+		assert.Len(t, records(req), 1)
+	}
+}
+
+// logsService is the OTLP/gRPC receiver stub.
+type logsService struct {
+	collogspb.UnimplementedLogsServiceServer
+	mu       sync.Mutex
+	reqs     []*collogspb.ExportLogsServiceRequest
+	md       []metadata.MD
+	failures int
+}
+
+func (s *logsService) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return nil, status.Error(codes.Unavailable, "collector is unavailable")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.reqs = append(s.reqs, req)
+	s.md = append(s.md, md)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (s *logsService) requests() []*collogspb.ExportLogsServiceRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reqs
+}
+
+func serveGRPC(t *testing.T, svc *logsService) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, svc)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(srv.Stop)
+	return l.Addr().String()
+}
+
+func TestPublishGRPC(t *testing.T) {
+	svc := &logsService{failures: 1}
+	addr := serveGRPC(t, svc)
+
+	cfg := newConfig(addr, GRPC)
+	cfg.Insecure = true
+	cfg.EnableGzip = true
+	cfg.Headers = map[string]string{"x-api-key": "secret"}
+
+	group, err := initOTLP(outputs.Config{Type: outputs.OTLP, Output: cfg})
+	require.NoError(t, err)
+	require.Len(t, group.Clients, 1)
+	o := group.Clients[0]
+	require.NoError(t, o.Connect())
+	defer o.Close()
+
+	require.NoError(t, o.Publish(eventtest.Batch("create-process", "connect")))
+
+	reqs := svc.requests()
+	require.Len(t, reqs, 1)
+	assert.Len(t, records(reqs[0]), 2)
+	assert.Equal(t, []string{"secret"}, svc.md[0].Get("x-api-key"))
+}
+
+func TestGRPCError(t *testing.T) {
+	var rerr *retryableError
+	assert.ErrorAs(t, grpcError(status.Error(codes.Unavailable, "unavailable")), &rerr)
+	assert.False(t, errors.As(grpcError(status.Error(codes.InvalidArgument, "invalid")), &rerr))
+	assert.False(t, errors.As(grpcError(status.Error(codes.ResourceExhausted, "exhausted")), &rerr))
+}
+
+func TestInvalidProtocol(t *testing.T) {
+	_, err := newOTLP(newConfig("localhost:4317", "http/xml"))
+	assert.ErrorContains(t, err, "unsupported OTLP protocol")
+}
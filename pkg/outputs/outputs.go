@@ -51,6 +51,16 @@ const (
 	Eventlog
 	// Null is the null output.
 	Null
+	// Syslog denotes the syslog output.
+	Syslog
+	// Kafka denotes the Kafka output.
+	Kafka
+	// Splunk denotes the Splunk HTTP Event Collector output.
+	Splunk
+	// OTLP denotes the OpenTelemetry logs output.
+	OTLP
+	// File denotes the rotating file output.
+	File
 	// Unknown is an undefined output type.
 	Unknown
 )
@@ -70,6 +80,16 @@ func (t Type) String() string {
 		return "eventlog"
 	case Null:
 		return "null"
+	case Syslog:
+		return "syslog"
+	case Kafka:
+		return "kafka"
+	case Splunk:
+		return "splunk"
+	case OTLP:
+		return "otlp"
+	case File:
+		return "file"
 	default:
 		return "unknown"
 	}
@@ -90,6 +110,16 @@ func TypeFromString(s string) Type {
 		return Eventlog
 	case "null":
 		return Null
+	case "syslog":
+		return Syslog
+	case "kafka":
+		return Kafka
+	case "splunk":
+		return Splunk
+	case "otlp":
+		return OTLP
+	case "file":
+		return File
 	default:
 		return Unknown
 	}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package outputs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sampling is the policy that reduces the volume of events forwarded to the
+// output. Events matched by rules are never dropped by the policy.
+type Sampling struct {
+	// Rates maps event categories or event names to the percentage of events
+	// forwarded to the output. Event names take precedence over categories.
+	// Events without the rate are forwarded to the output. Sampling is
+	// deterministic by process, so sampled processes keep complete activity.
+	Rates map[string]float64 `mapstructure:"rates"`
+	// MaxRate is the maximum number of events per second forwarded to the
+	// output. Rate limiting is disabled if zero.
+	MaxRate float64 `mapstructure:"max-rate"`
+	// Burst is the maximum number of events forwarded in a single burst when
+	// rate limiting is enabled. Defaults to the maximum rate.
+	Burst int `mapstructure:"burst"`
+}
+
+// IsEnabled determines if the sampling policy is defined.
+func (s Sampling) IsEnabled() bool { return len(s.Rates) > 0 || s.MaxRate > 0 }
+
+// Validate ensures the sampling rates are valid percentages.
+func (s Sampling) Validate() error {
+	for k, rate := range s.Rates {
+		if rate < 0 || rate > 100 {
+			return fmt.Errorf("sampling rate for %q must be between 0 and 100 but got %v", k, rate)
+		}
+	}
+	if s.MaxRate < 0 {
+		return errors.New("max rate can't be negative")
+	}
+	if s.Burst < 0 {
+		return errors.New("burst can't be negative")
+	}
+	return nil
+}
@@ -15,13 +15,144 @@
  * See the License for the specific language governing permissions and
  * limitations under the License.
  */
-
 package outputs
 
+import (
+	"fmt"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/ecs"
+	"github.com/rabbitstack/fibratus/pkg/event/eventpb"
+	"github.com/rabbitstack/fibratus/pkg/event/ocsf"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
 // Serializer is the type definition for the output serializers.
 type Serializer string
 
 const (
 	// JSON represents the JSON serializer type.
 	JSON Serializer = "json"
+	// ECS represents the Elastic Common Schema serializer type.
+	ECS Serializer = "ecs"
+	// OCSF represents the Open Cybersecurity Schema Framework serializer type.
+	OCSF Serializer = "ocsf"
+	// Protobuf represents the Protobuf serializer type. Events are encoded
+	// according to the schema published in the eventpb package.
+	Protobuf Serializer = "protobuf"
+	// Msgpack represents the MessagePack serializer type.
+	Msgpack Serializer = "msgpack"
 )
+
+// ErrUnsupportedSerializer signals the serializer is not supported by the output.
+var ErrUnsupportedSerializer = func(name Type, s Serializer) error {
+	return fmt.Errorf("unsupported %s output serializer %q", name, s)
+}
+
+// Validate checks whether the serializer is recognized. The
+// empty serializer is valid and falls back to the JSON serializer.
+// Binary serializers are only accepted by the outputs capable of
+// transporting arbitrary payloads.
+func (s Serializer) Validate(name Type) error {
+	switch s {
+	case JSON, ECS, OCSF, "":
+		return nil
+	case Protobuf, Msgpack:
+		switch name {
+		case HTTP, AMQP, File:
+			return nil
+		}
+		return ErrUnsupportedSerializer(name, s)
+	default:
+		return ErrUnsupportedSerializer(name, s)
+	}
+}
+
+// IsBinary determines if the serializer produces the binary encoding.
+func (s Serializer) IsBinary() bool { return s == Protobuf || s == Msgpack }
+
+// ContentType returns the media type of the serialized payload.
+func (s Serializer) ContentType() string {
+	switch s {
+	case Protobuf:
+		return "application/x-protobuf"
+	case Msgpack:
+		return "application/msgpack"
+	default:
+		return "application/json"
+	}
+}
+
+// Marshal serializes the event according to the serializer type.
+func (s Serializer) Marshal(evt *event.Event) ([]byte, error) {
+	switch s {
+	case JSON, "":
+		return evt.MarshalJSON(), nil
+	case ECS:
+		return ecs.Marshal(evt)
+	case OCSF:
+		return ocsf.Marshal(evt)
+	case Protobuf:
+		return proto.Marshal(evt.Proto())
+	case Msgpack:
+		return eventpb.MarshalMsgpack(evt.Proto())
+	default:
+		return nil, fmt.Errorf("unknown serializer %q", s)
+	}
+}
+
+// MarshalDelimited serializes the event so that it can be appended to
+// the stream of events. Text-based serializers terminate the event with
+// the line feed, while Protobuf messages are prefixed with their varint
+// encoded length. MessagePack documents are self-delimiting.
+func (s Serializer) MarshalDelimited(evt *event.Event) ([]byte, error) {
+	b, err := s.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+	switch s {
+	case Protobuf:
+		buf := make([]byte, 0, protowire.SizeVarint(uint64(len(b)))+len(b))
+		buf = protowire.AppendVarint(buf, uint64(len(b)))
+		return append(buf, b...), nil
+	case Msgpack:
+		return b, nil
+	default:
+		return append(b, '\n'), nil
+	}
+}
+
+// MarshalBatch serializes the batch of events. Text-based serializers
+// produce the JSON array where each element is the serialized event.
+// Binary serializers encode the batch as the eventpb.Batch message.
+func (s Serializer) MarshalBatch(batch *event.Batch) ([]byte, error) {
+	switch s {
+	case JSON, "":
+		return batch.MarshalJSON(), nil
+	case Protobuf, Msgpack:
+		pb := &eventpb.Batch{Events: make([]*eventpb.Event, 0, batch.Len())}
+		for _, evt := range batch.Events {
+			pb.Events = append(pb.Events, evt.Proto())
+		}
+		if s == Protobuf {
+			return proto.Marshal(pb)
+		}
+		return eventpb.MarshalMsgpack(pb)
+	}
+	buf := make([]byte, 0)
+	buf = append(buf, '[')
+	for i, evt := range batch.Events {
+		b, err := s.Marshal(evt)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+		buf = append(buf, '\n')
+		if i != len(batch.Events)-1 {
+			buf = append(buf, ',')
+		}
+	}
+	buf = append(buf, ']')
+	return buf, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package splunk
+
+import (
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/spf13/pflag"
+)
+
+const (
+	splunkEnabled         = "output.splunk.enabled"
+	splunkEndpoints       = "output.splunk.endpoints"
+	splunkToken           = "output.splunk.token"
+	splunkIndex           = "output.splunk.index"
+	splunkSource          = "output.splunk.source"
+	splunkSourcetype      = "output.splunk.sourcetype"
+	splunkHost            = "output.splunk.host"
+	splunkMaxBatchSize    = "output.splunk.max-batch-size"
+	splunkEnableGzip      = "output.splunk.enable-gzip"
+	splunkTimeout         = "output.splunk.timeout"
+	splunkMaxRetries      = "output.splunk.max-retries"
+	splunkRetryInterval   = "output.splunk.retry-interval"
+	splunkAck             = "output.splunk.ack"
+	splunkAckPollInterval = "output.splunk.ack-poll-interval"
+	splunkAckTimeout      = "output.splunk.ack-timeout"
+	splunkSerializer      = "output.splunk.serializer"
+)
+
+// Config contains the options for tweaking the Splunk HEC output behaviour.
+type Config struct {
+	outputs.TLSConfig
+	// Enabled determines whether Splunk output is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoints contains the list of HTTP Event Collector base URLs.
+	Endpoints []string `mapstructure:"endpoints"`
+	// Token is the HTTP Event Collector token.
+	Token string `mapstructure:"token"`
+	// Index is the name of the index by which the events are indexed.
+	// If empty, the default index assigned to the token is used.
+	Index string `mapstructure:"index"`
+	// Source is the source value assigned to events.
+	Source string `mapstructure:"source"`
+	// Sourcetype is the default sourcetype value assigned to events.
+	Sourcetype string `mapstructure:"sourcetype"`
+	// Sourcetypes maps event categories to sourcetypes.
+	Sourcetypes map[string]string `mapstructure:"sourcetypes"`
+	// Host overrides the host value of events. If empty, the
+	// host name where the event originated is used.
+	Host string `mapstructure:"host"`
+	// MaxBatchSize is the maximum size in bytes of the request payload.
+	MaxBatchSize int `mapstructure:"max-batch-size"`
+	// EnableGzip specifies whether the gzip compression is enabled.
+	EnableGzip bool `mapstructure:"enable-gzip"`
+	// Timeout represents the timeout for the HTTP requests.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxRetries is the number of times the request is retried when HEC is busy.
+	MaxRetries int `mapstructure:"max-retries"`
+	// RetryInterval is the initial wait time before retrying the request.
+	RetryInterval time.Duration `mapstructure:"retry-interval"`
+	// Ack indicates if the indexer acknowledgement is enabled.
+	Ack bool `mapstructure:"ack"`
+	// AckPollInterval is the interval at which the acknowledgement status is polled.
+	AckPollInterval time.Duration `mapstructure:"ack-poll-interval"`
+	// AckTimeout is the maximum time to wait for events to be acknowledged.
+	AckTimeout time.Duration `mapstructure:"ack-timeout"`
+	// Serializer indicates the serializer for the event field of the envelope.
+	Serializer outputs.Serializer `mapstructure:"serializer"`
+}
+
+// AddFlags registers persistent flags for the Splunk output.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(splunkEnabled, false, "Determines whether the Splunk output is enabled")
+	flags.StringSlice(splunkEndpoints, []string{"https://localhost:8088"}, "A comma-separated list of HTTP Event Collector base URLs")
+	flags.String(splunkToken, "", "Specifies the HTTP Event Collector token")
+	flags.String(splunkIndex, "", "Specifies the name of the index by which the events are indexed")
+	flags.String(splunkSource, "fibratus", "Specifies the source value assigned to events")
+	flags.String(splunkSourcetype, "fibratus:event", "Specifies the default sourcetype value assigned to events")
+	flags.String(splunkHost, "", "Overrides the host value of events")
+	flags.Int(splunkMaxBatchSize, 1000000, "Specifies the maximum size in bytes of the request payload")
+	flags.Bool(splunkEnableGzip, false, "Indicates whether the gzip compression is enabled")
+	flags.Duration(splunkTimeout, time.Second*10, "Represents the timeout for the HTTP requests")
+	flags.Int(splunkMaxRetries, 3, "Specifies the number of times the request is retried when HTTP Event Collector is busy")
+	flags.Duration(splunkRetryInterval, time.Second, "Specifies the initial wait time before retrying the request")
+	flags.Bool(splunkAck, false, "Indicates if the indexer acknowledgement is enabled")
+	flags.Duration(splunkAckPollInterval, time.Second, "Specifies the interval at which the acknowledgement status is polled")
+	flags.Duration(splunkAckTimeout, time.Minute, "Specifies the maximum time to wait for events to be acknowledged")
+	flags.String(splunkSerializer, string(outputs.JSON), "Indicates the event serializer type")
+	outputs.AddTLSFlags(flags, outputs.Splunk)
+}
@@ -0,0 +1,376 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package splunk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/rabbitstack/fibratus/pkg/util/tls"
+	"github.com/rabbitstack/fibratus/pkg/util/version"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// splunkErrors counts HEC delivery errors
+	splunkErrors = expvar.NewInt("output.splunk.publish.errors")
+	// splunkEvents counts the total number of published events
+	splunkEvents = expvar.NewInt("output.splunk.publish.events")
+	// splunkRetries counts requests retried due to HEC back-pressure
+	splunkRetries = expvar.NewInt("output.splunk.retries")
+	// splunkAckTimeouts counts batches not acknowledged by indexers in due time
+	splunkAckTimeouts = expvar.NewInt("output.splunk.ack.timeouts")
+	// splunkOversizedEvents counts events dropped because they exceed the maximum batch size
+	splunkOversizedEvents = expvar.NewInt("output.splunk.oversized.events")
+)
+
+// userAgentHeader represents the value of the User-Agent header
+var userAgentHeader = version.ProductToken()
+
+const (
+	// eventPath is the path of the HEC event endpoint
+	eventPath = "/services/collector/event"
+	// ackPath is the path of the HEC indexer acknowledgement endpoint
+	ackPath = "/services/collector/ack"
+)
+
+// envelope is the HEC event metadata that wraps the event.
+type envelope struct {
+	Time       float64         `json:"time"`
+	Host       string          `json:"host,omitempty"`
+	Source     string          `json:"source,omitempty"`
+	Sourcetype string          `json:"sourcetype,omitempty"`
+	Index      string          `json:"index,omitempty"`
+	Event      json.RawMessage `json:"event"`
+}
+
+// response is the HEC event endpoint response.
+type response struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckID *int64 `json:"ackId"`
+}
+
+// chunk is the request payload packed under the maximum batch size.
+type chunk struct {
+	buf    []byte
+	events int
+}
+
+type splunk struct {
+	client  *http.Client
+	config  Config
+	url     string
+	ackURL  string
+	channel string
+}
+
+func init() {
+	outputs.Register(outputs.Splunk, initSplunk)
+}
+
+func initSplunk(config outputs.Config) (outputs.OutputGroup, error) {
+	cfg, ok := config.Output.(Config)
+	if !ok {
+		return outputs.Fail(outputs.ErrInvalidConfig(outputs.Splunk, config.Output))
+	}
+	if cfg.Token == "" {
+		return outputs.Fail(errors.New("HTTP Event Collector token is required"))
+	}
+	if err := cfg.Serializer.Validate(outputs.Splunk); err != nil {
+		return outputs.Fail(err)
+	}
+
+	clients := make([]outputs.Client, len(cfg.Endpoints))
+	for i, endpoint := range cfg.Endpoints {
+		s, err := newSplunk(cfg, endpoint)
+		if err != nil {
+			return outputs.Fail(err)
+		}
+		clients[i] = s
+	}
+
+	return outputs.Success(clients...), nil
+}
+
+func newSplunk(cfg Config, endpoint string) (*splunk, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), eventPath)
+
+	tlsConfig, err := tls.MakeConfig(cfg.TLSCert, cfg.TLSKey, cfg.TLSCA, cfg.TLSInsecureSkipVerify)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS config: %v", err)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+			Proxy:           http.ProxyFromEnvironment,
+		},
+		Timeout: cfg.Timeout,
+	}
+
+	return &splunk{
+		client:  client,
+		config:  cfg,
+		url:     u.String() + eventPath,
+		ackURL:  u.String() + ackPath,
+		channel: uuid.New().String(),
+	}, nil
+}
+
+func (s *splunk) Connect() error { return nil }
+func (s *splunk) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// Publish sends the batch of events to the HTTP Event Collector. Events are
+// packed into one or more requests whose payload doesn't exceed the maximum
+// batch size. If the indexer acknowledgement is enabled, Publish returns once
+// indexers acknowledge all requests, so the batch is published again if the
+// acknowledgement doesn't arrive in due time.
+func (s *splunk) Publish(batch *event.Batch) error {
+	chunks, err := s.pack(batch.Events)
+	if err != nil {
+		splunkErrors.Add(1)
+		return outputs.Permanent(err)
+	}
+
+	acks := make([]int64, 0, len(chunks))
+	for _, c := range chunks {
+		id, err := s.send(c.buf)
+		if err != nil {
+			splunkErrors.Add(1)
+			return err
+		}
+		if s.config.Ack {
+			acks = append(acks, id)
+		}
+		splunkEvents.Add(int64(c.events))
+	}
+
+	if len(acks) > 0 {
+		if err := s.waitAcks(acks); err != nil {
+			splunkErrors.Add(1)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pack wraps events in HEC envelopes and concatenates envelopes
+// into chunks that don't exceed the maximum batch size. Events
+// that exceed the maximum batch size on their own are dropped.
+func (s *splunk) pack(evts []*event.Event) ([]chunk, error) {
+	chunks := make([]chunk, 0, 1)
+	var c chunk
+	for _, evt := range evts {
+		env, err := s.envelope(evt)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(env)
+		if err != nil {
+			return nil, err
+		}
+		if s.config.MaxBatchSize > 0 && len(b) > s.config.MaxBatchSize {
+			splunkOversizedEvents.Add(1)
+			log.Warnf("dropping %s event of %d bytes exceeding maximum Splunk batch size", evt.Name, len(b))
+			continue
+		}
+		if s.config.MaxBatchSize > 0 && len(c.buf)+len(b) > s.config.MaxBatchSize {
+			chunks = append(chunks, c)
+			c = chunk{}
+		}
+		c.buf = append(c.buf, b...)
+		c.events++
+	}
+	if c.events > 0 {
+		chunks = append(chunks, c)
+	}
+	return chunks, nil
+}
+
+// envelope wraps the event in the HEC envelope. The sourcetype
+// is resolved from the event category mapping, and falls back
+// to the default sourcetype.
+func (s *splunk) envelope(evt *event.Event) (envelope, error) {
+	host := s.config.Host
+	if host == "" {
+		host = evt.Host
+	}
+	sourcetype, ok := s.config.Sourcetypes[string(evt.Category)]
+	if !ok {
+		sourcetype = s.config.Sourcetype
+	}
+	data, err := s.config.Serializer.Marshal(evt)
+	if err != nil {
+		return envelope{}, err
+	}
+	return envelope{
+		Time:       float64(evt.Timestamp.UnixMilli()) / 1000,
+		Host:       host,
+		Source:     s.config.Source,
+		Sourcetype: sourcetype,
+		Index:      s.config.Index,
+		Event:      data,
+	}, nil
+}
+
+// send posts the payload to the event endpoint and returns the
+// acknowledgement identifier. If HEC responds with the 503 status
+// code signaling the server is busy, the request is retried after
+// the interval specified in the Retry-After header, or the backoff
+// interval that doubles on every attempt.
+func (s *splunk) send(payload []byte) (int64, error) {
+	if s.config.EnableGzip {
+		var b bytes.Buffer
+		gz := gzip.NewWriter(&b)
+		if _, err := gz.Write(payload); err != nil {
+			return 0, err
+		}
+		if err := gz.Close(); err != nil {
+			return 0, err
+		}
+		payload = b.Bytes()
+	}
+
+	backoff := s.config.RetryInterval
+	for attempt := 0; ; attempt++ {
+		resp, body, err := s.do(s.url, payload, s.config.EnableGzip)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case resp.StatusCode == http.StatusServiceUnavailable && attempt < s.config.MaxRetries:
+			wait := backoff
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+				wait = time.Duration(secs) * time.Second
+			}
+			splunkRetries.Add(1)
+			log.Debugf("HTTP Event Collector is busy. Retrying in %v...", wait)
+			time.Sleep(wait)
+			backoff *= 2
+			continue
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			err := fmt.Errorf("splunk request failed with %d status code: %s", resp.StatusCode, string(body))
+			if outputs.IsPermanentStatus(resp.StatusCode) {
+				return 0, outputs.Permanent(err)
+			}
+			return 0, err
+		}
+		if !s.config.Ack {
+			return 0, nil
+		}
+		var r response
+		if err := json.Unmarshal(body, &r); err != nil {
+			return 0, fmt.Errorf("invalid HTTP Event Collector response: %v", err)
+		}
+		if r.AckID == nil {
+			return 0, errors.New("HTTP Event Collector response is missing the ackId. Is indexer acknowledgement enabled for the token?")
+		}
+		return *r.AckID, nil
+	}
+}
+
+// waitAcks polls the acknowledgement endpoint until all
+// acknowledgement identifiers are reported as indexed.
+func (s *splunk) waitAcks(ids []int64) error {
+	deadline := time.Now().Add(s.config.AckTimeout)
+	for {
+		req, err := json.Marshal(map[string][]int64{"acks": ids})
+		if err != nil {
+			return err
+		}
+		resp, body, err := s.do(s.ackURL, req, false)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("splunk ack request failed with %d status code: %s", resp.StatusCode, string(body))
+		}
+		var status struct {
+			Acks map[string]bool `json:"acks"`
+		}
+		if err := json.Unmarshal(body, &status); err != nil {
+			return fmt.Errorf("invalid HTTP Event Collector ack response: %v", err)
+		}
+		pending := ids[:0]
+		for _, id := range ids {
+			if !status.Acks[strconv.FormatInt(id, 10)] {
+				pending = append(pending, id)
+			}
+		}
+		ids = pending
+		if len(ids) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			splunkAckTimeouts.Add(1)
+			return fmt.Errorf("%d request(s) not acknowledged by indexers in %v", len(ids), s.config.AckTimeout)
+		}
+		time.Sleep(s.config.AckPollInterval)
+	}
+}
+
+// do sends the request to the HEC endpoint and reads the response body.
+func (s *splunk) do(url string, payload []byte, gzip bool) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Authorization", "Splunk "+s.config.Token)
+	req.Header.Set("User-Agent", userAgentHeader)
+	req.Header.Set("Content-Type", "application/json")
+	if gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if s.config.Ack {
+		req.Header.Set("X-Splunk-Request-Channel", s.channel)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
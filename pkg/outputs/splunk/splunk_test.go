@@ -0,0 +1,236 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package splunk
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event/eventtest"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hec is the HTTP Event Collector stub.
+type hec struct {
+	mu        sync.Mutex
+	envelopes []map[string]any
+	requests  int
+	busy      int
+	ackID     int64
+	polls     int
+	ackAfter  int
+	channels  []string
+}
+
+func (h *hec) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(eventPath, func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		assert.Equal(t, "Splunk 11111111-2222-3333-4444-555555555555", r.Header.Get("Authorization"))
+		h.requests++
+		if h.busy > 0 {
+			h.busy--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"text":"Server is busy","code":9}`))
+			return
+		}
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = gz
+		}
+		dec := json.NewDecoder(body)
+		for dec.More() {
+			var e map[string]any
+			require.NoError(t, dec.Decode(&e))
+			h.envelopes = append(h.envelopes, e)
+		}
+		if ch := r.Header.Get("X-Splunk-Request-Channel"); ch != "" {
+			h.channels = append(h.channels, ch)
+			_, _ = w.Write([]byte(`{"text":"Success","code":0,"ackId":` + strconv.FormatInt(h.ackID, 10) + `}`))
+			h.ackID++
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	})
+	mux.HandleFunc(ackPath, func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		var req struct {
+			Acks []int64 `json:"acks"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		h.polls++
+		acks := make(map[string]bool)
+		for _, id := range req.Acks {
+			acks[strconv.FormatInt(id, 10)] = h.ackAfter >= 0 && h.polls > h.ackAfter
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"acks": acks})
+	})
+	return mux
+}
+
+func config() Config {
+	return Config{
+		Enabled:         true,
+		Token:           "11111111-2222-3333-4444-555555555555",
+		Index:           "edr",
+		Source:          "fibratus",
+		Sourcetype:      "fibratus:event",
+		Sourcetypes:     map[string]string{"process": "fibratus:process"},
+		MaxBatchSize:    1000000,
+		Timeout:         time.Second * 5,
+		MaxRetries:      3,
+		RetryInterval:   time.Millisecond * 10,
+		AckPollInterval: time.Millisecond * 10,
+		AckTimeout:      time.Second,
+	}
+}
+
+func newClient(t *testing.T, h *hec, cfg Config) (*splunk, func()) {
+	srv := httptest.NewServer(h.handler(t))
+	cfg.Endpoints = []string{srv.URL}
+	group, err := outputs.Load(outputs.Splunk, outputs.Config{Type: outputs.Splunk, Output: cfg})
+	require.NoError(t, err)
+	require.Len(t, group.Clients, 1)
+	return group.Clients[0].(*splunk), srv.Close
+}
+
+func TestPublish(t *testing.T) {
+	h := &hec{}
+	s, closer := newClient(t, h, config())
+	defer closer()
+
+	require.NoError(t, s.Publish(eventtest.Batch("create-process", "connect")))
+	require.Equal(t, 1, h.requests)
+	require.Len(t, h.envelopes, 2)
+
+	e := h.envelopes[0]
+	assert.Equal(t, 1715682161.125, e["time"])
+	assert.Equal(t, "archrabbit", e["host"])
+	assert.Equal(t, "fibratus", e["source"])
+	assert.Equal(t, "fibratus:process", e["sourcetype"])
+	assert.Equal(t, "edr", e["index"])
+	require.IsType(t, map[string]any{}, e["event"])
+	assert.Equal(t, "CreateProcess", e["event"].(map[string]any)["name"])
+
+	assert.Equal(t, "fibratus:event", h.envelopes[1]["sourcetype"])
+}
+
+func TestPublishGzip(t *testing.T) {
+	h := &hec{}
+	cfg := config()
+	cfg.EnableGzip = true
+	cfg.Host = "edr-collector"
+	s, closer := newClient(t, h, cfg)
+	defer closer()
+
+	require.NoError(t, s.Publish(eventtest.Batch("create-process", "connect")))
+	require.Len(t, h.envelopes, 2)
+	assert.Equal(t, "edr-collector", h.envelopes[0]["host"])
+}
+
+func TestPublishMaxBatchSize(t *testing.T) {
+	h := &hec{}
+	cfg := config()
+	s, closer := newClient(t, h, cfg)
+	defer closer()
+
+	batch := eventtest.Batch("create-process", "connect")
+	chunks, err := s.pack(batch.Events)
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+
+	// each envelope goes in a separate request
+	s.config.MaxBatchSize = len(chunks[0].buf) - 1
+	require.NoError(t, s.Publish(batch))
+	assert.Equal(t, 2, h.requests)
+	assert.Len(t, h.envelopes, 2)
+
+	// the oversized event is dropped
+	oversized := splunkOversizedEvents.Value()
+	s.config.MaxBatchSize = 10
+	require.NoError(t, s.Publish(batch))
+	assert.Equal(t, int64(2), splunkOversizedEvents.Value()-oversized)
+	assert.Equal(t, 2, h.requests)
+}
+
+func TestPublishBusy(t *testing.T) {
+	h := &hec{busy: 2}
+	s, closer := newClient(t, h, config())
+	defer closer()
+
+	retries := splunkRetries.Value()
+	require.NoError(t, s.Publish(eventtest.Batch("create-process", "connect")))
+	assert.Equal(t, 3, h.requests)
+	assert.Equal(t, int64(2), splunkRetries.Value()-retries)
+	assert.Len(t, h.envelopes, 2)
+
+	h.busy = 10
+	err := s.Publish(eventtest.Batch("create-process", "connect"))
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "503"))
+}
+
+func TestPublishAck(t *testing.T) {
+	h := &hec{ackAfter: 2}
+	cfg := config()
+	cfg.Ack = true
+	s, closer := newClient(t, h, cfg)
+	defer closer()
+
+	// each envelope goes in a separate request
+	chunks, err := s.pack(eventtest.Batch("create-process", "connect").Events)
+	require.NoError(t, err)
+	s.config.MaxBatchSize = len(chunks[0].buf) - 1
+
+	require.NoError(t, s.Publish(eventtest.Batch("create-process", "connect")))
+	assert.Equal(t, 2, h.requests)
+	assert.Equal(t, 3, h.polls)
+	require.Len(t, h.channels, 2)
+	assert.Equal(t, h.channels[0], h.channels[1])
+
+	// indexers never acknowledge the request
+	h.ackAfter = -1
+	timeouts := splunkAckTimeouts.Value()
+	s.config.AckTimeout = time.Millisecond * 50
+	require.Error(t, s.Publish(eventtest.Batch("create-process", "connect")))
+	assert.Equal(t, int64(1), splunkAckTimeouts.Value()-timeouts)
+}
+
+func TestEndpointURL(t *testing.T) {
+	for _, endpoint := range []string{"https://splunk:8088", "https://splunk:8088/", "https://splunk:8088/services/collector/event"} {
+		s, err := newSplunk(config(), endpoint)
+		require.NoError(t, err)
+		assert.Equal(t, "https://splunk:8088/services/collector/event", s.url)
+		assert.Equal(t, "https://splunk:8088/services/collector/ack", s.ackURL)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/spf13/pflag"
+)
+
+const (
+	syslogEnabled        = "output.syslog.enabled"
+	syslogNetwork        = "output.syslog.network"
+	syslogAddress        = "output.syslog.address"
+	syslogFormat         = "output.syslog.format"
+	syslogPayload        = "output.syslog.payload"
+	syslogFraming        = "output.syslog.framing"
+	syslogFacility       = "output.syslog.facility"
+	syslogAppName        = "output.syslog.app-name"
+	syslogStructuredData = "output.syslog.structured-data"
+	syslogEnterpriseID   = "output.syslog.enterprise-id"
+	syslogTimeout        = "output.syslog.timeout"
+	syslogSerializer     = "output.syslog.serializer"
+)
+
+// Payload is the format of the syslog message content.
+type Payload string
+
+const (
+	// JSON renders the event as JSON document.
+	JSON Payload = "json"
+	// CEF renders the event in Common Event Format.
+	CEF Payload = "cef"
+	// LEEF renders the event in Log Event Extended Format.
+	LEEF Payload = "leef"
+)
+
+// Config contains the options for tweaking the syslog output behaviour.
+type Config struct {
+	outputs.TLSConfig
+	// Enabled determines whether syslog output is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Network is the transport protocol. Can be udp, tcp, or tls.
+	Network string `mapstructure:"network"`
+	// Address is the syslog server address in the host:port format.
+	Address string `mapstructure:"address"`
+	// Format is the syslog message format. Can be rfc5424 or rfc3164.
+	Format string `mapstructure:"format"`
+	// Payload is the format of the message content. Can be json, cef, or leef.
+	Payload Payload `mapstructure:"payload"`
+	// Framing is the message framing method on stream transports. Can be
+	// octet-counting or non-transparent.
+	Framing string `mapstructure:"framing"`
+	// Facility is the syslog facility keyword, e.g. local0.
+	Facility string `mapstructure:"facility"`
+	// AppName identifies the application that originates messages.
+	AppName string `mapstructure:"app-name"`
+	// StructuredData indicates if RFC 5424 structured data elements
+	// are built from event parameters and process attributes.
+	StructuredData bool `mapstructure:"structured-data"`
+	// EnterpriseID is the private enterprise number used in structured data element identifiers.
+	EnterpriseID int `mapstructure:"enterprise-id"`
+	// Timeout is the connection and write timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Serializer indicates the event serializer for the json payload.
+	Serializer outputs.Serializer `mapstructure:"serializer"`
+}
+
+// AddFlags registers persistent flags for the syslog output.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(syslogEnabled, false, "Determines whether the syslog output is enabled")
+	flags.String(syslogNetwork, "udp", "Specifies the transport protocol. Possible values are udp, tcp, and tls")
+	flags.String(syslogAddress, "localhost:514", "Specifies the syslog server address in the host:port format")
+	flags.String(syslogFormat, "rfc5424", "Specifies the syslog message format. Possible values are rfc5424 and rfc3164")
+	flags.String(syslogPayload, string(JSON), "Specifies the format of the message content. Possible values are json, cef, and leef")
+	flags.String(syslogFraming, "octet-counting", "Specifies the message framing method on tcp and tls transports. Possible values are octet-counting and non-transparent")
+	flags.String(syslogFacility, "local0", "Specifies the syslog facility keyword")
+	flags.String(syslogAppName, "fibratus", "Identifies the application that originates messages")
+	flags.Bool(syslogStructuredData, true, "Indicates if RFC 5424 structured data elements are built from event parameters and process attributes")
+	flags.Int(syslogEnterpriseID, 32473, "Specifies the private enterprise number used in structured data element identifiers")
+	flags.Duration(syslogTimeout, time.Second*5, "Specifies the connection and write timeout")
+	flags.String(syslogSerializer, string(outputs.JSON), "Indicates the event serializer type for the json payload")
+	outputs.AddTLSFlags(flags, outputs.Syslog)
+}
@@ -0,0 +1,322 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"expvar"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	"github.com/rabbitstack/fibratus/pkg/util/stringcase"
+	"github.com/rabbitstack/fibratus/pkg/util/syslog"
+	"github.com/rabbitstack/fibratus/pkg/util/tls"
+	"github.com/rabbitstack/fibratus/pkg/util/version"
+)
+
+var (
+	// syslogErrors counts syslog delivery errors
+	syslogErrors = expvar.NewInt("output.syslog.publish.errors")
+	// syslogMessages counts the total number of published messages
+	syslogMessages = expvar.NewInt("output.syslog.publish.messages")
+)
+
+// cefKeys maps event parameters to CEF extension dictionary keys.
+var cefKeys = map[string]string{
+	"sip":       "src",
+	"dip":       "dst",
+	"sport":     "spt",
+	"dport":     "dpt",
+	"file_path": "filePath",
+}
+
+// leefKeys maps event parameters to LEEF predefined attributes.
+var leefKeys = map[string]string{
+	"sip":   "src",
+	"dip":   "dst",
+	"sport": "srcPort",
+	"dport": "dstPort",
+}
+
+type sys struct {
+	writer     *syslog.Writer
+	format     syslog.Format
+	payload    Payload
+	serializer outputs.Serializer
+	facility   syslog.Facility
+	appName    string
+	sd         bool
+	pen        string
+}
+
+func init() {
+	outputs.Register(outputs.Syslog, initSyslog)
+}
+
+func initSyslog(config outputs.Config) (outputs.OutputGroup, error) {
+	cfg, ok := config.Output.(Config)
+	if !ok {
+		return outputs.Fail(outputs.ErrInvalidConfig(outputs.Syslog, config.Output))
+	}
+	s, err := newSyslog(cfg)
+	if err != nil {
+		return outputs.Fail(err)
+	}
+	return outputs.Success(s), nil
+}
+
+func newSyslog(cfg Config) (*sys, error) {
+	format, err := syslog.ParseFormat(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+	framing, err := syslog.ParseFraming(cfg.Framing)
+	if err != nil {
+		return nil, err
+	}
+	facility, err := syslog.ParseFacility(cfg.Facility)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Payload {
+	case JSON, CEF, LEEF:
+	case "":
+		cfg.Payload = JSON
+	default:
+		return nil, fmt.Errorf("unknown syslog payload format %q", cfg.Payload)
+	}
+	if err := cfg.Serializer.Validate(outputs.Syslog); err != nil {
+		return nil, err
+	}
+	tlsConfig, err := tls.MakeConfig(cfg.TLSCert, cfg.TLSKey, cfg.TLSCA, cfg.TLSInsecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := syslog.NewWriter(cfg.Network, cfg.Address, tlsConfig, framing, cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &sys{
+		writer:     writer,
+		format:     format,
+		payload:    cfg.Payload,
+		serializer: cfg.Serializer,
+		facility:   facility,
+		appName:    cfg.AppName,
+		sd:         cfg.StructuredData,
+		pen:        strconv.Itoa(cfg.EnterpriseID),
+	}, nil
+}
+
+func (s *sys) Connect() error { return s.writer.Connect() }
+func (s *sys) Close() error   { return s.writer.Close() }
+
+// Publish sends the batch of events to the syslog server. Each event is
+// delivered in a separate syslog message. If the stream transport drops
+// the connection, the error is wrapped with outputs.ErrConnectionLost to
+// signal the aggregator worker to reconnect.
+func (s *sys) Publish(batch *event.Batch) error {
+	msgs := make([][]byte, 0, len(batch.Events))
+	for _, evt := range batch.Events {
+		m, err := s.message(evt)
+		if err != nil {
+			syslogErrors.Add(1)
+			return err
+		}
+		msgs = append(msgs, m.Encode(s.format))
+	}
+	if err := s.writer.Write(msgs...); err != nil {
+		syslogErrors.Add(1)
+		if s.writer.IsStream() {
+			return fmt.Errorf("%w: %v", outputs.ErrConnectionLost, err)
+		}
+		return err
+	}
+	syslogMessages.Add(int64(len(msgs)))
+	return nil
+}
+
+// message builds the syslog message from the event.
+func (s *sys) message(evt *event.Event) (syslog.Message, error) {
+	m := syslog.Message{
+		Facility:  s.facility,
+		Severity:  syslog.Informational,
+		Timestamp: evt.Timestamp,
+		Hostname:  evt.Host,
+		AppName:   s.appName,
+		ProcID:    strconv.FormatUint(uint64(evt.PID), 10),
+		MsgID:     evt.Name,
+	}
+	if evt.ContainsMeta(event.RuleNameKey) {
+		m.Severity = syslog.Notice
+	}
+	if s.sd && s.format == syslog.RFC5424 {
+		m.StructuredData = s.structuredData(evt)
+	}
+	switch s.payload {
+	case CEF:
+		m.Msg = cef(evt)
+	case LEEF:
+		m.Msg = leef(evt)
+	default:
+		b, err := s.serializer.Marshal(evt)
+		if err != nil {
+			return m, err
+		}
+		m.Msg = string(b)
+	}
+	return m, nil
+}
+
+// structuredData builds the event, process, and parameters structured data elements.
+func (s *sys) structuredData(evt *event.Event) []syslog.SDElement {
+	e := syslog.SDElement{
+		ID: "evt@" + s.pen,
+		Params: []syslog.SDParam{
+			{Name: "seq", Value: strconv.FormatUint(evt.Seq, 10)},
+			{Name: "name", Value: evt.Name},
+			{Name: "category", Value: string(evt.Category)},
+			{Name: "tid", Value: strconv.FormatUint(uint64(evt.Tid), 10)},
+			{Name: "cpu", Value: strconv.FormatUint(uint64(evt.CPU), 10)},
+		},
+	}
+	if rule := evt.GetMetaAsString(event.RuleNameKey); rule != "" {
+		e.Params = append(e.Params, syslog.SDParam{Name: "rule", Value: rule})
+	}
+	sd := []syslog.SDElement{e}
+
+	if evt.PS != nil {
+		ps := evt.PS
+		sd = append(sd, syslog.SDElement{
+			ID: "ps@" + s.pen,
+			Params: []syslog.SDParam{
+				{Name: "pid", Value: strconv.FormatUint(uint64(ps.PID), 10)},
+				{Name: "ppid", Value: strconv.FormatUint(uint64(ps.Ppid), 10)},
+				{Name: "name", Value: ps.Name},
+				{Name: "exe", Value: ps.Exe},
+				{Name: "cmdline", Value: ps.Cmdline},
+				{Name: "sid", Value: ps.SID},
+				{Name: "username", Value: ps.Username},
+				{Name: "domain", Value: ps.Domain},
+			},
+		})
+	}
+
+	if len(evt.Params) > 0 {
+		params := syslog.SDElement{ID: "params@" + s.pen}
+		for _, name := range paramNames(evt) {
+			params.Params = append(params.Params, syslog.SDParam{Name: name, Value: evt.GetParamAsString(name)})
+		}
+		sd = append(sd, params)
+	}
+
+	return sd
+}
+
+// cef renders the event in Common Event Format.
+func cef(evt *event.Event) string {
+	severity := 3
+	if evt.ContainsMeta(event.RuleNameKey) {
+		severity = 7
+	}
+	ext := []syslog.Field{
+		{Key: "rt", Value: strconv.FormatInt(evt.Timestamp.UnixMilli(), 10)},
+		{Key: "dvchost", Value: evt.Host},
+		{Key: "cat", Value: string(evt.Category)},
+		{Key: "msg", Value: evt.Description},
+	}
+	if evt.PS != nil {
+		ext = append(ext,
+			syslog.Field{Key: "spid", Value: strconv.FormatUint(uint64(evt.PS.PID), 10)},
+			syslog.Field{Key: "sproc", Value: evt.PS.Exe},
+			syslog.Field{Key: "suser", Value: username(evt)},
+		)
+	}
+	if rule := evt.GetMetaAsString(event.RuleNameKey); rule != "" {
+		ext = append(ext,
+			syslog.Field{Key: "cs1Label", Value: "rule"},
+			syslog.Field{Key: "cs1", Value: rule},
+		)
+	}
+	for _, name := range paramNames(evt) {
+		ext = append(ext, syslog.Field{Key: key(name, cefKeys), Value: evt.GetParamAsString(name)})
+	}
+	return syslog.CEF("Fibratus", "Fibratus", version.Get(), evt.Name, evt.Name, severity, ext)
+}
+
+// leef renders the event in Log Event Extended Format.
+func leef(evt *event.Event) string {
+	sev := "3"
+	if evt.ContainsMeta(event.RuleNameKey) {
+		sev = "7"
+	}
+	attrs := []syslog.Field{
+		{Key: "devTime", Value: strconv.FormatInt(evt.Timestamp.UnixMilli(), 10)},
+		{Key: "identHostName", Value: evt.Host},
+		{Key: "cat", Value: string(evt.Category)},
+		{Key: "sev", Value: sev},
+	}
+	if evt.PS != nil {
+		attrs = append(attrs,
+			syslog.Field{Key: "usrName", Value: username(evt)},
+			syslog.Field{Key: "procPid", Value: strconv.FormatUint(uint64(evt.PS.PID), 10)},
+			syslog.Field{Key: "procExe", Value: evt.PS.Exe},
+		)
+	}
+	if rule := evt.GetMetaAsString(event.RuleNameKey); rule != "" {
+		attrs = append(attrs, syslog.Field{Key: "rule", Value: rule})
+	}
+	for _, name := range paramNames(evt) {
+		attrs = append(attrs, syslog.Field{Key: key(name, leefKeys), Value: evt.GetParamAsString(name)})
+	}
+	return syslog.LEEF("Fibratus", "Fibratus", version.Get(), evt.Name, attrs)
+}
+
+// paramNames returns sorted event parameter names.
+func paramNames(evt *event.Event) []string {
+	names := make([]string, 0, len(evt.Params))
+	for name := range evt.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// key returns the extension key for the event parameter. Parameters
+// without the predefined key are converted to lower camel case.
+func key(name string, keys map[string]string) string {
+	if k, ok := keys[name]; ok {
+		return k
+	}
+	k := stringcase.Camel(name)
+	if k == "" {
+		return name
+	}
+	return strings.ToLower(k[:1]) + k[1:]
+}
+
+func username(evt *event.Event) string {
+	if evt.PS.Domain != "" && evt.PS.Username != "" {
+		return evt.PS.Domain + "\\" + evt.PS.Username
+	}
+	return evt.PS.Username
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	"github.com/rabbitstack/fibratus/pkg/outputs"
+	pstypes "github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatch() *event.Batch {
+	evt := &event.Event{
+		Seq:       2,
+		Type:      event.ConnectTCPv4,
+		Timestamp: time.Date(2025, 3, 14, 10, 11, 12, 0, time.UTC),
+		Name:      "Connect",
+		Category:  event.Net,
+		PID:       4024,
+		Tid:       2484,
+		Host:      "archrabbit",
+		Params: event.Params{
+			params.NetDport: {Name: params.NetDport, Type: params.Port, Value: uint16(443)},
+			params.NetDIP:   {Name: params.NetDIP, Type: params.IPv4, Value: net.ParseIP("216.58.201.174")},
+		},
+		Metadata: map[event.MetadataKey]any{event.RuleNameKey: "Suspicious connection"},
+		PS: &pstypes.PS{
+			PID:      4024,
+			Name:     "cmd.exe",
+			Exe:      `C:\Windows\System32\cmd.exe`,
+			Username: "admin",
+			Domain:   "ARCHRABBIT",
+		},
+	}
+	return event.NewBatch(evt)
+}
+
+func listen(t *testing.T) (net.Listener, chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}(conn)
+		}
+	}()
+	return l, lines
+}
+
+func TestSyslogPublish(t *testing.T) {
+	l, lines := listen(t)
+	defer l.Close()
+
+	s, err := newSyslog(Config{
+		Network:        "tcp",
+		Address:        l.Addr().String(),
+		Format:         "rfc5424",
+		Payload:        CEF,
+		Framing:        "non-transparent",
+		Facility:       "local0",
+		AppName:        "fibratus",
+		StructuredData: true,
+		EnterpriseID:   32473,
+		Timeout:        time.Second,
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Connect())
+	defer s.Close()
+
+	require.NoError(t, s.Publish(newBatch()))
+
+	select {
+	case line := <-lines:
+		assert.True(t, strings.HasPrefix(line, `<133>1 2025-03-14T10:11:12.000000Z archrabbit fibratus 4024 Connect [evt@32473 seq="2" name="Connect" category="net" tid="2484" cpu="0" rule="Suspicious connection"][ps@32473 pid="4024"`), line)
+		assert.Contains(t, line, `[params@32473 dip="216.58.201.174" dport="443"]`)
+		assert.Contains(t, line, `CEF:0|Fibratus|Fibratus|`)
+		assert.Contains(t, line, `|Connect|Connect|7|rt=1741947072000 dvchost=archrabbit cat=net spid=4024 sproc=C:\\Windows\\System32\\cmd.exe suser=ARCHRABBIT\\admin cs1Label=rule cs1=Suspicious connection dst=216.58.201.174 dpt=443`)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for syslog message")
+	}
+}
+
+func TestSyslogPublishConnectionLost(t *testing.T) {
+	s, err := newSyslog(Config{Network: "tcp", Address: "127.0.0.1:1", Payload: LEEF, Facility: "local0", Timeout: time.Second})
+	require.NoError(t, err)
+
+	// publishing without the established connection
+	// instructs the worker to reconnect the client
+	require.ErrorIs(t, s.Publish(newBatch()), outputs.ErrConnectionLost)
+
+	_, err = newSyslog(Config{Network: "tcp", Address: "127.0.0.1:514", Payload: "xml", Facility: "local0"})
+	require.Error(t, err)
+}
+
+func TestLEEF(t *testing.T) {
+	assert.Contains(t, leef(newBatch().Events[0]), "|Connect|devTime=1741947072000\tidentHostName=archrabbit\tcat=net\tsev=7\tusrName=ARCHRABBIT\\admin\tprocPid=4024\tprocExe=C:\\Windows\\System32\\cmd.exe\trule=Suspicious connection\tdst=216.58.201.174\tdstPort=443")
+}
+
+func TestKey(t *testing.T) {
+	var tests = []struct {
+		name     string
+		expected string
+	}{
+		{params.NetDIP, "dst"},
+		{"file_object", "fileObject"},
+		{"Status", "status"},
+		{"2fa_enabled", "2FaEnabled"},
+		{"_", "_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, key(tt.name, cefKeys))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quarantine
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	path        = "quarantine.path"
+	maxFileSize = "quarantine.max-file-size"
+)
+
+// Config contains the settings of the file quarantine.
+type Config struct {
+	// Path is the directory where quarantined files are stored.
+	Path string `json:"quarantine.path" yaml:"quarantine.path"`
+	// MaxFileSize is the maximum size in megabytes of the file
+	// that can be quarantined.
+	MaxFileSize int `json:"quarantine.max-file-size" yaml:"quarantine.max-file-size"`
+}
+
+// AddFlags registers persistent flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.String(path, "", "Specifies the directory where quarantined files are stored. Defaults to the Quarantine directory in the installation path")
+	flags.Int(maxFileSize, 256, "Specifies the maximum size in megabytes of the file that can be quarantined")
+}
+
+// InitFromViper initializes quarantine config from Viper.
+func (c *Config) InitFromViper(v *viper.Viper) {
+	c.Path = v.GetString(path)
+	c.MaxFileSize = v.GetInt(maxFileSize)
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+// +build !windows
+
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quarantine
+
+import "errors"
+
+// errUnsupported is returned on platforms that lack the vault protection primitives
+var errUnsupported = errors.New("quarantine vault is only supported on Windows")
+
+// protectDir returns unsupported platform error.
+func protectDir(dir string) error {
+	return errUnsupported
+}
+
+// protect returns unsupported platform error.
+func protect(b []byte) ([]byte, error) {
+	return nil, errUnsupported
+}
+
+// unprotect returns unsupported platform error.
+func unprotect(b []byte) ([]byte, error) {
+	return nil, errUnsupported
+}
@@ -0,0 +1,409 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quarantine
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// filesQuarantined counts the number of files moved to the quarantine
+var filesQuarantined = expvar.NewInt("quarantine.files.quarantined")
+
+// ErrNotFound is returned when the quarantine entry doesn't exist.
+var ErrNotFound = errors.New("quarantine entry not found")
+
+const (
+	// keyFile is the name of the file storing the protected vault key
+	keyFile = "vault.key"
+	// manifestExt is the extension of the quarantine entry manifest
+	manifestExt = ".json"
+	// dataExt is the extension of the encrypted file content
+	dataExt = ".bin"
+)
+
+// Entry is the manifest of the quarantined file.
+type Entry struct {
+	// ID uniquely identifies the quarantine entry.
+	ID string `json:"id"`
+	// Path is the original path of the quarantined file.
+	Path string `json:"path"`
+	// Size is the size of the quarantined file in bytes.
+	Size int64 `json:"size"`
+	// MD5 is the MD5 hash of the quarantined file.
+	MD5 string `json:"md5"`
+	// SHA1 is the SHA1 hash of the quarantined file.
+	SHA1 string `json:"sha1"`
+	// SHA256 is the SHA256 hash of the quarantined file.
+	SHA256 string `json:"sha256"`
+	// RuleID is the identifier of the rule that quarantined the file.
+	RuleID string `json:"rule_id,omitempty"`
+	// Rule is the name of the rule that quarantined the file.
+	Rule string `json:"rule"`
+	// Timestamp is the time the file was quarantined.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Vault is the protected directory where quarantined files are stored.
+// The file content is encrypted with AES-256 in counter mode to prevent
+// accidental execution or scanning of the quarantined file. Each entry
+// consists of the encrypted file and the JSON manifest describing the
+// original file.
+type Vault struct {
+	dir         string
+	key         []byte
+	maxFileSize int64
+	protected   []string
+	mu          sync.Mutex
+}
+
+// Open opens the quarantine vault. The vault directory and the
+// encryption key are created if they don't exist.
+func Open(c Config) (*Vault, error) {
+	dir := vaultPath(c.Path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create %q quarantine directory: %v", dir, err)
+	}
+	if err := protectDir(dir); err != nil {
+		return nil, fmt.Errorf("unable to protect %q quarantine directory: %v", dir, err)
+	}
+	key, err := loadKey(filepath.Join(dir, keyFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load quarantine key: %v", err)
+	}
+	return &Vault{dir: dir, key: key, maxFileSize: int64(c.MaxFileSize) * 1024 * 1024, protected: protectedDirs(dir)}, nil
+}
+
+// Quarantine moves the file into the vault. The original file is removed
+// once the encrypted copy and the manifest are written. If the original
+// file can't be removed, for example, because it is locked by the running
+// process, the entry is discarded and the error is returned. Copying
+// the file into the vault is aborted when the context is done.
+func (v *Vault) Quarantine(ctx context.Context, path, ruleID, rule string) (*Entry, error) {
+	if v.isProtected(path) {
+		return nil, fmt.Errorf("refusing to quarantine %s protected file", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	if v.maxFileSize > 0 && fi.Size() > v.maxFileSize {
+		return nil, fmt.Errorf("%s exceeds the maximum quarantine file size (%d bytes)", path, fi.Size())
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry := &Entry{
+		ID:        uuid.New().String(),
+		Path:      path,
+		RuleID:    ruleID,
+		Rule:      rule,
+		Timestamp: time.Now(),
+	}
+	md5h, sha1h, sha256h := md5.New(), sha1.New(), sha256.New()
+	err = v.write(entry.ID, func(w io.Writer) error {
+		n, err := io.Copy(io.MultiWriter(w, md5h, sha1h, sha256h), ctxReader{ctx: ctx, r: f})
+		entry.Size = n
+		return err
+	})
+	if err != nil {
+		v.discard(entry.ID)
+		return nil, err
+	}
+	entry.MD5 = hex.EncodeToString(md5h.Sum(nil))
+	entry.SHA1 = hex.EncodeToString(sha1h.Sum(nil))
+	entry.SHA256 = hex.EncodeToString(sha256h.Sum(nil))
+
+	b, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		v.discard(entry.ID)
+		return nil, err
+	}
+	if err := os.WriteFile(v.file(entry.ID, manifestExt), b, 0600); err != nil {
+		v.discard(entry.ID)
+		return nil, err
+	}
+
+	// the file handle must be closed before the file is removed
+	_ = f.Close()
+	if err := os.Remove(path); err != nil {
+		v.discard(entry.ID)
+		return nil, fmt.Errorf("unable to remove quarantined file: %v", err)
+	}
+	filesQuarantined.Add(1)
+
+	return entry, nil
+}
+
+// ctxReader stops reading the underlying reader once the context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(b []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(b)
+}
+
+// List returns all quarantine entries. The most recently quarantined files come first.
+func (v *Vault) List() ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(v.dir, "*"+manifestExt))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(files))
+	for _, file := range files {
+		entry, err := v.Get(strings.TrimSuffix(filepath.Base(file), manifestExt))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Timestamp.After(entries[j].Timestamp) })
+	return entries, nil
+}
+
+// Get returns the quarantine entry by its identifier.
+func (v *Vault) Get(id string) (*Entry, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrNotFound
+	}
+	b, err := os.ReadFile(v.file(id, manifestExt))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, fmt.Errorf("invalid %s quarantine manifest: %v", id, err)
+	}
+	return &entry, nil
+}
+
+// Restore decrypts the quarantined file to its original location, or
+// to the given path if not empty, and removes the quarantine entry.
+// The existing files are never overwritten. The restored file content
+// is verified against the SHA256 hash recorded in the manifest.
+func (v *Vault) Restore(id, path string) (*Entry, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, err := v.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		path = entry.Path
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	err = v.read(id, io.MultiWriter(f, h))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && hex.EncodeToString(h.Sum(nil)) != entry.SHA256 {
+		err = fmt.Errorf("%s quarantined file is corrupted: hash mismatch", id)
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	v.discard(id)
+
+	return entry, nil
+}
+
+// Purge permanently removes the quarantine entry.
+func (v *Vault) Purge(id string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, err := v.Get(id); err != nil {
+		return err
+	}
+	for _, ext := range []string{dataExt, manifestExt} {
+		if err := os.Remove(v.file(id, ext)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// write encrypts the data produced by the writer function to the entry data file.
+// The data file starts with the random initialization vector.
+func (v *Vault) write(id string, fn func(io.Writer) error) error {
+	block, err := aes.NewCipher(v.key)
+	if err != nil {
+		return err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(v.file(id, dataExt), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(iv); err != nil {
+		return err
+	}
+	if err := fn(&cipher.StreamWriter{S: cipher.NewCTR(block, iv), W: f}); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// read decrypts the entry data file into the writer.
+func (v *Vault) read(id string, w io.Writer) error {
+	block, err := aes.NewCipher(v.key)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(v.file(id, dataExt))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(f, iv); err != nil {
+		return fmt.Errorf("%s quarantined file is corrupted: %v", id, err)
+	}
+	_, err = io.Copy(w, &cipher.StreamReader{S: cipher.NewCTR(block, iv), R: f})
+	return err
+}
+
+// discard removes the entry files from the vault.
+func (v *Vault) discard(id string) {
+	_ = os.Remove(v.file(id, dataExt))
+	_ = os.Remove(v.file(id, manifestExt))
+}
+
+func (v *Vault) file(id, ext string) string {
+	return filepath.Join(v.dir, id+ext)
+}
+
+// loadKey reads and unprotects the vault key. If the
+// key doesn't exist, a new random key is generated and
+// written to the key file in the protected form.
+func loadKey(file string) ([]byte, error) {
+	b, err := os.ReadFile(file)
+	if err == nil {
+		return unprotect(b)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	b, err = protect(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(file, b, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// protectedDirs returns the directories whose files must never be
+// quarantined. Besides the operating system directories, these are
+// the Fibratus installation directory and the vault directory.
+func protectedDirs(vault string) []string {
+	dirs := make([]string, 0, 5)
+	if dir, err := filepath.Abs(vault); err == nil {
+		dirs = append(dirs, dir)
+	}
+	if exe, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Join(filepath.Dir(exe), ".."))
+	}
+	if root := os.Getenv("SystemRoot"); root != "" {
+		for _, dir := range []string{"System32", "SysWOW64", "WinSxS"} {
+			dirs = append(dirs, filepath.Join(root, dir))
+		}
+	}
+	return dirs
+}
+
+// isProtected determines if the file is located in
+// one of the directories that must never be quarantined.
+func (v *Vault) isProtected(path string) bool {
+	path = strings.ToLower(filepath.Clean(path))
+	for _, dir := range v.protected {
+		if strings.HasPrefix(path, strings.ToLower(filepath.Clean(dir))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// vaultPath returns the quarantine directory. If the
+// path is not specified, the Quarantine directory in
+// the installation path is used.
+func vaultPath(path string) string {
+	if path != "" {
+		return path
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return filepath.Join(os.Getenv("PROGRAMFILES"), "Fibratus", "Quarantine")
+	}
+	return filepath.Join(filepath.Dir(exe), "..", "Quarantine")
+}
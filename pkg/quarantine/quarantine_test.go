@@ -0,0 +1,58 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quarantine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsProtected(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("SystemRoot", root)
+	vault := filepath.Join(t.TempDir(), "Quarantine")
+	v := &Vault{dir: vault, protected: protectedDirs(vault)}
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	var tests = []struct {
+		path      string
+		protected bool
+	}{
+		{filepath.Join(root, "System32", "ntdll.dll"), true},
+		{filepath.Join(root, "SYSWOW64", "kernel32.dll"), true},
+		{filepath.Join(root, "WinSxS", "x86_microsoft.windows.common-controls", "comctl32.dll"), true},
+		{filepath.Join(root, "Temp", "dropper.exe"), false},
+		{filepath.Join(root, "System32"), false},
+		{filepath.Join(vault, keyFile), true},
+		{exe, true},
+		{filepath.Join(filepath.Dir(exe), "..", "Config", "fibratus.yml"), true},
+		{filepath.Join(t.TempDir(), "dropper.exe"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.protected, v.isProtected(tt.path))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quarantine
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var payload = append([]byte("MZ\x90\x00\x03\x00\x00\x00This program cannot be run in DOS mode."), bytes.Repeat([]byte{0xcc}, 4096)...)
+
+func dropFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "dropper.exe")
+	require.NoError(t, os.WriteFile(path, payload, 0644))
+	return path
+}
+
+func TestQuarantineRestore(t *testing.T) {
+	v, err := Open(Config{Path: t.TempDir(), MaxFileSize: 1})
+	require.NoError(t, err)
+
+	path := dropFile(t)
+	entry, err := v.Quarantine(context.Background(), path, "6f3d4a4c-2b1e-4c8e-9f0a-5e6b7c8d9e0f", "Suspicious executable dropped")
+	require.NoError(t, err)
+	assert.NoFileExists(t, path)
+
+	sum := sha256.Sum256(payload)
+	assert.Equal(t, hex.EncodeToString(sum[:]), entry.SHA256)
+	assert.Len(t, entry.MD5, 32)
+	assert.Len(t, entry.SHA1, 40)
+	assert.Equal(t, int64(len(payload)), entry.Size)
+	assert.Equal(t, path, entry.Path)
+	assert.Equal(t, "Suspicious executable dropped", entry.Rule)
+
+	// the quarantined file must not contain the original content
+	b, err := os.ReadFile(v.file(entry.ID, dataExt))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(b, payload[:32]))
+
+	entries, err := v.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entry.ID, entries[0].ID)
+	assert.Equal(t, entry.SHA256, entries[0].SHA256)
+	assert.True(t, entry.Timestamp.Equal(entries[0].Timestamp))
+
+	// the file is restored to the original location
+	_, err = v.Restore(entry.ID, "")
+	require.NoError(t, err)
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, payload, b)
+
+	entries, err = v.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 0)
+
+	_, err = v.Restore(entry.ID, "")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestQuarantineRestoreExistingFile(t *testing.T) {
+	v, err := Open(Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	path := dropFile(t)
+	entry, err := v.Quarantine(context.Background(), path, "", "Suspicious executable dropped")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("benign"), 0644))
+	_, err = v.Restore(entry.ID, "")
+	require.Error(t, err)
+	_, err = v.Get(entry.ID)
+	require.NoError(t, err)
+
+	dst := filepath.Join(t.TempDir(), "restored", "dropper.exe")
+	_, err = v.Restore(entry.ID, dst)
+	require.NoError(t, err)
+	assert.FileExists(t, dst)
+}
+
+func TestQuarantinePurge(t *testing.T) {
+	v, err := Open(Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	entry, err := v.Quarantine(context.Background(), dropFile(t), "", "Suspicious executable dropped")
+	require.NoError(t, err)
+
+	require.NoError(t, v.Purge(entry.ID))
+	assert.NoFileExists(t, v.file(entry.ID, dataExt))
+	assert.NoFileExists(t, v.file(entry.ID, manifestExt))
+	require.ErrorIs(t, v.Purge(entry.ID), ErrNotFound)
+}
+
+func TestQuarantineMaxFileSize(t *testing.T) {
+	v, err := Open(Config{Path: t.TempDir(), MaxFileSize: 1})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "large.bin")
+	require.NoError(t, os.WriteFile(path, make([]byte, 2*1024*1024), 0644))
+	_, err = v.Quarantine(context.Background(), path, "", "Suspicious executable dropped")
+	require.Error(t, err)
+	assert.FileExists(t, path)
+
+	_, err = v.Quarantine(context.Background(), filepath.Join(os.Getenv("SystemRoot"), "System32", "ntdll.dll"), "", "Suspicious executable dropped")
+	require.Error(t, err)
+	_, err = v.Quarantine(context.Background(), filepath.Join(v.dir, keyFile), "", "Suspicious executable dropped")
+	require.Error(t, err)
+	assert.FileExists(t, filepath.Join(v.dir, keyFile))
+}
+
+func TestQuarantineKeyPersistence(t *testing.T) {
+	dir := t.TempDir()
+	v, err := Open(Config{Path: dir})
+	require.NoError(t, err)
+	entry, err := v.Quarantine(context.Background(), dropFile(t), "", "Suspicious executable dropped")
+	require.NoError(t, err)
+
+	// the vault reopened with the same key restores the file
+	v, err = Open(Config{Path: dir})
+	require.NoError(t, err)
+	dst := filepath.Join(t.TempDir(), "dropper.exe")
+	_, err = v.Restore(entry.ID, dst)
+	require.NoError(t, err)
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, payload, b)
+}
+
+func TestQuarantineCancelled(t *testing.T) {
+	v, err := Open(Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	path := dropFile(t)
+	_, err = v.Quarantine(ctx, path, "", "Suspicious executable dropped")
+	require.ErrorIs(t, err, context.Canceled)
+	assert.FileExists(t, path)
+	entries, err := v.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
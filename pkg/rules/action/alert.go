@@ -19,6 +19,7 @@
 package action
 
 import (
+	"context"
 	"fmt"
 	"github.com/rabbitstack/fibratus/pkg/alertsender"
 	"github.com/rabbitstack/fibratus/pkg/config"
@@ -27,8 +28,11 @@ import (
 	"strings"
 )
 
-// Alert sends the rule alert via all configured alert senders.
-func Alert(ctx *config.ActionContext, title string, text string, severity string, tags []string) error {
+// Alert builds a task for each alert sender the alert is routed to. Every
+// task emits the rule alert via its sender, so the failure of
+// one sender doesn't prevent other senders from delivering the
+// alert.
+func Alert(ctx *config.ActionContext, title string, text string, severity string, tags []string) ([]Task, error) {
 	var b strings.Builder
 	for _, evt := range ctx.Events {
 		b.WriteString(evt.String())
@@ -36,34 +40,44 @@ func Alert(ctx *config.ActionContext, title string, text string, severity string
 	}
 	log.Infof("sending alert: [%s]. Text: %s Event(s): %s", title, text, b.String())
 
-	senders := alertsender.FindAll()
+	alert := alertsender.NewAlert(
+		title,
+		text,
+		tags,
+		alertsender.ParseSeverityFromString(severity),
+	)
+	alert.ID = ctx.Filter.ID
+	alert.Events = ctx.Events
+	alert.Labels = ctx.Filter.Labels
+	alert.Description = ctx.Filter.Description
+
+	alertsender.Record(alert)
+
+	senders := alert.Route()
 	if len(senders) == 0 {
-		return fmt.Errorf("no alertsenders registered. Alert won't be sent")
+		return nil, fmt.Errorf("no alertsenders registered or routed. Alert won't be sent")
 	}
 
+	tasks := make([]Task, 0, len(senders))
 	for _, sender := range senders {
-		alert := alertsender.NewAlert(
-			title,
-			text,
-			tags,
-			alertsender.ParseSeverityFromString(severity),
-		)
-
-		alert.ID = ctx.Filter.ID
-		alert.Events = ctx.Events
-		alert.Labels = ctx.Filter.Labels
-		alert.Description = ctx.Filter.Description
-
+		alert := alert
 		// strip markdown if not supported by the sender
 		if !sender.SupportsMarkdown() {
 			alert.Text = markdown.Strip(alert.Text)
 		}
 
-		err := sender.Send(alert)
-		if err != nil {
-			return fmt.Errorf("unable to emit alert from rule via [%s] sender: %v", sender.Type(), err)
-		}
+		tasks = append(tasks, Task{
+			Type:    "alert." + sender.Name,
+			Rule:    ctx.Filter.Name,
+			Payload: alert,
+			Run: func(ctx context.Context) error {
+				if err := alertsender.SendContext(ctx, sender.Sender, alert); err != nil {
+					return fmt.Errorf("unable to emit alert from rule via [%s] sender: %w", sender.Name, err)
+				}
+				return nil
+			},
+		})
 	}
 
-	return nil
+	return tasks, nil
 }
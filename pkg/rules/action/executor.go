@@ -0,0 +1,290 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	defaultWorkers   = 4
+	defaultQueueSize = 1024
+	// drainTimeout is the maximum time to wait for pending actions on shutdown
+	drainTimeout = time.Second * 10
+	// abandonTimeout is the maximum time to wait for workers to abandon pending actions
+	abandonTimeout = time.Second * 2
+)
+
+var (
+	actionsExecuted   = expvar.NewMap("action.executed")
+	actionsFailed     = expvar.NewMap("action.failures")
+	actionsRetried    = expvar.NewMap("action.retries")
+	actionsTimedOut   = expvar.NewMap("action.timeouts")
+	actionsDropped    = expvar.NewMap("action.dropped")
+	actionsDuration   = expvar.NewMap("action.duration.ms")
+	actionsQueueDepth = expvar.NewInt("action.queue.depth")
+
+	// ErrActionTimeout signals the action attempt didn't complete within the timeout
+	ErrActionTimeout = errors.New("action timed out")
+	// ErrQueueFull signals the action was rejected because the executor queue is full
+	ErrQueueFull = errors.New("action queue is full")
+	// ErrExecutorClosed signals the action was submitted after the executor was closed
+	ErrExecutorClosed = errors.New("action executor is closed")
+)
+
+// Task is the unit of work carried out by the action executor.
+type Task struct {
+	// Type identifies the action type, e.g. kill or alert.slack.
+	Type string
+	// Rule is the name of the rule that triggered the action.
+	Rule string
+	// Payload is the action data written to the dead-letter log if the action fails.
+	Payload any
+	// Run executes the action. The context is cancelled
+	// when the action attempt exceeds the timeout.
+	Run func(context.Context) error
+}
+
+// deadLetter is the dead-letter log entry of the failed action.
+type deadLetter struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	Rule      string    `json:"rule"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	Payload   any       `json:"payload,omitempty"`
+}
+
+// Executor runs rule actions asynchronously on a bounded pool of workers.
+// Each action attempt is subject to the timeout. Failed actions are retried
+// with exponential backoff unless the error is permanent. Actions that exhaust all retries or can't be
+// enqueued are written to the dead-letter log. Since every action is an
+// independent task, a slow or failing alert sender doesn't block other
+// senders or response actions.
+type Executor struct {
+	config config.ActionsConfig
+	tasks  chan Task
+	quit   chan struct{}
+	wg     sync.WaitGroup
+	closed atomic.Bool
+	mu     sync.RWMutex // guards the tasks channel on close
+
+	dlmu sync.Mutex
+	dl   *lumberjack.Logger
+}
+
+// NewExecutor creates and starts the action executor.
+func NewExecutor(c config.ActionsConfig) *Executor {
+	if c.Workers <= 0 {
+		c.Workers = defaultWorkers
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = defaultQueueSize
+	}
+	e := &Executor{
+		config: c,
+		tasks:  make(chan Task, c.QueueSize),
+		quit:   make(chan struct{}),
+		dl: &lumberjack.Logger{
+			Filename:   deadLetterPath(c.DeadLetterPath),
+			MaxSize:    50,
+			MaxBackups: 3,
+		},
+	}
+	for i := 0; i < c.Workers; i++ {
+		e.wg.Add(1)
+		go e.work()
+	}
+	return e
+}
+
+// Submit enqueues the action for execution. If the queue
+// is full, the action is dropped and dead-lettered.
+func (e *Executor) Submit(t Task) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed.Load() {
+		e.deadLetter(t, 0, ErrExecutorClosed)
+		return
+	}
+	select {
+	case e.tasks <- t:
+		actionsQueueDepth.Add(1)
+	default:
+		actionsDropped.Add(t.Type, 1)
+		e.deadLetter(t, 0, ErrQueueFull)
+	}
+}
+
+// Close stops accepting new actions and waits for pending actions
+// to complete. Actions still running after the drain timeout are
+// abandoned and sent to the dead-letter log before the log is closed.
+func (e *Executor) Close() error {
+	e.mu.Lock()
+	if e.closed.Swap(true) {
+		e.mu.Unlock()
+		return nil
+	}
+	close(e.tasks)
+	e.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(drainTimeout):
+		log.Warnf("timed out waiting for pending rule actions")
+		close(e.quit)
+		// workers dead-letter abandoned actions
+		// before the dead-letter log is closed
+		select {
+		case <-done:
+		case <-time.After(abandonTimeout):
+			log.Warnf("timed out waiting for rule action workers to stop")
+		}
+	}
+
+	e.dlmu.Lock()
+	defer e.dlmu.Unlock()
+	return e.dl.Close()
+}
+
+func (e *Executor) work() {
+	defer e.wg.Done()
+	for t := range e.tasks {
+		actionsQueueDepth.Add(-1)
+		e.execute(t)
+	}
+}
+
+// execute runs the action and retries failed attempts
+// with exponential backoff until the maximum number of
+// retries is reached. Permanent errors are not retried.
+func (e *Executor) execute(t Task) {
+	backoff := e.config.Backoff
+	var err error
+	attempt := 1
+	for ; attempt <= e.config.MaxRetries+1; attempt++ {
+		if attempt > 1 {
+			actionsRetried.Add(t.Type, 1)
+			select {
+			case <-time.After(backoff):
+			case <-e.quit:
+				actionsFailed.Add(t.Type, 1)
+				e.deadLetter(t, attempt-1, err)
+				return
+			}
+			backoff *= 2
+		}
+		start := time.Now()
+		err = e.run(t)
+		actionsDuration.Add(t.Type, time.Since(start).Milliseconds())
+		if err == nil {
+			actionsExecuted.Add(t.Type, 1)
+			return
+		}
+		log.Warnf("[%s] %s action attempt %d failed: %v", t.Rule, t.Type, attempt, err)
+		if alertsender.IsPermanent(err) {
+			break
+		}
+	}
+	actionsFailed.Add(t.Type, 1)
+	e.deadLetter(t, min(attempt, e.config.MaxRetries+1), err)
+}
+
+// run executes a single action attempt. If the attempt doesn't
+// complete within the timeout, or the executor is abandoning
+// pending actions, the context of the attempt is cancelled and
+// the worker moves on without waiting for the attempt to return.
+func (e *Executor) run(t Task) error {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if e.config.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), e.config.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() { errc <- t.Run(ctx) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		actionsTimedOut.Add(t.Type, 1)
+		return ErrActionTimeout
+	case <-e.quit:
+		return ErrExecutorClosed
+	}
+}
+
+func (e *Executor) deadLetter(t Task, attempts int, err error) {
+	log.Errorf("[%s] %s action failed after %d attempt(s): %v", t.Rule, t.Type, attempts, err)
+	entry := deadLetter{
+		Timestamp: time.Now(),
+		Type:      t.Type,
+		Rule:      t.Rule,
+		Attempts:  attempts,
+		Payload:   t.Payload,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		log.Warnf("unable to encode dead-letter entry: %v", err)
+		return
+	}
+	e.dlmu.Lock()
+	defer e.dlmu.Unlock()
+	if _, err := e.dl.Write(append(b, '\n')); err != nil {
+		log.Warnf("unable to write dead-letter entry: %v", err)
+	}
+}
+
+// deadLetterPath returns the dead-letter log path. If
+// not specified, the log is stored in the logs directory.
+func deadLetterPath(path string) string {
+	if path != "" {
+		return path
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return filepath.Join(os.Getenv("PROGRAMFILES"), "Fibratus", "Logs", "actions-dead-letter.log")
+	}
+	return filepath.Join(filepath.Dir(exe), "..", "Logs", "actions-dead-letter.log")
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package action
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readDeadLetters(t *testing.T, path string) []deadLetter {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var entries []deadLetter
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e deadLetter
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func counter(m *expvar.Map, key string) int64 {
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestExecutorRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.log")
+	e := NewExecutor(config.ActionsConfig{MaxRetries: 2, Backoff: time.Millisecond, DeadLetterPath: path})
+
+	var attempts atomic.Int32
+	e.Submit(Task{
+		Type: "kill",
+		Rule: "Suspicious process",
+		Run: func(context.Context) error {
+			if attempts.Add(1) < 3 {
+				return errors.New("access denied")
+			}
+			return nil
+		},
+	})
+
+	require.NoError(t, e.Close())
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Empty(t, readDeadLetters(t, path))
+}
+
+func TestExecutorDeadLetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.log")
+	e := NewExecutor(config.ActionsConfig{MaxRetries: 1, Backoff: time.Millisecond, Timeout: time.Millisecond * 50, DeadLetterPath: path})
+
+	e.Submit(Task{
+		Type:    "alert.slack",
+		Rule:    "Credential dumping",
+		Payload: []uint32{4},
+		Run:     func(context.Context) error { return errors.New("connection refused") },
+	})
+	e.Submit(Task{
+		Type: "alert.mail",
+		Rule: "Credential dumping",
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	require.NoError(t, e.Close())
+
+	entries := readDeadLetters(t, path)
+	require.Len(t, entries, 2)
+	errs := make(map[string]deadLetter)
+	for _, entry := range entries {
+		errs[entry.Type] = entry
+	}
+	assert.Equal(t, "connection refused", errs["alert.slack"].Error)
+	assert.Equal(t, 2, errs["alert.slack"].Attempts)
+	assert.Equal(t, "Credential dumping", errs["alert.slack"].Rule)
+	assert.Equal(t, ErrActionTimeout.Error(), errs["alert.mail"].Error)
+}
+
+func TestExecutorQueueFull(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.log")
+	e := NewExecutor(config.ActionsConfig{Workers: 1, QueueSize: 1, DeadLetterPath: path})
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	e.Submit(Task{Type: "isolate", Run: func(context.Context) error {
+		close(started)
+		<-block
+		return nil
+	}})
+	<-started
+	e.Submit(Task{Type: "isolate", Run: func(context.Context) error { return nil }})
+	e.Submit(Task{Type: "kill", Run: func(context.Context) error { return nil }})
+	close(block)
+
+	require.NoError(t, e.Close())
+
+	entries := readDeadLetters(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "kill", entries[0].Type)
+	assert.Equal(t, ErrQueueFull.Error(), entries[0].Error)
+
+	e.Submit(Task{Type: "kill", Run: func(context.Context) error { return nil }})
+	entries = readDeadLetters(t, path)
+	require.Len(t, entries, 2)
+	assert.Equal(t, ErrExecutorClosed.Error(), entries[1].Error)
+}
+
+func TestExecutorTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.log")
+	e := NewExecutor(config.ActionsConfig{MaxRetries: 2, Backoff: time.Millisecond, Timeout: time.Millisecond * 20, DeadLetterPath: path})
+
+	var attempts, cancelled atomic.Int32
+	timeouts := counter(actionsTimedOut, "alert.webhook")
+	e.Submit(Task{
+		Type: "alert.webhook",
+		Rule: "Credential dumping",
+		Run: func(ctx context.Context) error {
+			attempts.Add(1)
+			select {
+			case <-ctx.Done():
+				cancelled.Add(1)
+				return ctx.Err()
+			case <-time.After(time.Second):
+				// the late result is ignored
+				return nil
+			}
+		},
+	})
+
+	start := time.Now()
+	require.NoError(t, e.Close())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Eventually(t, func() bool { return cancelled.Load() == 3 }, time.Second, time.Millisecond*10)
+	assert.Equal(t, int64(3), counter(actionsTimedOut, "alert.webhook")-timeouts)
+	entries := readDeadLetters(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ErrActionTimeout.Error(), entries[0].Error)
+	assert.Equal(t, 3, entries[0].Attempts)
+}
+
+func TestExecutorHungAction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.log")
+	e := NewExecutor(config.ActionsConfig{Workers: 1, Backoff: time.Millisecond, Timeout: time.Millisecond * 20, DeadLetterPath: path})
+
+	// the hung sender ignores the cancellation
+	// but doesn't hold the worker
+	hung := make(chan struct{})
+	defer close(hung)
+	var executed atomic.Int32
+	e.Submit(Task{Type: "alert.slack", Run: func(context.Context) error { <-hung; return nil }})
+	e.Submit(Task{Type: "kill", Run: func(context.Context) error { executed.Add(1); return nil }})
+
+	require.NoError(t, e.Close())
+	assert.Equal(t, int32(1), executed.Load())
+	entries := readDeadLetters(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "alert.slack", entries[0].Type)
+}
+
+func TestExecutorPermanentError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.log")
+	e := NewExecutor(config.ActionsConfig{MaxRetries: 3, Backoff: time.Millisecond, DeadLetterPath: path})
+
+	var attempts atomic.Int32
+	e.Submit(Task{
+		Type: "alert.pagerduty",
+		Rule: "Credential dumping",
+		Run: func(context.Context) error {
+			attempts.Add(1)
+			return alertsender.Permanent(errors.New("invalid routing key"))
+		},
+	})
+
+	require.NoError(t, e.Close())
+	assert.Equal(t, int32(1), attempts.Load())
+	entries := readDeadLetters(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 1, entries[0].Attempts)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package action
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+
+	"github.com/rabbitstack/fibratus/pkg/quarantine"
+	"github.com/rabbitstack/fibratus/pkg/util/multierror"
+	log "github.com/sirupsen/logrus"
+)
+
+// Quarantine moves the files into the quarantine vault. Files that
+// no longer exist are skipped, so the action can be safely retried
+// after some of the files were already quarantined. Remaining files
+// are not quarantined once the context is done.
+func Quarantine(ctx context.Context, vault *quarantine.Vault, files []string, ruleID, rule string) error {
+	errs := make([]error, 0)
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		entry, err := vault.Quarantine(ctx, file, ruleID, rule)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			errs = append(errs, err)
+			continue
+		}
+		log.Infof("quarantined %s file: id=%s sha256=%s rule=%s", file, entry.ID, entry.SHA256, rule)
+	}
+	return multierror.Wrap(errs...)
+}
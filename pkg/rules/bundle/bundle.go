@@ -0,0 +1,356 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bundle implements the signed rule bundle format. A bundle is
+// a gzip-compressed tar archive that packs rule and macro files along
+// with the manifest describing the digest of each file. The manifest is
+// signed with the Ed25519 private key and the detached signature travels
+// inside the archive. Bundles are verified against the set of trusted
+// public keys before any of the rules is handed over to the rule engine.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// manifestFile is the name of the manifest file inside the archive
+	manifestFile = "manifest.json"
+	// signatureFile is the name of the detached manifest signature file
+	signatureFile = "manifest.sig"
+	// rulesDir is the archive directory where rule files are stored
+	rulesDir = "rules"
+	// macrosDir is the archive directory where macro files are stored
+	macrosDir = "macros"
+	// formatVersion is the current version of the bundle format
+	formatVersion = 1
+	// maxSize is the maximum size of the uncompressed bundle content
+	maxSize = 64 * 1024 * 1024
+)
+
+var (
+	// ErrUnsigned is returned when the bundle lacks the manifest signature.
+	ErrUnsigned = errors.New("rule bundle is not signed")
+	// ErrNoPublicKeys is returned when the bundle can't be verified because no trusted public keys are given.
+	ErrNoPublicKeys = errors.New("no public keys configured to verify the rule bundle signature")
+	// ErrSignatureMismatch is returned when the manifest signature is not produced by any of the trusted keys.
+	ErrSignatureMismatch = errors.New("rule bundle signature doesn't match any of the trusted public keys")
+	// ErrTampered is returned when the bundle content doesn't correspond to the signed manifest.
+	ErrTampered = func(file, reason string) error {
+		return fmt.Errorf("rule bundle is tampered: %s %s", file, reason)
+	}
+)
+
+// File represents a single rule or macro file in the bundle.
+type File struct {
+	// Name is the file name relative to the rules or macros directory.
+	Name string
+	// Data is the raw file content.
+	Data []byte
+}
+
+// ManifestEntry describes the file packed in the bundle.
+type ManifestEntry struct {
+	// Path is the archive path of the file.
+	Path string `json:"path"`
+	// SHA256 is the hex-encoded SHA256 digest of the file content.
+	SHA256 string `json:"sha256"`
+	// Size is the file size in bytes.
+	Size int64 `json:"size"`
+}
+
+// Manifest contains the bundle metadata along with digests of every file in the bundle.
+type Manifest struct {
+	// Version is the bundle format version.
+	Version int `json:"version"`
+	// Created is the timestamp when the bundle was signed.
+	Created time.Time `json:"created"`
+	// Files contains the manifest entry for each bundle file.
+	Files []ManifestEntry `json:"files"`
+}
+
+// Bundle is the verified rule bundle.
+type Bundle struct {
+	// Manifest is the signed bundle manifest.
+	Manifest Manifest
+	// Rules contains rule files packed in the bundle.
+	Rules []File
+	// Macros contains macro files packed in the bundle.
+	Macros []File
+	// KeyID is the fingerprint of the public key that verified the bundle.
+	KeyID string
+}
+
+// IsBundle determines if the given byte slice looks like a bundle archive
+// by checking the gzip magic number.
+func IsBundle(b []byte) bool {
+	return len(b) > 2 && b[0] == 0x1f && b[1] == 0x8b
+}
+
+// Builder assembles the rule bundle.
+type Builder struct {
+	rules  []File
+	macros []File
+}
+
+// NewBuilder creates an empty bundle builder.
+func NewBuilder() *Builder {
+	return &Builder{rules: make([]File, 0), macros: make([]File, 0)}
+}
+
+// AddRule adds the rule file to the bundle. Only the base
+// name of the slash-separated file name is retained.
+func (b *Builder) AddRule(name string, data []byte) {
+	b.rules = append(b.rules, File{Name: path.Base(name), Data: data})
+}
+
+// AddMacro adds the macro file to the bundle. Only the base
+// name of the slash-separated file name is retained.
+func (b *Builder) AddMacro(name string, data []byte) {
+	b.macros = append(b.macros, File{Name: path.Base(name), Data: data})
+}
+
+// Sign builds the manifest, signs it with the provided
+// private key and produces the compressed bundle archive.
+func (b *Builder) Sign(key ed25519.PrivateKey) ([]byte, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 private key size: %d", len(key))
+	}
+	if len(b.rules) == 0 {
+		return nil, errors.New("rule bundle requires at least one rule file")
+	}
+
+	entries := make(map[string][]byte)
+	for _, f := range b.rules {
+		entries[path.Join(rulesDir, f.Name)] = f.Data
+	}
+	for _, f := range b.macros {
+		entries[path.Join(macrosDir, f.Name)] = f.Data
+	}
+	if len(entries) != len(b.rules)+len(b.macros) {
+		return nil, errors.New("rule bundle contains duplicate file names")
+	}
+
+	paths := make([]string, 0, len(entries))
+	for p := range entries {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	manifest := Manifest{
+		Version: formatVersion,
+		Created: time.Now().UTC(),
+		Files:   make([]ManifestEntry, 0, len(paths)),
+	}
+	for _, p := range paths {
+		data := entries[p]
+		manifest.Files = append(manifest.Files, ManifestEntry{Path: p, SHA256: digest(data), Size: int64(len(data))})
+	}
+	m, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, m))
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	write := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: manifest.Created,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := write(manifestFile, m); err != nil {
+		return nil, err
+	}
+	if err := write(signatureFile, []byte(sig)); err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		if err := write(p, entries[p]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Open decompresses the bundle archive and verifies the manifest
+// signature against the trusted public keys. Every file in the archive
+// must be listed in the manifest and its digest must match the signed
+// digest. Otherwise, the bundle is considered tampered and rejected.
+func Open(b []byte, keys []ed25519.PublicKey) (*Bundle, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoPublicKeys
+	}
+	if !IsBundle(b) {
+		return nil, errors.New("invalid rule bundle: not a gzip archive")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule bundle: %v", err)
+	}
+	defer gz.Close()
+
+	var (
+		manifest []byte
+		sig      []byte
+		files    = make(map[string][]byte)
+		size     int64
+	)
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule bundle: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, ErrTampered(hdr.Name, "is not a regular file")
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || strings.HasPrefix(name, "..") {
+			return nil, ErrTampered(hdr.Name, "has an illegal path")
+		}
+		size += hdr.Size
+		if size > maxSize {
+			return nil, fmt.Errorf("invalid rule bundle: content exceeds %d bytes", maxSize)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, hdr.Size))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule bundle: %v", err)
+		}
+		switch name {
+		case manifestFile:
+			manifest = data
+		case signatureFile:
+			sig = data
+		default:
+			if _, ok := files[name]; ok {
+				return nil, ErrTampered(name, "is duplicated")
+			}
+			files[name] = data
+		}
+	}
+
+	if manifest == nil {
+		return nil, errors.New("invalid rule bundle: missing manifest")
+	}
+	if len(sig) == 0 {
+		return nil, ErrUnsigned
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, ErrUnsigned
+	}
+
+	var keyID string
+	for _, key := range keys {
+		if ed25519.Verify(key, manifest, signature) {
+			keyID = Fingerprint(key)
+			break
+		}
+	}
+	if keyID == "" {
+		return nil, ErrSignatureMismatch
+	}
+
+	bundle := &Bundle{KeyID: keyID, Rules: make([]File, 0), Macros: make([]File, 0)}
+	if err := json.Unmarshal(manifest, &bundle.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid rule bundle manifest: %v", err)
+	}
+	if bundle.Manifest.Version > formatVersion {
+		return nil, fmt.Errorf("unsupported rule bundle version %d", bundle.Manifest.Version)
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range bundle.Manifest.Files {
+		data, ok := files[entry.Path]
+		if !ok {
+			return nil, ErrTampered(entry.Path, "is listed in the manifest but missing in the bundle")
+		}
+		if int64(len(data)) != entry.Size || digest(data) != entry.SHA256 {
+			return nil, ErrTampered(entry.Path, "digest doesn't match the manifest")
+		}
+		seen[entry.Path] = true
+		f := File{Name: path.Base(entry.Path), Data: data}
+		switch path.Dir(entry.Path) {
+		case rulesDir:
+			bundle.Rules = append(bundle.Rules, f)
+		case macrosDir:
+			bundle.Macros = append(bundle.Macros, f)
+		default:
+			return nil, ErrTampered(entry.Path, "is not a rule or macro file")
+		}
+	}
+	for name := range files {
+		if !seen[name] {
+			return nil, ErrTampered(name, "is not listed in the manifest")
+		}
+	}
+
+	return bundle, nil
+}
+
+// Fingerprint returns the short fingerprint of the public key.
+func Fingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var rule = []byte(`name: only network category
+id: 313933e7-8eb9-45d9-81af-0305fee70e29
+version: 1.0.0
+condition: evt.category = 'net'
+min-engine-version: 2.0.0
+`)
+
+var macro = []byte(`- macro: spawn_process
+  expr: evt.name = 'CreateProcess'
+`)
+
+// rewrite repacks the bundle archive and gives the
+// caller a chance to alter or drop archive files and
+// append extra files.
+func rewrite(t *testing.T, b []byte, fn func(name string, data []byte) []byte, extra map[string][]byte) []byte {
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	write := func(name string, data []byte) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if data = fn(hdr.Name, data); data != nil {
+			write(hdr.Name, data)
+		}
+	}
+	for name, data := range extra {
+		write(name, data)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
+
+func TestSignAndOpen(t *testing.T) {
+	pub, priv, err := GenerateKey()
+	require.NoError(t, err)
+
+	b := NewBuilder()
+	b.AddRule("rules/network.yml", rule)
+	b.AddMacro("macros.yml", macro)
+
+	data, err := b.Sign(priv)
+	require.NoError(t, err)
+	require.True(t, IsBundle(data))
+
+	bundle, err := Open(data, []ed25519.PublicKey{pub})
+	require.NoError(t, err)
+
+	assert.Equal(t, Fingerprint(pub), bundle.KeyID)
+	assert.Equal(t, 1, bundle.Manifest.Version)
+	require.Len(t, bundle.Manifest.Files, 2)
+	require.Len(t, bundle.Rules, 1)
+	require.Len(t, bundle.Macros, 1)
+	assert.Equal(t, "network.yml", bundle.Rules[0].Name)
+	assert.Equal(t, rule, bundle.Rules[0].Data)
+	assert.Equal(t, "macros.yml", bundle.Macros[0].Name)
+	assert.Equal(t, macro, bundle.Macros[0].Data)
+}
+
+func TestOpenRejected(t *testing.T) {
+	pub, priv, err := GenerateKey()
+	require.NoError(t, err)
+	other, _, err := GenerateKey()
+	require.NoError(t, err)
+
+	b := NewBuilder()
+	b.AddRule("network.yml", rule)
+	b.AddMacro("macros.yml", macro)
+
+	data, err := b.Sign(priv)
+	require.NoError(t, err)
+
+	keep := func(name string, data []byte) []byte { return data }
+
+	var tests = []struct {
+		name string
+		data []byte
+		keys []ed25519.PublicKey
+		err  string
+	}{
+		{
+			"no public keys",
+			data,
+			nil,
+			ErrNoPublicKeys.Error(),
+		},
+		{
+			"untrusted key",
+			data,
+			[]ed25519.PublicKey{other},
+			ErrSignatureMismatch.Error(),
+		},
+		{
+			"unsigned",
+			rewrite(t, data, func(name string, data []byte) []byte {
+				if name == signatureFile {
+					return nil
+				}
+				return data
+			}, nil),
+			[]ed25519.PublicKey{pub},
+			ErrUnsigned.Error(),
+		},
+		{
+			"tampered rule",
+			rewrite(t, data, func(name string, data []byte) []byte {
+				if name == "rules/network.yml" {
+					return bytes.ReplaceAll(data, []byte("net"), []byte("file"))
+				}
+				return data
+			}, nil),
+			[]ed25519.PublicKey{pub},
+			"rule bundle is tampered: rules/network.yml digest doesn't match the manifest",
+		},
+		{
+			"tampered manifest",
+			rewrite(t, data, func(name string, data []byte) []byte {
+				if name == manifestFile {
+					return bytes.ReplaceAll(data, []byte(`"version": 1`), []byte(`"version": 0`))
+				}
+				return data
+			}, nil),
+			[]ed25519.PublicKey{pub},
+			ErrSignatureMismatch.Error(),
+		},
+		{
+			"missing rule",
+			rewrite(t, data, func(name string, data []byte) []byte {
+				if name == "macros/macros.yml" {
+					return nil
+				}
+				return data
+			}, nil),
+			[]ed25519.PublicKey{pub},
+			"rule bundle is tampered: macros/macros.yml is listed in the manifest but missing in the bundle",
+		},
+		{
+			"injected rule",
+			rewrite(t, data, keep, map[string][]byte{"rules/evil.yml": rule}),
+			[]ed25519.PublicKey{pub},
+			"rule bundle is tampered: rules/evil.yml is not listed in the manifest",
+		},
+		{
+			"not a bundle",
+			rule,
+			[]ed25519.PublicKey{pub},
+			"invalid rule bundle: not a gzip archive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Open(tt.data, tt.keys)
+			require.Error(t, err)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestKeysEncoding(t *testing.T) {
+	pub, priv, err := GenerateKey()
+	require.NoError(t, err)
+
+	pem, err := EncodePrivateKey(priv)
+	require.NoError(t, err)
+	decoded, err := DecodePrivateKey(pem)
+	require.NoError(t, err)
+	assert.True(t, priv.Equal(decoded))
+
+	keys, err := ParsePublicKeys([]string{EncodePublicKey(pub)})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, pub.Equal(keys[0]))
+
+	_, err = ParsePublicKey("aGVsbG8=")
+	require.Error(t, err)
+}
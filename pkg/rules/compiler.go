@@ -55,6 +55,9 @@ var (
 	ErrUnknownCategoryName = func(rule, name string) error {
 		return fmt.Errorf("rule %s references an invalid event category %q in the evt.category field", rule, name)
 	}
+	ErrUnknownSuppressField = func(rule, name string) error {
+		return fmt.Errorf("rule %s references an invalid field %q in the suppress block", rule, name)
+	}
 )
 
 type compiler struct {
@@ -84,10 +87,13 @@ func (c *compiler) compile() (map[*config.FilterConfig]filter.Filter, *config.Ru
 	filters := make(map[*config.FilterConfig]filter.Filter)
 
 	for _, f := range c.config.GetFilters() {
-		if f.IsDisabled() {
-			log.Warnf("[%s] rule is disabled", f.Name)
+		if state := c.config.Filters.RuleState(f); !state.Enabled {
+			log.Warnf("[%s] rule is disabled: %s", f.Name, state.Reason)
 			continue
 		}
+		if f.Status == config.StatusDeprecated {
+			log.Warnf("[%s] rule is deprecated and will be removed in future versions", f.Name)
+		}
 
 		filtersCount.Add(1)
 
@@ -136,6 +142,15 @@ func (c *compiler) compile() (map[*config.FilterConfig]filter.Filter, *config.Ru
 			}
 		}
 
+		// validate suppression fields
+		if f.Suppress != nil {
+			for _, name := range f.Suppress.By {
+				if n, _, _ := strings.Cut(name, "["); !fields.IsField(n) {
+					return nil, nil, ErrUnknownSuppressField(f.Name, name)
+				}
+			}
+		}
+
 		// visit filter or sequence expressions
 		// to extract approver predicates
 		expr := fltr.Expr()
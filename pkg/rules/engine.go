@@ -19,6 +19,7 @@
 package rules
 
 import (
+	"context"
 	"expvar"
 	"fmt"
 	"sync"
@@ -29,7 +30,9 @@ import (
 	"github.com/rabbitstack/fibratus/pkg/filter"
 	"github.com/rabbitstack/fibratus/pkg/filter/fields"
 	"github.com/rabbitstack/fibratus/pkg/ps"
+	"github.com/rabbitstack/fibratus/pkg/quarantine"
 	"github.com/rabbitstack/fibratus/pkg/rules/action"
+	"github.com/rabbitstack/fibratus/pkg/rules/risk"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -65,6 +68,13 @@ type Engine struct {
 
 	compiler *compiler
 
+	suppressor *suppressor
+	scorer     *risk.Scorer
+	executor   *action.Executor
+
+	vault *quarantine.Vault
+	vmu   sync.Mutex // guards the lazy initialization of the quarantine vault
+
 	matchFunc RuleMatchFunc
 }
 
@@ -139,9 +149,16 @@ func NewEngine(psnap ps.Snapshotter, config *config.Config) *Engine {
 		config:    config,
 		scavenger: time.NewTicker(sequenceGcInterval),
 		compiler:  newCompiler(psnap, config),
+		executor:  action.NewExecutor(config.Actions),
+	}
+	e.suppressor = newSuppressor(e.summaryAlert)
+
+	if config.Risk.Enabled {
+		e.scorer = risk.NewScorer(config.Risk, e.riskThresholdExceeded)
 	}
 
 	go e.gcSequences()
+	go e.suppressor.run()
 
 	return e
 }
@@ -210,6 +227,10 @@ func (e *Engine) Compile() (*config.RulesCompileResult, error) {
 	return rs, nil
 }
 
+// RiskScorer returns the entity risk scorer. If
+// risk scoring is disabled, nil is returned.
+func (e *Engine) RiskScorer() *risk.Scorer { return e.scorer }
+
 func (e *Engine) RegisterMatchFunc(fn RuleMatchFunc) {
 	e.matchFunc = fn
 }
@@ -268,10 +289,10 @@ func (e *Engine) ProcessEvent(evt *event.Event) (bool, error) {
 	return matches, nil
 }
 
-// processActions executes rule actions
-// on behalf of rule matches. Actions are
-// categorized into implicit and explicit
-// actions.
+// processActions submits rule actions on
+// behalf of rule matches to the action
+// executor. Actions are categorized into
+// implicit and explicit actions.
 // Sending an alert is an implicit action
 // carried out each time there is a rule
 // match. Other actions are executed if
@@ -285,9 +306,14 @@ func (e *Engine) processActions() error {
 		f, evts := m.ctx.Filter, m.ctx.Events
 		filterMatches.Add(f.Name, 1)
 		log.Debugf("[%s] rule matched", f.Name)
-		err := action.Alert(m.ctx, f.Name, filter.InterpolateFields(f.Output, evts), f.Severity, f.Tags)
-		if err != nil {
-			return ErrRuleAction(f.Name, err)
+		if e.scorer != nil && f.Risk > 0 {
+			e.scorer.Add(f.ID, f.Name, float64(f.Risk), riskEntities(evts)...)
+		}
+		// duplicate matches within the suppression
+		// window don't emit alerts, but other actions
+		// are still executed
+		if !e.isRiskOnly(f) && !e.suppressor.suppress(m.ctx) {
+			e.alert(m.ctx, f.Name, filter.InterpolateFields(f.Output, evts), f.Severity, f.Tags)
 		}
 
 		actions, err := f.DecodeActions()
@@ -295,18 +321,49 @@ func (e *Engine) processActions() error {
 			return err
 		}
 
+		// the running image of the process is only
+		// quarantined if the kill action precedes
+		// the quarantine action
+		var killed bool
 		for _, act := range actions {
 			switch t := act.(type) {
 			case config.KillAction:
-				log.Infof("executing kill action: pids=%v rule=%s", m.ctx.UniquePids(), f.Name)
-				if err := action.Kill(m.ctx.UniquePids()); err != nil {
-					return ErrRuleAction(f.Name, err)
-				}
+				killed = true
+				pids := m.ctx.UniquePids()
+				e.executor.Submit(action.Task{
+					Type:    "kill",
+					Rule:    f.Name,
+					Payload: pids,
+					Run: func(context.Context) error {
+						log.Infof("executing kill action: pids=%v rule=%s", pids, f.Name)
+						return action.Kill(pids)
+					},
+				})
 			case config.IsolateAction:
-				log.Infof("executing isolate action: rule=%s", f.Name)
-				if err := action.Isolate(t.Whitelist); err != nil {
-					return ErrRuleAction(f.Name, err)
-				}
+				e.executor.Submit(action.Task{
+					Type:    "isolate",
+					Rule:    f.Name,
+					Payload: t.Whitelist,
+					Run: func(context.Context) error {
+						log.Infof("executing isolate action: rule=%s", f.Name)
+						return action.Isolate(t.Whitelist)
+					},
+				})
+			case config.QuarantineAction:
+				files := m.ctx.UniqueFiles(killed)
+				e.executor.Submit(action.Task{
+					Type:    "quarantine",
+					Rule:    f.Name,
+					Payload: files,
+					Run: func(ctx context.Context) error {
+						log.Infof("executing quarantine action: files=%v rule=%s", files, f.Name)
+						vault, err := e.quarantineVault()
+						if err != nil {
+							return err
+						}
+						return action.Quarantine(ctx, vault, files, f.ID, f.Name)
+					},
+				})
 			}
 		}
 	}
@@ -314,9 +371,52 @@ func (e *Engine) processActions() error {
 	return nil
 }
 
+// quarantineVault opens the quarantine vault on the first
+// execution of the quarantine action.
+func (e *Engine) quarantineVault() (*quarantine.Vault, error) {
+	e.vmu.Lock()
+	defer e.vmu.Unlock()
+	if e.vault != nil {
+		return e.vault, nil
+	}
+	vault, err := quarantine.Open(e.config.Quarantine)
+	if err != nil {
+		return nil, err
+	}
+	e.vault = vault
+	return vault, nil
+}
+
+// alert submits the alert for each configured alert sender to the action executor.
+func (e *Engine) alert(ctx *config.ActionContext, title, text, severity string, tags []string) {
+	tasks, err := action.Alert(ctx, title, text, severity, tags)
+	if err != nil {
+		log.Errorf("unable to execute rule action: %v", ErrRuleAction(ctx.Filter.Name, err))
+		return
+	}
+	for _, task := range tasks {
+		e.executor.Submit(task)
+	}
+}
+
+// Close stops the action executor after
+// pending rule actions are completed. The
+// summary alerts of open suppression windows
+// are sent before the executor is stopped.
+func (e *Engine) Close() error {
+	if e.scorer != nil {
+		e.scorer.Close()
+	}
+	e.suppressor.stop()
+	return e.executor.Close()
+}
+
 func (e *Engine) appendMatch(f *config.FilterConfig, evts ...*event.Event) {
 	for _, evt := range evts {
 		evt.AddMeta(event.RuleNameKey, f.Name)
+		if f.Severity != "" {
+			evt.AddMeta(event.RuleSeverityKey, f.Severity)
+		}
 		for k, v := range f.Labels {
 			evt.AddMeta(event.MetadataKey(k), v)
 		}
@@ -507,7 +507,9 @@ func TestKillAction(t *testing.T) {
 
 	require.True(t, sys.IsProcessRunning(pi.Process))
 	require.True(t, wrapProcessEvent(evt, e.ProcessEvent))
-	require.False(t, sys.IsProcessRunning(pi.Process))
+	require.Eventually(t, func() bool {
+		return !sys.IsProcessRunning(pi.Process)
+	}, time.Second*5, time.Millisecond*100)
 }
 
 func BenchmarkRunRules(b *testing.B) {
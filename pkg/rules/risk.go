@@ -0,0 +1,108 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rabbitstack/fibratus/pkg/alertsender"
+	"github.com/rabbitstack/fibratus/pkg/config"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/rules/risk"
+	"github.com/rabbitstack/fibratus/pkg/util/hostname"
+)
+
+// RiskThresholdExceeded is the title of the alert sent when the entity risk score exceeds the threshold
+const RiskThresholdExceeded = "Risk threshold exceeded"
+
+// isRiskOnly determines if the rule only contributes to
+// entity risk scores without emitting the alert on each
+// match. This happens when the rule severity is below the
+// minimum alert severity of risk-scored rules.
+func (e *Engine) isRiskOnly(f *config.FilterConfig) bool {
+	if e.scorer == nil || f.Risk == 0 {
+		return false
+	}
+	return alertsender.ParseSeverityFromString(f.Severity) < alertsender.ParseSeverityFromString(e.config.Risk.AlertMinSeverity)
+}
+
+// riskThresholdExceeded sends the alert with the list
+// of rules that contributed to the entity risk score.
+func (e *Engine) riskThresholdExceeded(score risk.Score) {
+	ent := score.Entity
+	name := ent.Name
+	if name == "" {
+		name = ent.ID
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Risk score of the %s `%s` reached %s. Contributing rules:\n", ent.Type, name, score))
+	for _, c := range score.Contributions {
+		b.WriteString(fmt.Sprintf("\n- %s (matches: %d, score: %.2f)", c.RuleName, c.Matches, c.Score))
+	}
+
+	ctx := &config.ActionContext{
+		Events: []*event.Event{},
+		Filter: &config.FilterConfig{
+			ID:       "risk-" + ent.Key(),
+			Name:     RiskThresholdExceeded,
+			Severity: alertsender.High.String(),
+			Labels: map[string]string{
+				"risk.entity.type": string(ent.Type),
+				"risk.entity.id":   ent.ID,
+				"risk.score":       score.String(),
+			},
+		},
+	}
+	e.alert(ctx, RiskThresholdExceeded, b.String(), ctx.Filter.Severity, []string{"risk"})
+}
+
+// riskEntities returns the process, user, and host
+// entities that are scored for the rule match.
+func riskEntities(evts []*event.Event) []risk.Entity {
+	entities := make([]risk.Entity, 0, len(evts)*2+1)
+	seen := make(map[string]bool)
+	add := func(ent risk.Entity) {
+		if seen[ent.Key()] {
+			return
+		}
+		seen[ent.Key()] = true
+		entities = append(entities, ent)
+	}
+
+	for _, evt := range evts {
+		ps := evt.PS
+		if ps == nil {
+			continue
+		}
+		add(risk.Entity{Type: risk.Process, ID: strconv.FormatUint(ps.UUID(), 10), Name: ps.Exe})
+		if ps.SID != "" {
+			user := ps.Username
+			if ps.Domain != "" {
+				user = ps.Domain + "\\" + ps.Username
+			}
+			add(risk.Entity{Type: risk.User, ID: ps.SID, Name: user})
+		}
+	}
+	add(risk.Entity{Type: risk.Host, ID: hostname.Get(), Name: hostname.Get()})
+
+	return entities
+}
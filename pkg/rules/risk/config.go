@@ -0,0 +1,68 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package risk
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	enabled          = "risk.enabled"
+	threshold        = "risk.threshold"
+	halfLife         = "risk.half-life"
+	entities         = "risk.entities"
+	alertMinSeverity = "risk.alert-min-severity"
+)
+
+// Config contains the settings that influence the behaviour of the risk scorer.
+type Config struct {
+	// Enabled indicates if rules contribute risk scores to entities.
+	Enabled bool `json:"risk.enabled" yaml:"risk.enabled"`
+	// Threshold is the score at which the risk threshold exceeded alert is sent.
+	Threshold int `json:"risk.threshold" yaml:"risk.threshold"`
+	// HalfLife is the period after which the entity risk score decays to half of its value.
+	HalfLife time.Duration `json:"risk.half-life" yaml:"risk.half-life"`
+	// Entities contains the entity types which are scored.
+	Entities []string `json:"risk.entities" yaml:"risk.entities"`
+	// AlertMinSeverity is the minimum severity of risk-scored rules that still emit
+	// alerts on every match. Matches of rules with lower severity only contribute to
+	// entity risk scores.
+	AlertMinSeverity string `json:"risk.alert-min-severity" yaml:"risk.alert-min-severity"`
+}
+
+// AddFlags registers persistent flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.Bool(enabled, false, "Indicates if rules contribute risk scores to process, user, and host entities")
+	flags.Int(threshold, 100, "Specifies the entity risk score at which the risk threshold exceeded alert is sent")
+	flags.Duration(halfLife, time.Hour, "Specifies the period after which the entity risk score decays to half of its value")
+	flags.StringSlice(entities, []string{string(Process), string(User), string(Host)}, "Comma-separated list of entity types which are scored")
+	flags.String(alertMinSeverity, "low", "Specifies the minimum severity of risk-scored rules that emit alerts on every match")
+}
+
+// InitFromViper initializes risk scorer config from Viper.
+func (c *Config) InitFromViper(v *viper.Viper) {
+	c.Enabled = v.GetBool(enabled)
+	c.Threshold = v.GetInt(threshold)
+	c.HalfLife = v.GetDuration(halfLife)
+	c.Entities = v.GetStringSlice(entities)
+	c.AlertMinSeverity = v.GetString(alertMinSeverity)
+}
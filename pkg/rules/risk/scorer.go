@@ -0,0 +1,293 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package risk implements the scorer that accumulates risk scores
+// contributed by rule matches to process, user, and host entities.
+// Scores decay exponentially over time. When the score of the entity
+// reaches the configured threshold, the threshold exceeded function
+// is invoked with the list of contributing rules.
+package risk
+
+import (
+	"expvar"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EntityType identifies the kind of the scored entity.
+type EntityType string
+
+const (
+	// Process is the process entity identified by the process UUID.
+	Process EntityType = "process"
+	// User is the user entity identified by the security identifier.
+	User EntityType = "user"
+	// Host is the host entity identified by the host name.
+	Host EntityType = "host"
+)
+
+// minScore is the score below which the entity is evicted
+const minScore = 0.5
+
+var (
+	// gcInterval determines how often decayed entities are evicted
+	gcInterval = time.Minute
+
+	// scoresGauge exposes current entity risk scores
+	scoresGauge = expvar.NewMap("risk.scores")
+	// thresholdExceeded counts the number of times the risk threshold was exceeded
+	thresholdExceeded = expvar.NewInt("risk.threshold.exceeded")
+)
+
+// Entity describes the scored entity.
+type Entity struct {
+	// Type is the entity type.
+	Type EntityType `json:"type"`
+	// ID uniquely identifies the entity within the entity type.
+	ID string `json:"id"`
+	// Name is the human-friendly entity name, e.g. process executable path.
+	Name string `json:"name"`
+}
+
+// Key returns the unique key of the entity.
+func (e Entity) Key() string { return string(e.Type) + ":" + e.ID }
+
+// Contribution represents the risk contributed by the rule.
+type Contribution struct {
+	// RuleID is the identifier of the contributing rule.
+	RuleID string `json:"rule-id"`
+	// RuleName is the name of the contributing rule.
+	RuleName string `json:"rule-name"`
+	// Matches is the number of rule matches.
+	Matches int `json:"matches"`
+	// Score is the cumulative score contributed by the rule.
+	Score float64 `json:"score"`
+	// LastSeen is the timestamp of the most recent rule match.
+	LastSeen time.Time `json:"last-seen"`
+}
+
+// Score is the snapshot of the entity risk score.
+type Score struct {
+	Entity Entity `json:"entity"`
+	// Score is the decayed risk score.
+	Score float64 `json:"score"`
+	// Exceeded indicates if the score is above the risk threshold.
+	Exceeded bool `json:"exceeded"`
+	// Contributions contains contributing rules sorted by score.
+	Contributions []Contribution `json:"contributions"`
+	// UpdatedAt is the timestamp of the most recent score change.
+	UpdatedAt time.Time `json:"updated-at"`
+}
+
+// ThresholdFunc is invoked when the entity risk score exceeds the threshold.
+type ThresholdFunc func(Score)
+
+type entityScore struct {
+	entity        Entity
+	score         float64
+	exceeded      bool
+	contributions map[string]*Contribution
+	updated       time.Time
+}
+
+func (e *entityScore) snapshot() Score {
+	s := Score{
+		Entity:        e.entity,
+		Score:         e.score,
+		Exceeded:      e.exceeded,
+		Contributions: make([]Contribution, 0, len(e.contributions)),
+		UpdatedAt:     e.updated,
+	}
+	for _, c := range e.contributions {
+		s.Contributions = append(s.Contributions, *c)
+	}
+	slices.SortFunc(s.Contributions, func(a, b Contribution) int {
+		return cmpDesc(a.Score, b.Score)
+	})
+	return s
+}
+
+// Scorer accumulates and decays entity risk scores.
+type Scorer struct {
+	mu       sync.Mutex
+	config   Config
+	entities map[string]*entityScore
+	types    map[EntityType]bool
+	fn       ThresholdFunc
+	quit     chan struct{}
+}
+
+// NewScorer creates a new risk scorer. The threshold function is
+// called each time the entity risk score crosses the threshold.
+func NewScorer(config Config, fn ThresholdFunc) *Scorer {
+	s := &Scorer{
+		config:   config,
+		entities: make(map[string]*entityScore),
+		types:    make(map[EntityType]bool),
+		fn:       fn,
+		quit:     make(chan struct{}, 1),
+	}
+	for _, typ := range config.Entities {
+		s.types[EntityType(typ)] = true
+	}
+	go s.gc()
+	return s
+}
+
+// Add contributes the risk score of the rule to the given entities.
+func (s *Scorer) Add(ruleID, ruleName string, score float64, entities ...Entity) {
+	s.add(time.Now(), ruleID, ruleName, score, entities...)
+}
+
+func (s *Scorer) add(now time.Time, ruleID, ruleName string, score float64, entities ...Entity) {
+	exceeded := make([]Score, 0)
+
+	s.mu.Lock()
+	for _, ent := range entities {
+		if !s.types[ent.Type] || ent.ID == "" {
+			continue
+		}
+		key := ent.Key()
+		e, ok := s.entities[key]
+		if !ok {
+			e = &entityScore{entity: ent, contributions: make(map[string]*Contribution)}
+			s.entities[key] = e
+		}
+		s.decay(e, now)
+		e.score += score
+		e.updated = now
+
+		c, ok := e.contributions[ruleID]
+		if !ok {
+			c = &Contribution{RuleID: ruleID, RuleName: ruleName}
+			e.contributions[ruleID] = c
+		}
+		c.Matches++
+		c.Score += score
+		c.LastSeen = now
+
+		if !e.exceeded && e.score >= float64(s.config.Threshold) {
+			e.exceeded = true
+			thresholdExceeded.Add(1)
+			exceeded = append(exceeded, e.snapshot())
+		}
+		scoresGauge.Set(key, score2Var(e.score))
+	}
+	s.mu.Unlock()
+
+	if s.fn == nil {
+		return
+	}
+	for _, sc := range exceeded {
+		s.fn(sc)
+	}
+}
+
+// Scores returns the snapshot of all entity risk
+// scores sorted in descending order by score.
+func (s *Scorer) Scores() []Score {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	scores := make([]Score, 0, len(s.entities))
+	for _, e := range s.entities {
+		s.decay(e, now)
+		scores = append(scores, e.snapshot())
+	}
+	slices.SortFunc(scores, func(a, b Score) int {
+		return cmpDesc(a.Score, b.Score)
+	})
+	return scores
+}
+
+// Close stops the scorer.
+func (s *Scorer) Close() {
+	s.quit <- struct{}{}
+}
+
+// decay applies the exponential decay to the entity score
+// based on the time elapsed since the last update. Once the
+// score drops below the threshold, the entity can fire the
+// threshold exceeded alert again.
+func (s *Scorer) decay(e *entityScore, now time.Time) {
+	if s.config.HalfLife > 0 && !e.updated.IsZero() {
+		elapsed := now.Sub(e.updated)
+		if elapsed > 0 {
+			f := math.Pow(0.5, float64(elapsed)/float64(s.config.HalfLife))
+			e.score *= f
+			for _, c := range e.contributions {
+				c.Score *= f
+			}
+			e.updated = now
+		}
+	}
+	if e.exceeded && e.score < float64(s.config.Threshold) {
+		e.exceeded = false
+	}
+}
+
+func (s *Scorer) gc() {
+	tick := time.NewTicker(gcInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case now := <-tick.C:
+			s.evict(now)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// evict removes entities whose risk score decayed below the minimum score.
+func (s *Scorer) evict(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, e := range s.entities {
+		s.decay(e, now)
+		if e.score < minScore {
+			delete(s.entities, key)
+			scoresGauge.Delete(key)
+			continue
+		}
+		scoresGauge.Set(key, score2Var(e.score))
+	}
+}
+
+func score2Var(score float64) *expvar.Float {
+	v := new(expvar.Float)
+	v.Set(math.Round(score*100) / 100)
+	return v
+}
+
+func cmpDesc(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String returns the score formatted with two decimals.
+func (s Score) String() string { return strconv.FormatFloat(s.Score, 'f', 2, 64) }
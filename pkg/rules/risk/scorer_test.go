@@ -0,0 +1,78 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScorer(t *testing.T) {
+	var exceeded []Score
+	s := NewScorer(Config{
+		Threshold: 100,
+		HalfLife:  time.Hour,
+		Entities:  []string{string(Process), string(User)},
+	}, func(sc Score) { exceeded = append(exceeded, sc) })
+	defer s.Close()
+
+	proc := Entity{Type: Process, ID: "1234", Name: `C:\Windows\System32\rundll32.exe`}
+	user := Entity{Type: User, ID: "S-1-5-21-1", Name: `ARCHRABBIT\admin`}
+	host := Entity{Type: Host, ID: "archrabbit"}
+
+	now := time.Now()
+	s.add(now, "rule-1", "Suspicious DLL loaded", 40, proc, user, host)
+	s.add(now, "rule-2", "LSASS memory access", 50, proc)
+	assert.Empty(t, exceeded)
+
+	scores := s.Scores()
+	require.Len(t, scores, 2)
+	assert.Equal(t, proc, scores[0].Entity)
+	assert.InDelta(t, 90, scores[0].Score, 0.1)
+	require.Len(t, scores[0].Contributions, 2)
+	assert.Equal(t, "rule-2", scores[0].Contributions[0].RuleID)
+
+	// exceeds the threshold only once
+	s.add(now, "rule-1", "Suspicious DLL loaded", 40, proc)
+	s.add(now, "rule-1", "Suspicious DLL loaded", 40, proc)
+	require.Len(t, exceeded, 1)
+	assert.Equal(t, proc, exceeded[0].Entity)
+	assert.True(t, exceeded[0].Exceeded)
+	assert.Len(t, exceeded[0].Contributions, 2)
+	assert.Equal(t, 2, exceeded[0].Contributions[0].Matches)
+
+	// after two half-lives the score decays to a quarter
+	// and the entity can exceed the threshold again
+	later := now.Add(2 * time.Hour)
+	s.evict(later)
+	s.mu.Lock()
+	assert.InDelta(t, 42.5, s.entities[proc.Key()].score, 0.1)
+	assert.False(t, s.entities[proc.Key()].exceeded)
+	s.mu.Unlock()
+
+	s.add(later, "rule-2", "LSASS memory access", 60, proc)
+	require.Len(t, exceeded, 2)
+
+	// decayed entities are evicted
+	s.evict(later.Add(24 * time.Hour))
+	assert.Empty(t, s.Scores())
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rules
+
+import (
+	"testing"
+
+	"github.com/rabbitstack/fibratus/pkg/config"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/ps"
+	"github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/rabbitstack/fibratus/pkg/rules/risk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskEntities(t *testing.T) {
+	proc := &types.PS{PID: 1234, Exe: `C:\Windows\System32\rundll32.exe`, SID: "S-1-5-18", Username: "SYSTEM", Domain: "NT AUTHORITY"}
+	evts := []*event.Event{
+		{Type: event.CreateProcess, PID: 1234, PS: proc},
+		{Type: event.LoadModule, PID: 1234, PS: proc},
+		{Type: event.CreateFile, PID: 4},
+	}
+
+	entities := riskEntities(evts)
+	require.Len(t, entities, 3)
+	assert.Equal(t, risk.Process, entities[0].Type)
+	assert.Equal(t, `C:\Windows\System32\rundll32.exe`, entities[0].Name)
+	assert.Equal(t, risk.Entity{Type: risk.User, ID: "S-1-5-18", Name: `NT AUTHORITY\SYSTEM`}, entities[1])
+	assert.Equal(t, risk.Host, entities[2].Type)
+}
+
+func TestIsRiskOnly(t *testing.T) {
+	c := newConfig()
+	c.Risk = risk.Config{Enabled: true, Threshold: 100, AlertMinSeverity: "high"}
+	e := NewEngine(new(ps.SnapshotterMock), c)
+	require.NotNil(t, e.RiskScorer())
+
+	assert.True(t, e.isRiskOnly(&config.FilterConfig{Risk: 20, Severity: "low"}))
+	assert.False(t, e.isRiskOnly(&config.FilterConfig{Risk: 20, Severity: "critical"}))
+	assert.False(t, e.isRiskOnly(&config.FilterConfig{Severity: "low"}))
+}
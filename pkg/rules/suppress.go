@@ -0,0 +1,167 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rules
+
+import (
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/config"
+	"github.com/rabbitstack/fibratus/pkg/filter"
+)
+
+var (
+	// suppressInterval determines how often expired suppression windows are flushed
+	suppressInterval = time.Second * 5
+
+	// suppressedMatches counts the number of suppressed rule matches per rule
+	suppressedMatches = expvar.NewMap("filter.suppressed.matches")
+)
+
+// suppressWindow tracks duplicate matches of the
+// rule for the duration of the suppression window.
+type suppressWindow struct {
+	ctx     *config.ActionContext
+	count   int
+	expires time.Time
+}
+
+// suppressor deduplicates rule matches. The first
+// match opens the suppression window and emits the
+// alert. Subsequent matches with the same values of
+// suppression fields only increment the counter. When
+// the window closes, the summary alert with the number
+// of suppressed matches is sent.
+type suppressor struct {
+	mu      sync.Mutex
+	windows map[string]*suppressWindow
+	alert   func(*config.ActionContext, int)
+	quit    chan struct{}
+	once    sync.Once
+}
+
+func newSuppressor(alert func(*config.ActionContext, int)) *suppressor {
+	return &suppressor{windows: make(map[string]*suppressWindow), alert: alert, quit: make(chan struct{})}
+}
+
+// suppress determines if the alert for the given rule match should be
+// suppressed. If this is the first match within the suppression window,
+// the window is opened and false is returned. If the previous window
+// expired before it was flushed, its summary alert is sent first.
+func (s *suppressor) suppress(ctx *config.ActionContext) bool {
+	f := ctx.Filter
+	if !f.IsSuppressed() {
+		return false
+	}
+	key := suppressKey(ctx)
+	now := time.Now()
+
+	s.mu.Lock()
+	w, ok := s.windows[key]
+	if ok && now.Before(w.expires) {
+		w.count++
+		s.mu.Unlock()
+		suppressedMatches.Add(f.Name, 1)
+		return true
+	}
+	s.windows[key] = &suppressWindow{ctx: ctx, expires: now.Add(f.Suppress.For)}
+	s.mu.Unlock()
+
+	if ok && w.count > 0 {
+		s.alert(w.ctx, w.count)
+	}
+	return false
+}
+
+// flush closes all expired suppression windows and sends
+// the summary alert for windows with suppressed matches.
+func (s *suppressor) flush(now time.Time) {
+	s.close(func(w *suppressWindow) bool { return !now.Before(w.expires) })
+}
+
+// close closes suppression windows satisfying the predicate
+// and sends the summary alert for windows with suppressed
+// matches.
+func (s *suppressor) close(pred func(*suppressWindow) bool) {
+	s.mu.Lock()
+	expired := make([]*suppressWindow, 0)
+	for key, w := range s.windows {
+		if !pred(w) {
+			continue
+		}
+		delete(s.windows, key)
+		if w.count > 0 {
+			expired = append(expired, w)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, w := range expired {
+		s.alert(w.ctx, w.count)
+	}
+}
+
+func (s *suppressor) run() {
+	tick := time.NewTicker(suppressInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case now := <-tick.C:
+			s.flush(now)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// stop stops flushing expired windows and closes all
+// open windows, so the summary alerts of pending
+// suppressed matches are sent before shutdown.
+func (s *suppressor) stop() {
+	s.once.Do(func() { close(s.quit) })
+	s.close(func(*suppressWindow) bool { return true })
+}
+
+// suppressKey builds the key that identifies duplicate
+// matches from the rule id and suppression field values
+// extracted from the first event of the match.
+func suppressKey(ctx *config.ActionContext) string {
+	f := ctx.Filter
+	var b strings.Builder
+	b.WriteString(f.ID)
+	for _, name := range f.Suppress.By {
+		b.WriteByte('|')
+		if len(ctx.Events) > 0 {
+			b.WriteString(fmt.Sprintf("%v", filter.GetFieldValue(name, ctx.Events[0])))
+		}
+	}
+	return b.String()
+}
+
+// summaryAlert sends the alert with the number of
+// matches suppressed within the suppression window.
+func (e *Engine) summaryAlert(ctx *config.ActionContext, count int) {
+	f := ctx.Filter
+	text := fmt.Sprintf("%s\n\n%d duplicate alert(s) suppressed in the last %v",
+		filter.InterpolateFields(f.Output, ctx.Events), count, f.Suppress.For)
+	e.alert(ctx, f.Name, text, f.Severity, f.Tags)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rules
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rabbitstack/fibratus/pkg/config"
+	"github.com/rabbitstack/fibratus/pkg/event"
+	"github.com/rabbitstack/fibratus/pkg/event/params"
+	"github.com/rabbitstack/fibratus/pkg/ps/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressor(t *testing.T) {
+	summaries := make(map[string]int)
+	s := newSuppressor(func(ctx *config.ActionContext, count int) {
+		summaries[ctx.Events[0].PS.Exe] = count
+	})
+
+	f := &config.FilterConfig{
+		ID:   "5b3c5d6e-7f80-4a91-b2c3-d4e5f6a7b8c9",
+		Name: "outbound connection",
+		Suppress: &config.SuppressConfig{
+			By:  []string{"ps.exe", "net.dip"},
+			For: time.Minute,
+		},
+	}
+
+	newMatch := func(exe string, dip string) *config.ActionContext {
+		evt := &event.Event{
+			Type:     event.ConnectTCPv4,
+			Category: event.Net,
+			PID:      859,
+			Params: event.Params{
+				params.NetDIP: {Name: params.NetDIP, Type: params.IPv4, Value: net.ParseIP(dip)},
+			},
+			PS: &types.PS{PID: 859, Exe: exe},
+		}
+		return &config.ActionContext{Events: []*event.Event{evt}, Filter: f}
+	}
+
+	assert.False(t, s.suppress(newMatch(`C:\Windows\System32\svchost.exe`, "216.58.201.174")))
+	assert.True(t, s.suppress(newMatch(`C:\Windows\System32\svchost.exe`, "216.58.201.174")))
+	assert.True(t, s.suppress(newMatch(`C:\Windows\System32\svchost.exe`, "216.58.201.174")))
+	// different destination address opens a new window
+	assert.False(t, s.suppress(newMatch(`C:\Windows\System32\svchost.exe`, "172.217.16.14")))
+	assert.False(t, s.suppress(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174")))
+	assert.True(t, s.suppress(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174")))
+
+	require.Len(t, s.windows, 3)
+
+	// windows are still open
+	s.flush(time.Now())
+	assert.Empty(t, summaries)
+
+	s.flush(time.Now().Add(time.Minute * 2))
+	assert.Empty(t, s.windows)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, 2, summaries[`C:\Windows\System32\svchost.exe`])
+	assert.Equal(t, 1, summaries[`C:\Windows\notepad.exe`])
+
+	// the window is opened again after it is closed
+	assert.False(t, s.suppress(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174")))
+
+	// the summary of the expired window is sent when the window is reopened
+	s.windows[suppressKey(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174"))].expires = time.Now().Add(-time.Second)
+	s.windows[suppressKey(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174"))].count = 3
+	assert.False(t, s.suppress(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174")))
+	assert.Equal(t, 3, summaries[`C:\Windows\notepad.exe`])
+
+	// open windows are closed on shutdown
+	assert.True(t, s.suppress(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174")))
+	go s.run()
+	s.stop()
+	assert.Empty(t, s.windows)
+	assert.Equal(t, 1, summaries[`C:\Windows\notepad.exe`])
+
+	// rules without the suppress block are never suppressed
+	f.Suppress = nil
+	assert.False(t, s.suppress(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174")))
+	assert.False(t, s.suppress(newMatch(`C:\Windows\notepad.exe`, "216.58.201.174")))
+}
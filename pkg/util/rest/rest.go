@@ -21,6 +21,7 @@ package rest
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/rabbitstack/fibratus/pkg/api"
 	"io"
 	"net"
@@ -32,11 +33,21 @@ import (
 
 var transport *http.Transport
 
+// StatusError is returned when the server responds with the error status code.
+type StatusError struct {
+	Code   int
+	Status string
+	Body   string
+}
+
+func (e *StatusError) Error() string { return fmt.Sprintf("%s: %s", e.Status, e.Body) }
+
 type opts struct {
 	addr        string
 	uri         string
 	contentType string
 	timeout     time.Duration
+	statusError bool
 }
 
 // Option represents the option for the HTTP client.
@@ -72,11 +83,25 @@ func WithContentType(contentType string) Option {
 	}
 }
 
+// WithStatusError makes the request fail with the StatusError if the
+// server responds with the error status code. By default, the response
+// body is returned regardless of the status code.
+func WithStatusError() Option {
+	return func(o *opts) {
+		o.statusError = true
+	}
+}
+
 // Get performs the GET request.
 func Get(opts ...Option) ([]byte, error) {
 	return request("GET", opts...)
 }
 
+// Post performs the POST request.
+func Post(opts ...Option) ([]byte, error) {
+	return request("POST", opts...)
+}
+
 func request(method string, options ...Option) ([]byte, error) {
 	var opts opts
 	for _, opt := range options {
@@ -122,5 +147,8 @@ func request(method string, options ...Option) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if opts.statusError && resp.StatusCode >= http.StatusBadRequest {
+		return nil, &StatusError{Code: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(body))}
+	}
 	return body, nil
 }
@@ -47,6 +47,37 @@ func TestGet(t *testing.T) {
 	assert.Equal(t, "test", string(resp))
 }
 
+func TestPost(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /alerts/{id}/ack", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "1" {
+			http.Error(w, "alert not found", http.StatusNotFound)
+			return
+		}
+		if _, err := w.Write([]byte("acked")); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := Post(WithURI("alerts/1/ack"), WithTransport(fmt.Sprintf("localhost:%s", port(srv.URL))))
+	require.NoError(t, err)
+	assert.Equal(t, "acked", string(resp))
+
+	// the body is returned regardless of the status code by default
+	resp, err = Post(WithURI("alerts/2/ack"), WithTransport(fmt.Sprintf("localhost:%s", port(srv.URL))))
+	require.NoError(t, err)
+	assert.Equal(t, "alert not found\n", string(resp))
+
+	_, err = Post(WithURI("alerts/2/ack"), WithTransport(fmt.Sprintf("localhost:%s", port(srv.URL))), WithStatusError())
+	require.EqualError(t, err, "404 Not Found: alert not found")
+	var serr *StatusError
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, http.StatusNotFound, serr.Code)
+}
+
 func TestGetPipe(t *testing.T) {
 	usr, err := user.Current()
 	require.NoError(t, err)
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Field is the key/value pair of the CEF extension or LEEF event attribute.
+type Field struct {
+	Key   string
+	Value string
+}
+
+var (
+	cefHeaderEscaper  = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtEscaper     = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+	leefHeaderEscaper = strings.NewReplacer(`|`, `\|`, "\r", " ", "\n", " ")
+	leefAttrEscaper   = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+)
+
+// CEF builds the Common Event Format record:
+//
+//	CEF:0|Vendor|Product|Version|SignatureID|Name|Severity|Extension
+//
+// Severity ranges from 0 to 10. Extension fields with empty values are omitted.
+func CEF(vendor, product, version, signatureID, name string, severity int, ext []Field) string {
+	var b strings.Builder
+	b.WriteString("CEF:0|")
+	for _, h := range []string{vendor, product, version, signatureID, name} {
+		b.WriteString(cefHeaderEscaper.Replace(h))
+		b.WriteByte('|')
+	}
+	b.WriteString(strconv.Itoa(min(max(severity, 0), 10)))
+	b.WriteByte('|')
+	n := 0
+	for _, f := range ext {
+		if f.Value == "" {
+			continue
+		}
+		if n > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(cefExtEscaper.Replace(f.Value))
+		n++
+	}
+	return b.String()
+}
+
+// LEEF builds the Log Event Extended Format 1.0 record with
+// tab-delimited event attributes:
+//
+//	LEEF:1.0|Vendor|Product|Version|EventID|key=value<tab>key=value
+//
+// Attributes with empty values are omitted.
+func LEEF(vendor, product, version, eventID string, attrs []Field) string {
+	var b strings.Builder
+	b.WriteString("LEEF:1.0|")
+	for _, h := range []string{vendor, product, version, eventID} {
+		b.WriteString(leefHeaderEscaper.Replace(h))
+		b.WriteByte('|')
+	}
+	n := 0
+	for _, f := range attrs {
+		if f.Value == "" {
+			continue
+		}
+		if n > 0 {
+			b.WriteByte('\t')
+		}
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(leefAttrEscaper.Replace(f.Value))
+		n++
+	}
+	return b.String()
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package syslog implements the syslog message encoding as specified
+// in RFC 5424 and RFC 3164, along with the CEF and LEEF payload formats
+// and the UDP, TCP, and TLS transports.
+package syslog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Facility is the syslog facility code.
+type Facility uint8
+
+// Severity is the syslog severity level.
+type Severity uint8
+
+const (
+	// Emergency indicates the system is unusable
+	Emergency Severity = iota
+	// Alert indicates the action must be taken immediately
+	Alert
+	// Critical indicates critical conditions
+	Critical
+	// Error indicates error conditions
+	Error
+	// Warning indicates warning conditions
+	Warning
+	// Notice indicates normal but significant conditions
+	Notice
+	// Informational indicates informational messages
+	Informational
+	// Debug indicates debug-level messages
+	Debug
+)
+
+var facilities = map[string]Facility{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"ntp":      12,
+	"security": 13,
+	"console":  14,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// ParseFacility parses the facility from its keyword, e.g. local0.
+func ParseFacility(s string) (Facility, error) {
+	f, ok := facilities[strings.ToLower(s)]
+	if !ok {
+		return 0, fmt.Errorf("unknown syslog facility %q", s)
+	}
+	return f, nil
+}
+
+// Format is the syslog message format.
+type Format uint8
+
+const (
+	// RFC5424 is the syslog protocol message format.
+	RFC5424 Format = iota
+	// RFC3164 is the legacy BSD syslog message format.
+	RFC3164
+)
+
+// ParseFormat parses the syslog message format.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "rfc5424", "":
+		return RFC5424, nil
+	case "rfc3164":
+		return RFC3164, nil
+	default:
+		return 0, fmt.Errorf("unknown syslog format %q", s)
+	}
+}
+
+// SDParam is the structured data parameter.
+type SDParam struct {
+	Name  string
+	Value string
+}
+
+// SDElement is the structured data element identified by the SD-ID.
+type SDElement struct {
+	ID     string
+	Params []SDParam
+}
+
+// Message represents the syslog message.
+type Message struct {
+	Facility  Facility
+	Severity  Severity
+	Timestamp time.Time
+	Hostname  string
+	AppName   string
+	ProcID    string
+	MsgID     string
+	// StructuredData is only encoded in RFC 5424 messages.
+	StructuredData []SDElement
+	Msg            string
+}
+
+// pri returns the message priority value.
+func (m Message) pri() int { return int(m.Facility)*8 + int(m.Severity) }
+
+// Encode encodes the message in the given format.
+func (m Message) Encode(f Format) []byte {
+	if f == RFC3164 {
+		return m.encodeRFC3164()
+	}
+	return m.encodeRFC5424()
+}
+
+// encodeRFC5424 encodes the message as per RFC 5424:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (m Message) encodeRFC5424() []byte {
+	var b strings.Builder
+	b.WriteByte('<')
+	b.WriteString(strconv.Itoa(m.pri()))
+	b.WriteString(">1 ")
+	if m.Timestamp.IsZero() {
+		b.WriteByte('-')
+	} else {
+		b.WriteString(m.Timestamp.Format("2006-01-02T15:04:05.000000Z07:00"))
+	}
+	b.WriteByte(' ')
+	b.WriteString(headerField(m.Hostname, 255))
+	b.WriteByte(' ')
+	b.WriteString(headerField(m.AppName, 48))
+	b.WriteByte(' ')
+	b.WriteString(headerField(m.ProcID, 128))
+	b.WriteByte(' ')
+	b.WriteString(headerField(m.MsgID, 32))
+	b.WriteByte(' ')
+	if len(m.StructuredData) == 0 {
+		b.WriteByte('-')
+	}
+	for _, e := range m.StructuredData {
+		b.WriteByte('[')
+		b.WriteString(sdName(e.ID))
+		for _, p := range e.Params {
+			b.WriteByte(' ')
+			b.WriteString(sdName(p.Name))
+			b.WriteString(`="`)
+			b.WriteString(sdValueEscaper.Replace(p.Value))
+			b.WriteByte('"')
+		}
+		b.WriteByte(']')
+	}
+	if m.Msg != "" {
+		b.WriteByte(' ')
+		b.WriteString(m.Msg)
+	}
+	return []byte(b.String())
+}
+
+// encodeRFC3164 encodes the message as per RFC 3164:
+//
+//	<PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+func (m Message) encodeRFC3164() []byte {
+	var b strings.Builder
+	b.WriteByte('<')
+	b.WriteString(strconv.Itoa(m.pri()))
+	b.WriteByte('>')
+	ts := m.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	b.WriteString(ts.Format(time.Stamp))
+	b.WriteByte(' ')
+	b.WriteString(headerField(m.Hostname, 255))
+	b.WriteByte(' ')
+	tag := m.AppName
+	if tag == "" {
+		tag = "-"
+	}
+	b.WriteString(headerField(tag, 32))
+	if m.ProcID != "" {
+		b.WriteByte('[')
+		b.WriteString(headerField(m.ProcID, 128))
+		b.WriteByte(']')
+	}
+	b.WriteString(": ")
+	b.WriteString(m.Msg)
+	return []byte(b.String())
+}
+
+// headerField sanitizes the header field value. Only printable
+// US-ASCII characters without spaces are permitted. Empty values
+// are replaced with the NILVALUE.
+func headerField(s string, maxLen int) string {
+	if s == "" {
+		return "-"
+	}
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > maxLen {
+		return s[:maxLen]
+	}
+	return s
+}
+
+// sdName sanitizes the SD-ID and PARAM-NAME values.
+func sdName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > 32 {
+		return s[:32]
+	}
+	return s
+}
+
+// sdValueEscaper escapes the characters that must be escaped in PARAM-VALUE.
+var sdValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
@@ -0,0 +1,182 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeRFC5424(t *testing.T) {
+	m := Message{
+		Facility:  16,
+		Severity:  Notice,
+		Timestamp: time.Date(2025, 3, 14, 10, 11, 12, 345678000, time.UTC),
+		Hostname:  "archrabbit",
+		AppName:   "fibratus",
+		ProcID:    "4024",
+		MsgID:     "CreateProcess",
+		StructuredData: []SDElement{
+			{ID: "ps@32473", Params: []SDParam{{Name: "exe", Value: `C:\Windows\cmd.exe`}, {Name: "cmdline", Value: `cmd.exe /c "echo [x]"`}}},
+		},
+		Msg: "spawned",
+	}
+	assert.Equal(t,
+		`<133>1 2025-03-14T10:11:12.345678Z archrabbit fibratus 4024 CreateProcess [ps@32473 exe="C:\\Windows\\cmd.exe" cmdline="cmd.exe /c \"echo [x\]\""] spawned`,
+		string(m.Encode(RFC5424)))
+
+	m = Message{Facility: 1, Severity: Informational, Msg: "message"}
+	assert.Equal(t, `<14>1 - - - - - - message`, string(m.Encode(RFC5424)))
+
+	m = Message{Facility: 1, Severity: Informational, Hostname: "arch rabbit", MsgID: strings.Repeat("A", 40)}
+	assert.Equal(t, `<14>1 - arch_rabbit - - `+strings.Repeat("A", 32)+` -`, string(m.Encode(RFC5424)))
+}
+
+func TestEncodeRFC3164(t *testing.T) {
+	m := Message{
+		Facility:  4,
+		Severity:  Critical,
+		Timestamp: time.Date(2025, 3, 4, 10, 11, 12, 0, time.UTC),
+		Hostname:  "archrabbit",
+		AppName:   "fibratus",
+		ProcID:    "4024",
+		StructuredData: []SDElement{
+			{ID: "ps@32473", Params: []SDParam{{Name: "exe", Value: `C:\Windows\cmd.exe`}}},
+		},
+		Msg: "LSASS memory dumping",
+	}
+	assert.Equal(t, `<34>Mar  4 10:11:12 archrabbit fibratus[4024]: LSASS memory dumping`, string(m.Encode(RFC3164)))
+}
+
+func TestParse(t *testing.T) {
+	f, err := ParseFacility("LOCAL4")
+	require.NoError(t, err)
+	assert.Equal(t, Facility(20), f)
+	_, err = ParseFacility("local8")
+	require.Error(t, err)
+
+	format, err := ParseFormat("rfc3164")
+	require.NoError(t, err)
+	assert.Equal(t, RFC3164, format)
+	_, err = ParseFormat("rfc822")
+	require.Error(t, err)
+
+	framing, err := ParseFraming("non-transparent")
+	require.NoError(t, err)
+	assert.Equal(t, NonTransparent, framing)
+}
+
+func TestCEF(t *testing.T) {
+	assert.Equal(t,
+		`CEF:0|Fibratus|Fibratus|2.0.0|Create\|Process|CreateProcess|10|msg=a\=b\\c\nd sproc=C:\\cmd.exe`,
+		CEF("Fibratus", "Fibratus", "2.0.0", "Create|Process", "CreateProcess", 12, []Field{
+			{Key: "msg", Value: "a=b\\c\nd"},
+			{Key: "suser", Value: ""},
+			{Key: "sproc", Value: `C:\cmd.exe`},
+		}))
+}
+
+func TestLEEF(t *testing.T) {
+	assert.Equal(t,
+		"LEEF:1.0|Fibratus|Fibratus|2.0.0|CreateProcess|src=10.0.0.1\tcmdline=cmd.exe /c  dir",
+		LEEF("Fibratus", "Fibratus", "2.0.0", "CreateProcess", []Field{
+			{Key: "src", Value: "10.0.0.1"},
+			{Key: "usrName", Value: ""},
+			{Key: "cmdline", Value: "cmd.exe /c\t\ndir"},
+		}))
+}
+
+func TestWriterTCP(t *testing.T) {
+	var tests = []struct {
+		framing  Framing
+		expected string
+	}{
+		{OctetCounting, "7 <14>1 a7 <14>1 b"},
+		{NonTransparent, "<14>1 a\n<14>1 b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.framing)), func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer l.Close()
+
+			data := make(chan string, 1)
+			go func() {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				buf := make([]byte, len(tt.expected))
+				_, _ = io.ReadFull(conn, buf)
+				data <- string(buf)
+			}()
+
+			w, err := NewWriter("tcp", l.Addr().String(), nil, tt.framing, time.Second)
+			require.NoError(t, err)
+			defer w.Close()
+
+			require.ErrorIs(t, w.Write([]byte("<14>1 a")), ErrNotConnected)
+			require.NoError(t, w.Connect())
+			require.NoError(t, w.Write([]byte("<14>1 a"), []byte("<14>1 b")))
+
+			select {
+			case s := <-data:
+				assert.Equal(t, tt.expected, s)
+			case <-time.After(time.Second * 5):
+				t.Fatal("timed out waiting for syslog message")
+			}
+		})
+	}
+}
+
+func TestWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w, err := NewWriter("udp", conn.LocalAddr().String(), nil, OctetCounting, time.Second)
+	require.NoError(t, err)
+	defer w.Close()
+	require.NoError(t, w.Connect())
+	require.NoError(t, w.Write([]byte("<14>1 a"), []byte("<14>1 b")))
+
+	buf := make([]byte, 1024)
+	for _, expected := range []string{"<14>1 a", "<14>1 b"} {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(buf[:n]))
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	_, err := NewWriter("unix", "localhost:514", nil, OctetCounting, time.Second)
+	require.Error(t, err)
+	_, err = NewWriter("tcp", "localhost", nil, OctetCounting, time.Second)
+	require.Error(t, err)
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2021-present by Nedim Sabic Sabic
+ * https://www.fibratus.io
+ * All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package syslog
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotConnected signals the writer is not connected to the syslog server
+var ErrNotConnected = errors.New("not connected to syslog server")
+
+// Framing determines how messages are delimited on stream transports.
+type Framing uint8
+
+const (
+	// OctetCounting prefixes each message with its length as per RFC 6587.
+	OctetCounting Framing = iota
+	// NonTransparent terminates each message with the LF character.
+	NonTransparent
+)
+
+// ParseFraming parses the stream transport framing method.
+func ParseFraming(s string) (Framing, error) {
+	switch strings.ToLower(s) {
+	case "octet-counting", "":
+		return OctetCounting, nil
+	case "non-transparent":
+		return NonTransparent, nil
+	default:
+		return 0, fmt.Errorf("unknown syslog framing %q", s)
+	}
+}
+
+// Writer delivers syslog messages to the remote server over UDP, TCP, or TLS transports.
+type Writer struct {
+	network   string
+	addr      string
+	tlsConfig *tls.Config
+	framing   Framing
+	timeout   time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewWriter creates a new syslog writer. The network is one of udp, tcp, or tls.
+// If the TLS configuration is nil, the default configuration is used for the tls
+// network.
+func NewWriter(network, addr string, tlsConfig *tls.Config, framing Framing, timeout time.Duration) (*Writer, error) {
+	switch network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", network)
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid syslog server address %q: %v", addr, err)
+	}
+	if network == "tls" && tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	return &Writer{network: network, addr: addr, tlsConfig: tlsConfig, framing: framing, timeout: timeout}, nil
+}
+
+// Connect establishes the connection to the syslog
+// server. The existing connection is closed.
+func (w *Writer) Connect() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	dialer := &net.Dialer{Timeout: w.timeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if w.network == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", w.addr, w.tlsConfig)
+	} else {
+		conn, err = dialer.Dial(w.network, w.addr)
+	}
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// IsStream indicates if the writer uses the stream transport.
+func (w *Writer) IsStream() bool { return w.network != "udp" }
+
+// Write sends the syslog messages. On stream transports, messages
+// are framed according to the framing method. If the write fails,
+// the connection is dropped and the writer must be reconnected
+// before sending further messages.
+func (w *Writer) Write(msgs ...[]byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return ErrNotConnected
+	}
+	if w.timeout > 0 {
+		_ = w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	}
+	if !w.IsStream() {
+		// each datagram carries exactly one message
+		for _, msg := range msgs {
+			if _, err := w.conn.Write(msg); err != nil {
+				return w.drop(err)
+			}
+		}
+		return nil
+	}
+	buf := make([]byte, 0, 512*len(msgs))
+	for _, msg := range msgs {
+		buf = w.frame(buf, msg)
+	}
+	if _, err := w.conn.Write(buf); err != nil {
+		return w.drop(err)
+	}
+	return nil
+}
+
+func (w *Writer) frame(buf, msg []byte) []byte {
+	if w.framing == NonTransparent {
+		buf = append(buf, msg...)
+		return append(buf, '\n')
+	}
+	buf = strconv.AppendInt(buf, int64(len(msg)), 10)
+	buf = append(buf, ' ')
+	return append(buf, msg...)
+}
+
+func (w *Writer) drop(err error) error {
+	_ = w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// Close closes the connection to the syslog server.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}